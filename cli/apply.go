@@ -0,0 +1,50 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/saat-sy/hyprlander/pkg/core/tools"
+	"github.com/saat-sy/hyprlander/pkg/hyprlang"
+	"github.com/saat-sy/hyprlander/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+// applyChanges shows the diff of every change, asks for confirmation unless
+// --yes is set, snapshots the files and writes them. With dryRun it stops
+// after the diffs.
+func applyChanges(cmd *cobra.Command, reason string, changes []hyprlang.FileChange, dryRun bool) error {
+	s, err := loadSettings(cmd)
+	if err != nil {
+		return err
+	}
+
+	userUI := ui.New()
+	for _, change := range changes {
+		userUI.PrintWriteTool(map[string]interface{}{"path": change.Path, "content": change.Content})
+	}
+	if dryRun {
+		return nil
+	}
+
+	if !s.AssumeYes {
+		confirmed, err := userUI.Confirm(fmt.Sprintf("Write %d file(s)?", len(changes)))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			userUI.Print("Nothing was written.")
+			return nil
+		}
+	}
+
+	snap, err := tools.WriteChanges(reason, changes)
+	if err != nil {
+		if snap != nil {
+			return fmt.Errorf("%w; restore the previous files with 'hyprlander snapshot restore %s'", err, snap.ID)
+		}
+		return err
+	}
+
+	userUI.PrintSuccess(fmt.Sprintf("Wrote %d file(s). Undo with 'hyprlander snapshot restore %s'", len(changes), snap.ID))
+	return nil
+}
@@ -0,0 +1,146 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/saat-sy/hyprlander/pkg/audit"
+	"github.com/saat-sy/hyprlander/pkg/config"
+	"github.com/saat-sy/hyprlander/pkg/policy"
+	"github.com/saat-sy/hyprlander/pkg/secrets"
+	"github.com/saat-sy/hyprlander/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+func AuditCommand() *cobra.Command {
+	auditCommand := &cobra.Command{
+		Use:   "audit",
+		Short: "Inspect the log of tools the agent ran",
+		Long:  "Inspect the hash-chained log of every tool the agent ran, kept in ~/.hyprlander/audit",
+	}
+
+	showCommand := &cobra.Command{
+		Use:   "show",
+		Short: "Print the most recent audit entries",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := config.GetAuditFilePath()
+			if err != nil {
+				return fmt.Errorf("could not determine audit log path: %w", err)
+			}
+
+			records, err := audit.Read(path)
+			if err != nil {
+				return err
+			}
+
+			limit, _ := cmd.Flags().GetInt("limit")
+			if limit > 0 && len(records) > limit {
+				records = records[len(records)-limit:]
+			}
+
+			if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+				encoder := json.NewEncoder(cmd.OutOrStdout())
+				for _, record := range records {
+					if err := encoder.Encode(struct {
+						audit.Entry
+						Hash string `json:"hash"`
+					}{record.Entry, record.Hash}); err != nil {
+						return err
+					}
+				}
+				return nil
+			}
+
+			userUI := ui.New()
+			if len(records) == 0 {
+				userUI.Print("The audit log is empty.")
+				return nil
+			}
+
+			userUI.PrintTitle(path)
+			for _, record := range records {
+				entry := record.Entry
+				userUI.Print(fmt.Sprintf("#%d %s %s@%s [%s] %s %s",
+					entry.Seq,
+					entry.Started.Local().Format("2006-01-02 15:04:05"),
+					entry.User, entry.Host, entry.Profile,
+					entry.Tool, policy.Subject(entry.Tool, entry.Args)))
+
+				status := fmt.Sprintf("    %s by %s", entry.Decision, entry.ApprovedBy)
+				if entry.Error != "" {
+					status += ", failed: " + entry.Error
+				}
+				userUI.Print(status)
+
+				for _, file := range entry.Files {
+					if file.Before != file.After {
+						userUI.Print(fmt.Sprintf("    %s %s -> %s", file.Path, shortHash(file.Before), shortHash(file.After)))
+					}
+				}
+			}
+			return nil
+		},
+	}
+	showCommand.Flags().IntP("limit", "n", 20, "number of entries to show, 0 for all")
+	showCommand.Flags().Bool("json", false, "print entries as JSON lines")
+	auditCommand.AddCommand(showCommand)
+
+	auditCommand.AddCommand(&cobra.Command{
+		Use:   "verify",
+		Short: "Check that the audit log has not been changed",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := config.GetAuditFilePath()
+			if err != nil {
+				return fmt.Errorf("could not determine audit log path: %w", err)
+			}
+
+			// A log without a key yet is checked without signatures.
+			key, err := secrets.LoadKey(config.AuditKeyName)
+			if err != nil && !errors.Is(err, secrets.ErrNotFound) {
+				return err
+			}
+
+			records, err := audit.Verify(path, key)
+			if err != nil {
+				return err
+			}
+
+			userUI := ui.New()
+			if len(records) == 0 {
+				userUI.Print("The audit log is empty.")
+				return nil
+			}
+			head := records[len(records)-1].Hash
+			userUI.PrintSuccess(fmt.Sprintf("Verified %d entries. Latest hash: %s", len(records), head))
+			if unsigned := countUnsigned(records); unsigned > 0 {
+				userUI.PrintWarning(fmt.Sprintf("%d entries were written before the log was signed and are only protected by the hash chain.", unsigned))
+			}
+			return nil
+		},
+	})
+
+	return auditCommand
+}
+
+func shortHash(hash string) string {
+	if hash == "" {
+		return "(none)"
+	}
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
+
+func countUnsigned(records []audit.Record) int {
+	count := 0
+	for _, record := range records {
+		if !record.Signed {
+			count++
+		}
+	}
+	return count
+}
@@ -0,0 +1,69 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/saat-sy/hyprlander/pkg/binds"
+	"github.com/saat-sy/hyprlander/pkg/config"
+	"github.com/saat-sy/hyprlander/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+func BindsCommand() *cobra.Command {
+	bindsCommand := &cobra.Command{
+		Use:   "binds",
+		Short: "List keybindings and find conflicts",
+		Long:  "List the keybindings of your Hyprland config across all sourced files, grouped by submap and modifier, and flag duplicated and conflicting binds and unknown dispatchers.",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := hyprlandDir(cmd)
+			if err != nil {
+				return err
+			}
+
+			inventory, err := binds.Load(filepath.Join(dir, config.MainConfigFileName))
+			if err != nil {
+				return err
+			}
+
+			userUI := ui.New()
+			out := cmd.OutOrStdout()
+
+			if combo, _ := cmd.Flags().GetString("check"); combo != "" {
+				mods, key, err := binds.ParseCombo(combo)
+				if err != nil {
+					return err
+				}
+				submap, _ := cmd.Flags().GetString("submap")
+				existing := inventory.Find(mods, key, submap)
+				if len(existing) == 0 {
+					userUI.PrintSuccess(fmt.Sprintf("%s is free", binds.FormatCombo(mods, key)))
+					return nil
+				}
+				for _, bind := range existing {
+					fmt.Fprintf(out, "%s  %s  (%s)\n", bind.Combo(), bind.Action(), bind.Location())
+				}
+				return fmt.Errorf("%s is already bound", binds.FormatCombo(mods, key))
+			}
+
+			if conflictsOnly, _ := cmd.Flags().GetBool("conflicts"); !conflictsOnly {
+				fmt.Fprintln(out, inventory.Format(dir))
+			}
+
+			if len(inventory.Issues) == 0 {
+				userUI.PrintSuccess(fmt.Sprintf("%d binds, no conflicts", len(inventory.Binds)))
+				return nil
+			}
+			userUI.PrintWarning(fmt.Sprintf("%d problems found", len(inventory.Issues)))
+			fmt.Fprintln(out, binds.FormatIssues(inventory.Issues, dir))
+			return nil
+		},
+	}
+
+	bindsCommand.Flags().Bool("conflicts", false, "only show problems")
+	bindsCommand.Flags().String("check", "", "check whether a key combination such as SUPER+T is free")
+	bindsCommand.Flags().String("submap", "", "submap for --check, defaults to the global binds")
+
+	return bindsCommand
+}
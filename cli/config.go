@@ -0,0 +1,110 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/saat-sy/hyprlander/pkg/config"
+	"github.com/saat-sy/hyprlander/pkg/settings"
+	"github.com/saat-sy/hyprlander/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+func ConfigCommand() *cobra.Command {
+	configCommand := &cobra.Command{
+		Use:   "config",
+		Short: "View or change hyprlander settings",
+		Long:  "View or change the settings stored in the hyprlander config.toml",
+	}
+
+	configCommand.AddCommand(&cobra.Command{
+		Use:   "get <key>",
+		Short: "Print the value of a setting",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			s, _, err := loadSettingsFile()
+			if err != nil {
+				return err
+			}
+
+			value, err := s.Get(args[0])
+			if err != nil {
+				return err
+			}
+
+			fmt.Fprintln(cmd.OutOrStdout(), value)
+			return nil
+		},
+	})
+
+	configCommand.AddCommand(&cobra.Command{
+		Use:   "set <key> <value>",
+		Short: "Change a setting",
+		Args:  cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			s, broken, err := loadSettingsFile()
+			if err != nil {
+				return err
+			}
+
+			if err := s.Set(args[0], args[1]); err != nil {
+				return err
+			}
+
+			userUI := ui.New()
+			if broken {
+				backup, err := settings.SetAside()
+				if err != nil {
+					return err
+				}
+				userUI.PrintWarning(fmt.Sprintf("Wrote a new settings file with the defaults; the one that could not be parsed was kept as %s", backup))
+			}
+
+			if err := s.Save(); err != nil {
+				return fmt.Errorf("failed to save settings: %w", err)
+			}
+
+			userUI.PrintSuccess(fmt.Sprintf("%s set to %s", args[0], args[1]))
+			return nil
+		},
+	})
+
+	configCommand.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List all settings",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			s, _, err := loadSettingsFile()
+			if err != nil {
+				return err
+			}
+
+			path, err := config.GetSettingsFilePath()
+			if err != nil {
+				return fmt.Errorf("could not determine settings file path: %w", err)
+			}
+
+			userUI := ui.New()
+			userUI.PrintTitle(path)
+			for _, key := range settings.Keys() {
+				value, _ := s.Get(key)
+				userUI.Print(fmt.Sprintf("%s = %s", key, value))
+			}
+			return nil
+		},
+	})
+
+	return configCommand
+}
+
+// loadSettingsFile reads the settings file for the config commands. A file
+// that is not valid TOML gives the defaults, reported as broken, so the
+// commands still run and config set can replace it. The root command has
+// already warned about the parse error.
+func loadSettingsFile() (*settings.Settings, bool, error) {
+	s, err := settings.LoadFile()
+	if errors.Is(err, settings.ErrParse) {
+		return settings.Default(), true, nil
+	}
+	return s, false, err
+}
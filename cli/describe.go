@@ -0,0 +1,49 @@
+package cli
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/saat-sy/hyprlander/pkg/schema"
+	"github.com/spf13/cobra"
+)
+
+func DescribeCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "describe [option]",
+		Short: "Show the type, default and range of a Hyprland option",
+		Long:  "Show the type, default, range and deprecation of a Hyprland option, e.g. decoration:blur:size or just border_size. Without an option, list every known option.",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := cmd.OutOrStdout()
+
+			if len(args) == 0 {
+				for _, option := range schema.Options() {
+					line := fmt.Sprintf("%s (%s)", option.Path, option.Type)
+					if option.Deprecated != nil {
+						line += " deprecated"
+					}
+					fmt.Fprintln(out, line)
+				}
+				return nil
+			}
+
+			found := schema.Find(args[0])
+			if len(found) == 0 {
+				message := fmt.Sprintf("unknown option %s", args[0])
+				if suggestions := schema.Suggest(args[0], 5); len(suggestions) > 0 {
+					message += ", did you mean " + strings.Join(suggestions, ", ") + "?"
+				}
+				return fmt.Errorf("%s", message)
+			}
+
+			for i, option := range found {
+				if i > 0 {
+					fmt.Fprintln(out)
+				}
+				fmt.Fprintln(out, schema.Describe(option))
+			}
+			return nil
+		},
+	}
+}
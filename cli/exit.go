@@ -0,0 +1,48 @@
+package cli
+
+import (
+	"errors"
+
+	"github.com/saat-sy/hyprlander/pkg/core/agent"
+)
+
+const (
+	ExitOK            = 0
+	ExitError         = 1
+	ExitDeclined      = 2
+	ExitMaxTurns      = 3
+	ExitProviderError = 4
+	ExitLintFailed    = 5
+)
+
+// ErrLintFailed is returned by 'hyprlander lint' when it found errors, or
+// warnings with --strict. The findings are already printed, so main does not
+// print it again.
+var ErrLintFailed = errors.New("lint found problems")
+
+// ErrNotFormatted is returned by 'hyprlander fmt --check' when a file would
+// change. The files are already listed.
+var ErrNotFormatted = errors.New("files are not formatted")
+
+// Silent reports whether err has already been reported to the user.
+func Silent(err error) bool {
+	return errors.Is(err, ErrLintFailed) || errors.Is(err, ErrNotFormatted)
+}
+
+// ExitCode maps an error returned by a command to the process exit code.
+func ExitCode(err error) int {
+	switch {
+	case err == nil:
+		return ExitOK
+	case errors.Is(err, agent.ErrDeclined):
+		return ExitDeclined
+	case errors.Is(err, agent.ErrMaxTurns):
+		return ExitMaxTurns
+	case errors.Is(err, agent.ErrProvider):
+		return ExitProviderError
+	case errors.Is(err, ErrLintFailed):
+		return ExitLintFailed
+	default:
+		return ExitError
+	}
+}
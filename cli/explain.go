@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+
+	"github.com/saat-sy/hyprlander/pkg/explain"
+	"github.com/saat-sy/hyprlander/pkg/hyprlang"
+	"github.com/spf13/cobra"
+)
+
+func ExplainCommand() *cobra.Command {
+	explainCommand := &cobra.Command{
+		Use:   "explain <option>",
+		Short: "Show where an option is set and which value takes effect",
+		Long: `Follow the source lines from hyprland.conf, expand $variables and list every file and line that sets an option, which assignment wins, and the default from the option schema.
+
+Options of hyprpaper, hyprlock, hypridle and hyprsunset are explained against their own config. The program is detected from the option name; pass --program when the name is ambiguous.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := hyprlandDir(cmd)
+			if err != nil {
+				return err
+			}
+			name, _ := cmd.Flags().GetString("program")
+			program, err := explain.Program(args[0], name)
+			if err != nil {
+				return err
+			}
+			cfg, err := hyprlang.LoadConfig(filepath.Join(dir, program.ConfigFileName()))
+			if err != nil {
+				return err
+			}
+
+			explanation, err := explain.ExplainIn(cfg, program, args[0])
+			if err != nil {
+				return err
+			}
+
+			if asJSON, _ := cmd.Flags().GetBool("json"); asJSON {
+				encoder := json.NewEncoder(cmd.OutOrStdout())
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(explanation)
+			}
+			fmt.Fprintln(cmd.OutOrStdout(), explanation.Format(dir))
+			return nil
+		},
+	}
+
+	explainCommand.Flags().Bool("json", false, "print the explanation as JSON")
+	explainCommand.Flags().String("program", "", "hypr program whose config to read: hyprland, hyprpaper, hyprlock, hypridle or hyprsunset")
+
+	return explainCommand
+}
@@ -0,0 +1,73 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/saat-sy/hyprlander/pkg/config"
+	"github.com/saat-sy/hyprlander/pkg/hyprlang"
+	"github.com/saat-sy/hyprlander/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+func FmtCommand() *cobra.Command {
+	fmtCommand := &cobra.Command{
+		Use:   "fmt [file...]",
+		Short: "Format Hyprland config files",
+		Long: "Normalize indentation inside category blocks, spacing around '=', blank lines and comment indentation, and align the columns of bind lines. " +
+			"Without arguments every file reached from hyprland.conf is formatted. " +
+			"With --check nothing is written and the command fails if a file would change.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			paths := args
+			if len(paths) == 0 {
+				dir, err := hyprlandDir(cmd)
+				if err != nil {
+					return err
+				}
+				cfg, err := hyprlang.LoadConfig(filepath.Join(dir, config.MainConfigFileName))
+				if err != nil {
+					return err
+				}
+				for _, doc := range cfg.Files {
+					paths = append(paths, doc.Path)
+				}
+			}
+
+			var changes []hyprlang.FileChange
+			for _, path := range paths {
+				content, err := os.ReadFile(path)
+				if err != nil {
+					return fmt.Errorf("could not read %s: %w", path, err)
+				}
+				if formatted := hyprlang.Format(string(content)); formatted != string(content) {
+					changes = append(changes, hyprlang.FileChange{Path: path, Content: formatted})
+				}
+			}
+
+			userUI := ui.New()
+			if check, _ := cmd.Flags().GetBool("check"); check {
+				for _, change := range changes {
+					fmt.Fprintln(cmd.OutOrStdout(), change.Path)
+				}
+				if len(changes) > 0 {
+					userUI.PrintWarning(fmt.Sprintf("%d file(s) are not formatted, run 'hyprlander fmt'", len(changes)))
+					return ErrNotFormatted
+				}
+				return nil
+			}
+
+			if len(changes) == 0 {
+				userUI.PrintSuccess("Already formatted")
+				return nil
+			}
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			return applyChanges(cmd, "fmt", changes, dryRun)
+		},
+	}
+
+	fmtCommand.Flags().Bool("check", false, "list unformatted files and fail if there are any")
+	fmtCommand.Flags().Bool("dry-run", false, "show the changes without writing them")
+
+	return fmtCommand
+}
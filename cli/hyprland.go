@@ -0,0 +1,30 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/saat-sy/hyprlander/pkg/config"
+	"github.com/saat-sy/hyprlander/pkg/setup"
+	"github.com/spf13/cobra"
+)
+
+// hyprlandDir resolves the Hyprland config directory of the active profile,
+// falling back to the one recorded by 'hyprlander init'.
+func hyprlandDir(cmd *cobra.Command) (string, error) {
+	s, err := loadSettings(cmd)
+	if err != nil {
+		return "", err
+	}
+	if s.HyprlandDir != "" {
+		return s.HyprlandDir, nil
+	}
+
+	keys, err := setup.NewSetup().FetchProfileConfig(s.ProfileName())
+	if err != nil {
+		return "", fmt.Errorf("hyprland directory not configured, run 'hyprlander init' or pass --dir: %w", err)
+	}
+	if dir := keys[config.HyprlandDirName]; dir != "" {
+		return dir, nil
+	}
+	return "", fmt.Errorf("hyprland directory not configured for profile %s", s.ProfileName())
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 
 	"github.com/saat-sy/hyprlander/pkg/config"
+	"github.com/saat-sy/hyprlander/pkg/secrets"
 	"github.com/saat-sy/hyprlander/pkg/setup"
 	"github.com/saat-sy/hyprlander/pkg/ui"
 	"github.com/spf13/cobra"
@@ -26,9 +27,19 @@ func InitCommand() *cobra.Command {
 				return nil
 			}
 
-			apiKey, err := init.Prompt("Please enter your Gemini API key: ")
-			if err != nil {
-				return fmt.Errorf("failed to get API key: %w", err)
+			if _, source, err := secrets.LoadAPIKey(config.APIKeyName); err == nil {
+				userUI.Print(fmt.Sprintf("Using the API key from %s", source))
+			} else {
+				apiKey, err := init.PromptSecret("Please enter your Gemini API key: ")
+				if err != nil {
+					return fmt.Errorf("failed to get API key: %w", err)
+				}
+
+				backend, err := secrets.SaveAPIKey(config.APIKeyName, apiKey)
+				if err != nil {
+					return err
+				}
+				userUI.Print(fmt.Sprintf("API key stored in the %s", backend))
 			}
 
 			hyprlandDir, err := init.Prompt("Please enter the path to your Hyprland configuration directory (e.g., /home/user/.config/hypr): ")
@@ -37,7 +48,6 @@ func InitCommand() *cobra.Command {
 			}
 
 			values := map[string]string{
-				config.APIKeyName:      apiKey,
 				config.HyprlandDirName: hyprlandDir,
 			}
 			if err := init.Run(values); err != nil {
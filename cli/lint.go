@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/saat-sy/hyprlander/pkg/lint"
+	"github.com/saat-sy/hyprlander/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+func LintCommand() *cobra.Command {
+	lintCommand := &cobra.Command{
+		Use:   "lint",
+		Short: "Check your Hyprland config for problems",
+		Long: "Check your Hyprland config and every file it sources for unknown and deprecated options, invalid values, " +
+			"undefined and unused variables, missing source targets and .conf files that are never sourced. " +
+			"Exits with status 5 when errors are found, or warnings with --strict.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out := cmd.OutOrStdout()
+
+			if listRules, _ := cmd.Flags().GetBool("list-rules"); listRules {
+				for _, rule := range lint.Rules() {
+					fmt.Fprintf(out, "%-20s %s\n", rule.ID(), rule.Description())
+				}
+				return nil
+			}
+
+			enable, _ := cmd.Flags().GetStringSlice("enable")
+			disable, _ := cmd.Flags().GetStringSlice("disable")
+			rules, err := lint.Select(enable, disable)
+			if err != nil {
+				return err
+			}
+
+			dir, err := hyprlandDir(cmd)
+			if err != nil {
+				return err
+			}
+			target, err := lint.Load(dir)
+			if err != nil {
+				return err
+			}
+			findings := lint.Run(target, rules)
+			errorCount, warningCount := lint.Count(findings)
+
+			format, _ := cmd.Flags().GetString("format")
+			switch format {
+			case "text":
+				userUI := ui.New()
+				if len(findings) == 0 {
+					userUI.PrintSuccess("No problems found")
+					break
+				}
+				fmt.Fprintln(out, lint.FormatText(target, findings))
+				userUI.PrintWarning(fmt.Sprintf("%d errors, %d warnings", errorCount, warningCount))
+			case "json":
+				err = lint.WriteJSON(out, target, findings)
+			case "sarif":
+				err = lint.WriteSARIF(out, target, rules, findings)
+			default:
+				return fmt.Errorf("unknown format %q, expected text, json or sarif", format)
+			}
+			if err != nil {
+				return err
+			}
+
+			strict, _ := cmd.Flags().GetBool("strict")
+			if errorCount > 0 || (strict && warningCount > 0) {
+				return ErrLintFailed
+			}
+			return nil
+		},
+	}
+
+	lintCommand.Flags().String("format", "text", "output format: text, json or sarif")
+	lintCommand.Flags().Bool("strict", false, "fail on warnings as well as errors")
+	lintCommand.Flags().StringSlice("enable", nil, "only run these rules")
+	lintCommand.Flags().StringSlice("disable", nil, "skip these rules")
+	lintCommand.Flags().Bool("list-rules", false, "list the available rules")
+
+	return lintCommand
+}
@@ -0,0 +1,64 @@
+package cli
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/saat-sy/hyprlander/pkg/config"
+	"github.com/saat-sy/hyprlander/pkg/hyprlang"
+	"github.com/saat-sy/hyprlander/pkg/migrate"
+	"github.com/saat-sy/hyprlander/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+func MigrateCommand() *cobra.Command {
+	migrateCommand := &cobra.Command{
+		Use:   "migrate",
+		Short: "Rewrite deprecated options and syntax for a newer Hyprland",
+		Long: "Rewrite renamed and removed options and legacy syntax such as windowrule and blurls in your config and every file it sources. " +
+			"The changes are mechanical and made without the model. The diff is shown before anything is written and the files are snapshotted first.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			to, _ := cmd.Flags().GetString("to")
+			if to == "" {
+				to = migrate.Latest()
+			}
+
+			dir, err := hyprlandDir(cmd)
+			if err != nil {
+				return err
+			}
+			cfg, err := hyprlang.LoadConfig(filepath.Join(dir, config.MainConfigFileName))
+			if err != nil {
+				return err
+			}
+			results, err := migrate.Plan(cfg, to)
+			if err != nil {
+				return err
+			}
+
+			userUI := ui.New()
+			if len(results) == 0 {
+				userUI.PrintSuccess(fmt.Sprintf("Nothing to migrate for Hyprland %s", to))
+				return nil
+			}
+
+			var changes []hyprlang.FileChange
+			for _, result := range results {
+				userUI.PrintTitle(result.Path)
+				for _, change := range result.Changes {
+					userUI.Print(fmt.Sprintf("  line %d: %s", change.Line, change.Description))
+				}
+				changes = append(changes, hyprlang.FileChange{Path: result.Path, Content: result.Migrated})
+			}
+
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			return applyChanges(cmd, "migrate --to "+to, changes, dryRun)
+		},
+	}
+
+	migrateCommand.Flags().String("to", "", fmt.Sprintf("Hyprland release to migrate to (default %s)", migrate.Latest()))
+	migrateCommand.Flags().Bool("dry-run", false, "show the changes without writing them")
+
+	return migrateCommand
+}
@@ -0,0 +1,139 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/saat-sy/hyprlander/pkg/secrets"
+	"github.com/saat-sy/hyprlander/pkg/settings"
+	"github.com/saat-sy/hyprlander/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+func ProfileCommand() *cobra.Command {
+	profileCommand := &cobra.Command{
+		Use:   "profile",
+		Short: "Manage named profiles",
+		Long:  "Manage named profiles, each with its own Hyprland directory, API key, model and policy",
+	}
+
+	profileCommand.AddCommand(&cobra.Command{
+		Use:   "create <name>",
+		Short: "Create a profile",
+		Long:  "Create a profile. Use --dir, --model and --max-turns to set its values.",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			name := args[0]
+			userUI := ui.New()
+
+			s, err := settings.LoadFile()
+			if err != nil {
+				return err
+			}
+
+			var profile settings.Profile
+			flags := cmd.Flags()
+			if flags.Changed("dir") {
+				profile.HyprlandDir, _ = flags.GetString("dir")
+			} else {
+				profile.HyprlandDir, err = userUI.InputRequired("Please enter the path to the Hyprland configuration directory for this profile: ")
+				if err != nil {
+					return fmt.Errorf("failed to get Hyprland config directory: %w", err)
+				}
+			}
+			if flags.Changed("model") {
+				profile.Model, _ = flags.GetString("model")
+			}
+			if flags.Changed("max-turns") {
+				profile.MaxTurns, _ = flags.GetInt("max-turns")
+			}
+
+			if err := s.AddProfile(name, profile); err != nil {
+				return err
+			}
+
+			separateKey, err := userUI.Confirm("Use a separate API key for this profile?")
+			if err != nil {
+				return fmt.Errorf("failed to read answer: %w", err)
+			}
+			if separateKey {
+				apiKey, err := userUI.InputSecret("Please enter the Gemini API key for this profile: ")
+				if err != nil {
+					return fmt.Errorf("failed to get API key: %w", err)
+				}
+				if _, err := secrets.SaveAPIKey(settings.ProfileAPIKeyName(name), apiKey); err != nil {
+					return err
+				}
+			}
+
+			if err := s.Save(); err != nil {
+				return fmt.Errorf("failed to save settings: %w", err)
+			}
+
+			userUI.PrintSuccess(fmt.Sprintf("Profile %s created! Switch to it with 'hyprlander profile use %s'", name, name))
+			return nil
+		},
+	})
+
+	profileCommand.AddCommand(&cobra.Command{
+		Use:   "list",
+		Short: "List profiles",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			s, err := settings.LoadFile()
+			if err != nil {
+				return err
+			}
+
+			userUI := ui.New()
+			userUI.PrintTitle("Profiles")
+			for _, name := range s.ProfileNames() {
+				marker := " "
+				if name == s.ProfileName() {
+					marker = "*"
+				}
+
+				dir, model := s.HyprlandDir, s.Model
+				if profile, ok := s.Profiles[name]; ok {
+					if profile.HyprlandDir != "" {
+						dir = profile.HyprlandDir
+					}
+					if profile.Model != "" {
+						model = profile.Model
+					}
+				}
+				if dir == "" {
+					dir = "(from secrets.ini)"
+				}
+
+				userUI.Print(fmt.Sprintf("%s %s  %s  %s", marker, name, dir, model))
+			}
+			return nil
+		},
+	})
+
+	profileCommand.AddCommand(&cobra.Command{
+		Use:   "use <name>",
+		Short: "Switch the active profile",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			s, err := settings.LoadFile()
+			if err != nil {
+				return err
+			}
+
+			if err := s.UseProfile(args[0]); err != nil {
+				return err
+			}
+
+			if err := s.Save(); err != nil {
+				return fmt.Errorf("failed to save settings: %w", err)
+			}
+
+			userUI := ui.New()
+			userUI.PrintSuccess(fmt.Sprintf("Now using profile %s", args[0]))
+			return nil
+		},
+	})
+
+	return profileCommand
+}
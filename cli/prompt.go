@@ -1,24 +1,149 @@
 package cli
 
 import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
 	"github.com/saat-sy/hyprlander/pkg/core/agent"
+	"github.com/saat-sy/hyprlander/pkg/ui"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 )
 
 func PromptCommand() *cobra.Command {
 	promptCommand := &cobra.Command{
-		Use:   "prompt",
+		Use:   "prompt [request...]",
 		Short: "Execute prompt-based hyprland configuration changes",
-		Long:  "Use natural language prompts to modify hyprland configuration files",
+		Long: `Use natural language prompts to modify hyprland configuration files.
+
+The request is taken from the arguments, from --file, or from stdin when it is
+not a terminal. With --output json only the first line of stdin is the request;
+the following lines answer confirmations. Exit codes: 0 success, 1 error, 2 declined, 3 maximum turns
+reached, 4 model provider error.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			agent := agent.NewAgent()
-			if len(args) > 0 {
-				prompt := args[0]
-				agent.InvokeAgent(prompt)
+			s, err := loadSettings(cmd)
+			if err != nil {
+				return err
+			}
+
+			output, _ := cmd.Flags().GetString("output")
+			switch output {
+			case ui.OutputText:
+			case ui.OutputJSON:
+				ui.Configure(ui.Options{Color: false, ShowDiff: s.UI.ShowDiff, Output: ui.OutputJSON})
+			default:
+				return fmt.Errorf("unknown output format %q, expected text or json", output)
+			}
+
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			promptFile, _ := cmd.Flags().GetString("file")
+
+			prompt, fromStdin, err := readPrompt(args, promptFile, output == ui.OutputJSON)
+			if err != nil {
+				return err
 			}
-			return nil
+			if fromStdin && !s.AssumeYes && !dryRun {
+				return fmt.Errorf("the prompt was read from stdin, so tool calls cannot be confirmed; pass --yes or --dry-run")
+			}
+
+			options := agent.Options{DryRun: dryRun}
+			if useTUI, _ := cmd.Flags().GetBool("tui"); useTUI {
+				switch {
+				case output == ui.OutputJSON:
+					return fmt.Errorf("--tui cannot be combined with --output json")
+				case !ui.CanUseTUI():
+					ui.New().PrintWarning("Not running in a terminal, falling back to plain output")
+				default:
+					tui := ui.NewTUI()
+					defer tui.Close()
+					options.UI = tui
+				}
+			}
+
+			agent, err := agent.NewAgent(s, options)
+			if err != nil {
+				return err
+			}
+
+			return agent.InvokeAgent(prompt)
 		},
 	}
 
+	promptCommand.Flags().Bool("dry-run", false, "run reads but only show the writes and commands the agent would make")
+	promptCommand.Flags().StringP("output", "o", ui.OutputText, "output format: text or json (newline-delimited events)")
+	promptCommand.Flags().Bool("tui", false, "use the full-screen terminal UI")
+	promptCommand.Flags().StringP("file", "f", "", "read the prompt from a file, or '-' for stdin")
+
 	return promptCommand
 }
+
+// readPrompt joins the arguments into a prompt, or reads it from a file or
+// piped stdin. With firstLine only the first line of stdin is read, leaving
+// the rest for answers to confirmations. It reports whether stdin was
+// consumed.
+func readPrompt(args []string, file string, firstLine bool) (string, bool, error) {
+	if len(args) > 0 {
+		return strings.Join(args, " "), false, nil
+	}
+
+	var reader io.Reader
+	fromStdin := false
+	stdin := file == "-" || (file == "" && !term.IsTerminal(int(os.Stdin.Fd())))
+	switch {
+	case stdin && firstLine:
+		line, err := readLine(os.Stdin)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to read prompt: %w", err)
+		}
+		reader = strings.NewReader(line)
+	case stdin:
+		reader = os.Stdin
+		fromStdin = true
+	case file != "":
+		f, err := os.Open(file)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to open prompt file: %w", err)
+		}
+		defer f.Close()
+		reader = f
+	default:
+		return "", false, fmt.Errorf("no prompt given; pass it as an argument, with --file, or on stdin")
+	}
+
+	content, err := io.ReadAll(reader)
+	if err != nil {
+		return "", false, fmt.Errorf("failed to read prompt: %w", err)
+	}
+
+	prompt := strings.TrimSpace(string(content))
+	if prompt == "" {
+		return "", false, fmt.Errorf("the prompt is empty")
+	}
+
+	return prompt, fromStdin, nil
+}
+
+// readLine reads up to the first newline one byte at a time, so nothing after
+// it is buffered away from later readers of r.
+func readLine(r io.Reader) (string, error) {
+	var line []byte
+	buf := make([]byte, 1)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			if buf[0] == '\n' {
+				return string(line), nil
+			}
+			line = append(line, buf[0])
+		}
+		if errors.Is(err, io.EOF) {
+			return string(line), nil
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+}
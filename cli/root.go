@@ -1,6 +1,7 @@
 package cli
 
 import (
+	"github.com/saat-sy/hyprlander/pkg/settings"
 	"github.com/saat-sy/hyprlander/pkg/ui"
 	"github.com/spf13/cobra"
 )
@@ -10,15 +11,94 @@ func RootCommand() *cobra.Command {
 		Use:   "hyprlander",
 		Short: "An agent that can modify how hyprland looks!",
 		Long:  "Use this package to just give prompts and to directly make changes to the hypr config files",
+		// main prints returned errors through the UI.
+		SilenceErrors: true,
+		SilenceUsage:  true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			s, err := loadSettings(cmd)
+			if err != nil && repairsSettings(cmd) {
+				// These commands are how invalid settings get fixed, so they
+				// still run.
+				ui.New().PrintWarning(err.Error())
+				s, err = settings.Default(), nil
+			}
+			if err != nil {
+				return err
+			}
+			ui.Configure(ui.Options{
+				Color:    s.UI.Color,
+				ShowDiff: s.UI.ShowDiff,
+			})
+			return nil
+		},
 		Run: func(cmd *cobra.Command, args []string) {
 			userUI := ui.New()
 			userUI.Print("Welcome to hyprlander! Use 'hyprlander --help' to see available commands.")
 		},
 	}
 
+	rootCmd.PersistentFlags().String("profile", "", "profile to use instead of the active one")
+	rootCmd.PersistentFlags().String("model", "", "Gemini model to use")
+	rootCmd.PersistentFlags().Int("max-turns", 0, "maximum number of agent turns")
+	rootCmd.PersistentFlags().BoolP("yes", "y", false, "approve tool calls without asking")
+	rootCmd.PersistentFlags().String("dir", "", "Hyprland configuration directory")
+
 	rootCmd.AddCommand(PromptCommand())
 	rootCmd.AddCommand(InitCommand())
 	rootCmd.AddCommand(UpdateCommand())
+	rootCmd.AddCommand(ConfigCommand())
+	rootCmd.AddCommand(ProfileCommand())
+	rootCmd.AddCommand(AuditCommand())
+	rootCmd.AddCommand(DescribeCommand())
+	rootCmd.AddCommand(BindsCommand())
+	rootCmd.AddCommand(LintCommand())
+	rootCmd.AddCommand(MigrateCommand())
+	rootCmd.AddCommand(SnapshotCommand())
+	rootCmd.AddCommand(FmtCommand())
+	rootCmd.AddCommand(ExplainCommand())
+	rootCmd.AddCommand(ThemeCommand())
 
 	return rootCmd
 }
+
+// repairsSettings reports whether cmd belongs to a command group that edits
+// the settings file rather than relying on it: config, and profile for when
+// the active profile was renamed or removed by hand.
+func repairsSettings(cmd *cobra.Command) bool {
+	for c := cmd; c.HasParent(); c = c.Parent() {
+		if c.Parent() == cmd.Root() && (c.Name() == "config" || c.Name() == "profile") {
+			return true
+		}
+	}
+	return false
+}
+
+// loadSettings resolves the effective settings, treating any root flags the
+// user passed explicitly as overrides of the settings file.
+func loadSettings(cmd *cobra.Command) (*settings.Settings, error) {
+	flags := cmd.Flags()
+	var overrides settings.Overrides
+
+	if flags.Changed("profile") {
+		profile, _ := flags.GetString("profile")
+		overrides.Profile = &profile
+	}
+	if flags.Changed("model") {
+		model, _ := flags.GetString("model")
+		overrides.Model = &model
+	}
+	if flags.Changed("max-turns") {
+		maxTurns, _ := flags.GetInt("max-turns")
+		overrides.MaxTurns = &maxTurns
+	}
+	if flags.Changed("yes") {
+		yes, _ := flags.GetBool("yes")
+		overrides.AssumeYes = &yes
+	}
+	if flags.Changed("dir") {
+		dir, _ := flags.GetString("dir")
+		overrides.HyprlandDir = &dir
+	}
+
+	return settings.Load(overrides)
+}
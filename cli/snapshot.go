@@ -0,0 +1,75 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/saat-sy/hyprlander/pkg/config"
+	"github.com/saat-sy/hyprlander/pkg/snapshot"
+	"github.com/saat-sy/hyprlander/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+func SnapshotCommand() *cobra.Command {
+	snapshotCommand := &cobra.Command{
+		Use:   "snapshot",
+		Short: "List and restore the snapshots taken before config rewrites",
+		Long:  "Commands such as 'hyprlander migrate' save the files they rewrite to ~/.hyprlander/snapshots first. List those snapshots or put the files back.",
+	}
+
+	listCommand := &cobra.Command{
+		Use:   "list",
+		Short: "List snapshots, newest first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := config.GetSnapshotsDirectory()
+			if err != nil {
+				return fmt.Errorf("could not determine snapshot directory: %w", err)
+			}
+			snapshots, err := snapshot.List(dir)
+			if err != nil {
+				return err
+			}
+
+			userUI := ui.New()
+			if len(snapshots) == 0 {
+				userUI.Print("No snapshots yet.")
+				return nil
+			}
+			for _, snap := range snapshots {
+				userUI.Print(fmt.Sprintf("%s  %s  %d file(s)", snap.ID, snap.Reason, len(snap.Files)))
+			}
+			return nil
+		},
+	}
+
+	restoreCommand := &cobra.Command{
+		Use:   "restore <id>",
+		Short: "Put the files of a snapshot back",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := config.GetSnapshotsDirectory()
+			if err != nil {
+				return fmt.Errorf("could not determine snapshot directory: %w", err)
+			}
+			snap, err := snapshot.Load(dir, args[0])
+			if err != nil {
+				return err
+			}
+			if err := snap.Restore(); err != nil {
+				return err
+			}
+
+			userUI := ui.New()
+			for _, file := range snap.Files {
+				userUI.Print(file.Path)
+			}
+			userUI.PrintSuccess(fmt.Sprintf("Restored snapshot %s", snap.ID))
+			return nil
+		},
+	}
+
+	snapshotCommand.AddCommand(listCommand)
+	snapshotCommand.AddCommand(restoreCommand)
+
+	return snapshotCommand
+}
@@ -0,0 +1,160 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/saat-sy/hyprlander/pkg/core/tools"
+	"github.com/saat-sy/hyprlander/pkg/theme"
+	"github.com/saat-sy/hyprlander/pkg/ui"
+	"github.com/spf13/cobra"
+)
+
+func ThemeCommand() *cobra.Command {
+	themeCommand := &cobra.Command{
+		Use:   "theme",
+		Short: "List, preview and apply bundled color themes",
+		Long: "Apply a bundled color theme to the borders, groups, shadows and background of your Hyprland config, and with --apps to waybar, kitty, foot, alacritty, mako and wofi. " +
+			"Themes are applied without the model; the diff is shown and the files are snapshotted first.",
+	}
+
+	listCommand := &cobra.Command{
+		Use:   "list",
+		Short: "List the bundled themes",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			palettes, err := theme.List()
+			if err != nil {
+				return err
+			}
+			for _, palette := range palettes {
+				fmt.Fprintf(cmd.OutOrStdout(), "%-22s %s (%s)\n", palette.Name, palette.Title, palette.Variant)
+			}
+			return nil
+		},
+	}
+
+	previewCommand := &cobra.Command{
+		Use:   "preview <name>",
+		Short: "Show a theme's colors and the options it sets",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			palette, err := theme.Get(args[0])
+			if err != nil {
+				return err
+			}
+			ui.New().Print(theme.Preview(palette))
+			return nil
+		},
+	}
+
+	applyCommand := &cobra.Command{
+		Use:   "apply <name>",
+		Short: "Apply a theme to your Hyprland config and, with --apps, other apps",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := hyprlandDir(cmd)
+			if err != nil {
+				return err
+			}
+			palette, err := tools.LoadPalette(dir, args[0], "")
+			if err != nil {
+				return err
+			}
+			return applyPalette(cmd, dir, palette, "theme apply "+args[0])
+		},
+	}
+	applyCommand.Flags().Bool("dry-run", false, "show the changes without writing them")
+	applyCommand.Flags().StringSlice("apps", nil, "configs to theme: hyprland, waybar, kitty, foot, alacritty, mako, wofi, or all (default hyprland)")
+
+	wallpaperCommand := &cobra.Command{
+		Use:   "wallpaper",
+		Short: "Derive a theme from the wallpaper and apply it",
+		Long: "Extract the dominant colors of the wallpaper set in hyprpaper.conf, or of --image, and assign them to the background, border and accent roles. " +
+			"Each role is adjusted until it is readable against the background. Use --preview to only show the palette.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dir, err := hyprlandDir(cmd)
+			if err != nil {
+				return err
+			}
+
+			image, _ := cmd.Flags().GetString("image")
+			if image == "" {
+				monitor, _ := cmd.Flags().GetString("monitor")
+				if image, err = theme.WallpaperPath(dir, monitor); err != nil {
+					return err
+				}
+			}
+			variant, _ := cmd.Flags().GetString("variant")
+			palette, swatches, err := theme.FromImage(image, variant)
+			if err != nil {
+				return err
+			}
+
+			userUI := ui.New()
+			userUI.Print(theme.PreviewSwatches(swatches))
+			userUI.Print(theme.Preview(palette))
+			if preview, _ := cmd.Flags().GetBool("preview"); preview {
+				return nil
+			}
+			return applyPalette(cmd, dir, palette, "theme wallpaper "+image)
+		},
+	}
+	wallpaperCommand.Flags().String("image", "", "image to take the colors from instead of the hyprpaper wallpaper")
+	wallpaperCommand.Flags().String("monitor", "", "use the wallpaper of this monitor")
+	wallpaperCommand.Flags().String("variant", "", "dark or light (default: from the image)")
+	wallpaperCommand.Flags().Bool("preview", false, "only show the palette")
+	wallpaperCommand.Flags().Bool("dry-run", false, "show the changes without writing them")
+	wallpaperCommand.Flags().StringSlice("apps", nil, "configs to theme: hyprland, waybar, kitty, foot, alacritty, mako, wofi, or all (default hyprland)")
+
+	appsCommand := &cobra.Command{
+		Use:   "apps",
+		Short: "List the apps themes can be written to and where their configs are",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			s, err := loadSettings(cmd)
+			if err != nil {
+				return err
+			}
+			for _, app := range theme.Apps() {
+				path, err := app.Path(s.AppDirs)
+				if err != nil {
+					return err
+				}
+				status := "not found"
+				if app.Installed(s.AppDirs) {
+					status = "found"
+				}
+				fmt.Fprintf(cmd.OutOrStdout(), "%-10s %s (%s)\n", app.Name, path, status)
+			}
+			return nil
+		},
+	}
+
+	themeCommand.AddCommand(listCommand)
+	themeCommand.AddCommand(previewCommand)
+	themeCommand.AddCommand(applyCommand)
+	themeCommand.AddCommand(wallpaperCommand)
+	themeCommand.AddCommand(appsCommand)
+
+	return themeCommand
+}
+
+func applyPalette(cmd *cobra.Command, dir string, palette theme.Palette, reason string) error {
+	s, err := loadSettings(cmd)
+	if err != nil {
+		return err
+	}
+	apps, _ := cmd.Flags().GetStringSlice("apps")
+	changes, err := tools.PlanTheme(tools.ThemeTargets{HyprlandDir: dir, Apps: apps, AppDirs: s.AppDirs}, palette)
+	if err != nil {
+		return err
+	}
+	if len(changes) == 0 {
+		ui.New().PrintSuccess("The config already uses these colors")
+		return nil
+	}
+
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	return applyChanges(cmd, reason, changes, dryRun)
+}
@@ -2,7 +2,10 @@ package cli
 
 import (
 	"fmt"
+	"sort"
 
+	"github.com/saat-sy/hyprlander/pkg/config"
+	"github.com/saat-sy/hyprlander/pkg/secrets"
 	"github.com/saat-sy/hyprlander/pkg/setup"
 	"github.com/saat-sy/hyprlander/pkg/ui"
 	"github.com/spf13/cobra"
@@ -12,7 +15,7 @@ func UpdateCommand() *cobra.Command {
 	updateCommand := &cobra.Command{
 		Use:   "update",
 		Short: "Update gemini api key",
-		Long:  "Update the stored gemini api key used for authentication",
+		Long:  "Update the stored gemini api key used for authentication, or the Hyprland config directory",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			userUI := ui.New()
 			userUI.Print("Fetching current config...")
@@ -29,10 +32,29 @@ func UpdateCommand() *cobra.Command {
 				return fmt.Errorf("failed to fetch current config: %w", err)
 			}
 
-			var keys []string
+			// Older versions kept the key in plain text in secrets.ini. Move it
+			// into a secure store before rewriting the file without it.
+			if legacyKey, ok := currentConfig[config.APIKeyName]; ok {
+				delete(currentConfig, config.APIKeyName)
+				if _, _, err := secrets.LoadAPIKey(config.APIKeyName); err != nil && legacyKey != "" {
+					backend, err := secrets.SaveAPIKey(config.APIKeyName, legacyKey)
+					if err != nil {
+						return err
+					}
+					userUI.PrintWarning(fmt.Sprintf("Moved the plain text API key into the %s", backend))
+				}
+				if err := update.Update(currentConfig); err != nil {
+					return fmt.Errorf("failed to update config: %w", err)
+				}
+			}
+
+			keys := []string{config.APIKeyName}
+			var configKeys []string
 			for key := range currentConfig {
-				keys = append(keys, key)
+				configKeys = append(configKeys, key)
 			}
+			sort.Strings(configKeys)
+			keys = append(keys, configKeys...)
 
 			selectedIndex, err := userUI.Select("What do you want to update?", keys)
 			if err != nil {
@@ -41,6 +63,10 @@ func UpdateCommand() *cobra.Command {
 
 			selectedKey := keys[selectedIndex]
 
+			if selectedKey == config.APIKeyName {
+				return updateAPIKey(userUI)
+			}
+
 			contentMap := make(map[string]string)
 
 			for key, value := range currentConfig {
@@ -66,3 +92,28 @@ func UpdateCommand() *cobra.Command {
 
 	return updateCommand
 }
+
+func updateAPIKey(userUI ui.UI) error {
+	current, source, err := secrets.LoadAPIKey(config.APIKeyName)
+	prompt := "Enter new API key: "
+	if err == nil {
+		prompt = fmt.Sprintf("Enter new API key (current: %s from %s): ", secrets.Mask(current), source)
+	}
+
+	newKey, err := userUI.InputSecret(prompt)
+	if err != nil {
+		return fmt.Errorf("failed to read new value: %w", err)
+	}
+
+	backend, err := secrets.SaveAPIKey(config.APIKeyName, newKey)
+	if err != nil {
+		return err
+	}
+
+	if source == config.EnvAPIKey || source == config.EnvGeminiAPIKey {
+		userUI.PrintWarning(fmt.Sprintf("%s is set and will still take precedence over the stored key", source))
+	}
+
+	userUI.PrintSuccess(fmt.Sprintf("API key updated in the %s!", backend))
+	return nil
+}
@@ -11,8 +11,10 @@ func main() {
 	rootCmd := cli.RootCommand()
 
 	if err := rootCmd.Execute(); err != nil {
-		userUI := ui.New()
-		userUI.PrintError(err)
-		os.Exit(1)
+		if !cli.Silent(err) {
+			userUI := ui.New()
+			userUI.PrintError(err)
+		}
+		os.Exit(cli.ExitCode(err))
 	}
 }
@@ -0,0 +1,360 @@
+// Package audit keeps an append-only log of every tool the agent runs. Each
+// record carries the hash of the one before it, so editing or removing a
+// record in the middle of the log breaks the chain and is caught by Verify.
+//
+// Records are also signed with an HMAC under a key kept in the secret store,
+// and the count and hash of the latest record are kept in a signed head file
+// next to the log, so someone without the key cannot rewrite a record and
+// recompute the hashes after it, or cut records off the end, unnoticed.
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"path/filepath"
+	"syscall"
+	"time"
+)
+
+// GenesisHash is the previous hash of the first record.
+const GenesisHash = "0000000000000000000000000000000000000000000000000000000000000000"
+
+var ErrTampered = errors.New("audit log has been tampered with")
+
+type Entry struct {
+	Seq        int64          `json:"seq"`
+	Started    time.Time      `json:"started"`
+	Finished   time.Time      `json:"finished"`
+	Session    string         `json:"session"`
+	User       string         `json:"user"`
+	Host       string         `json:"host"`
+	Profile    string         `json:"profile"`
+	Tool       string         `json:"tool"`
+	Args       map[string]any `json:"args"`
+	Decision   string         `json:"decision"`
+	ApprovedBy string         `json:"approved_by"`
+	OutputHash string         `json:"output_sha256,omitempty"`
+	Error      string         `json:"error,omitempty"`
+	Files      []FileHash     `json:"files,omitempty"`
+	Prev       string         `json:"prev"`
+}
+
+// FileHash records the SHA-256 of a file before and after a call. An empty
+// hash means the file did not exist.
+type FileHash struct {
+	Path   string `json:"path"`
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+type Record struct {
+	Entry Entry
+	Hash  string
+	// Signed is false for records written before the log had a key.
+	Signed bool
+}
+
+// line is the on-disk form of a record. The hash covers the entry bytes
+// exactly as written, so verifying does not depend on re-encoding.
+type line struct {
+	Entry json.RawMessage `json:"entry"`
+	Hash  string          `json:"hash"`
+	MAC   string          `json:"mac,omitempty"`
+}
+
+// head records the latest record, so records cut off the end of the log are
+// noticed.
+type head struct {
+	Seq  int64  `json:"seq"`
+	Hash string `json:"hash"`
+	MAC  string `json:"mac,omitempty"`
+}
+
+type Log struct {
+	path    string
+	key     []byte
+	session string
+	user    string
+	host    string
+	profile string
+}
+
+// NewLog returns a log that appends to path and stamps every entry with a new
+// session id and the current user, host and profile. Records are signed with
+// key unless it is nil.
+func NewLog(path, profile string, key []byte) *Log {
+	log := &Log{path: path, key: key, profile: profile}
+
+	id := make([]byte, 8)
+	if _, err := rand.Read(id); err == nil {
+		log.session = hex.EncodeToString(id)
+	}
+	if current, err := user.Current(); err == nil {
+		log.user = current.Username
+	}
+	log.host, _ = os.Hostname()
+
+	return log
+}
+
+func (l *Log) Path() string {
+	return l.path
+}
+
+// Append links entry to the end of the chain and writes it. The file is
+// locked while appending so concurrent sessions do not fork the chain.
+func (l *Log) Append(entry Entry) (Record, error) {
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o700); err != nil {
+		return Record{}, fmt.Errorf("failed to create audit directory: %w", err)
+	}
+
+	file, err := os.OpenFile(l.path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o600)
+	if err != nil {
+		return Record{}, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	if err := syscall.Flock(int(file.Fd()), syscall.LOCK_EX); err != nil {
+		return Record{}, fmt.Errorf("failed to lock audit log: %w", err)
+	}
+	defer syscall.Flock(int(file.Fd()), syscall.LOCK_UN)
+
+	records, err := readRecords(file)
+	if err != nil {
+		return Record{}, err
+	}
+
+	entry.Seq = 1
+	entry.Prev = GenesisHash
+	if len(records) > 0 {
+		last := records[len(records)-1]
+		entry.Seq = last.Entry.Seq + 1
+		entry.Prev = last.Hash
+	}
+	entry.Session, entry.User, entry.Host, entry.Profile = l.session, l.user, l.host, l.profile
+
+	payload, err := json.Marshal(entry)
+	if err != nil {
+		return Record{}, fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+	record := line{Entry: payload, Hash: HashBytes(payload)}
+	if l.key != nil {
+		record.MAC = sign(l.key, record.Hash)
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		return Record{}, fmt.Errorf("failed to encode audit entry: %w", err)
+	}
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return Record{}, fmt.Errorf("failed to write audit log: %w", err)
+	}
+	if err := writeHead(l.path, l.key, entry.Seq, record.Hash); err != nil {
+		return Record{}, err
+	}
+
+	return Record{Entry: entry, Hash: record.Hash, Signed: record.MAC != ""}, nil
+}
+
+func headPath(path string) string {
+	return path + ".head"
+}
+
+// writeHead replaces the head file with the latest record's count and hash.
+func writeHead(path string, key []byte, seq int64, hash string) error {
+	h := head{Seq: seq, Hash: hash}
+	if key != nil {
+		h.MAC = sign(key, fmt.Sprintf("%d:%s", seq, hash))
+	}
+	data, err := json.Marshal(h)
+	if err != nil {
+		return fmt.Errorf("failed to encode audit head: %w", err)
+	}
+
+	tmp := headPath(path) + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0o600); err != nil {
+		return fmt.Errorf("failed to write audit head: %w", err)
+	}
+	if err := os.Rename(tmp, headPath(path)); err != nil {
+		return fmt.Errorf("failed to write audit head: %w", err)
+	}
+	return nil
+}
+
+// readHead returns the head of the log at path, or nil if it has none.
+func readHead(path string) (*head, error) {
+	data, err := os.ReadFile(headPath(path))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read audit head: %w", err)
+	}
+	var h head
+	if err := json.Unmarshal(data, &h); err != nil {
+		return nil, fmt.Errorf("%w: head file is corrupt: %v", ErrTampered, err)
+	}
+	return &h, nil
+}
+
+func sign(key []byte, message string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(message))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func validSignature(key []byte, message, signature string) bool {
+	expected, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(message))
+	return hmac.Equal(mac.Sum(nil), expected)
+}
+
+// Read returns every record in the log without checking the chain. A missing
+// log has no records.
+func Read(path string) ([]Record, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer file.Close()
+
+	return readRecords(file)
+}
+
+// Verify checks every record's hash, signature and link to the previous
+// record, and that the log ends at the recorded head. It returns the verified
+// records, or an error wrapping ErrTampered that names the first line that
+// does not check out. Signatures are checked with key; a signed log cannot
+// be verified without it.
+func Verify(path string, key []byte) ([]Record, error) {
+	h, err := readHead(path)
+	if err != nil {
+		return nil, err
+	}
+	if h != nil && h.MAC != "" {
+		if key == nil {
+			return nil, fmt.Errorf("the audit log is signed but its key is not available in the keyring or the encrypted file store")
+		}
+		if !validSignature(key, fmt.Sprintf("%d:%s", h.Seq, h.Hash), h.MAC) {
+			return nil, fmt.Errorf("%w: the head file's signature does not match", ErrTampered)
+		}
+	}
+
+	var records []Record
+	file, err := os.Open(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	if err == nil {
+		defer file.Close()
+
+		prev := GenesisHash
+		signed := false
+		err = scanLines(file, func(number int, raw line, entry Entry) error {
+			switch {
+			case HashBytes(raw.Entry) != raw.Hash:
+				return fmt.Errorf("%w: line %d: entry does not match its hash", ErrTampered, number)
+			case entry.Prev != prev:
+				return fmt.Errorf("%w: line %d: entry does not follow the previous one", ErrTampered, number)
+			case entry.Seq != int64(len(records)+1):
+				return fmt.Errorf("%w: line %d: expected sequence %d, found %d", ErrTampered, number, len(records)+1, entry.Seq)
+			case raw.MAC == "" && signed:
+				return fmt.Errorf("%w: line %d: entry is not signed but earlier ones are", ErrTampered, number)
+			case raw.MAC != "" && key == nil:
+				return fmt.Errorf("the audit log is signed but its key is not available in the keyring or the encrypted file store")
+			case raw.MAC != "" && !validSignature(key, raw.Hash, raw.MAC):
+				return fmt.Errorf("%w: line %d: entry signature does not match", ErrTampered, number)
+			}
+			signed = signed || raw.MAC != ""
+			prev = raw.Hash
+			records = append(records, Record{Entry: entry, Hash: raw.Hash, Signed: raw.MAC != ""})
+			return nil
+		})
+		if err != nil {
+			return records, err
+		}
+	}
+
+	count := int64(len(records))
+	switch {
+	case h == nil && count > 0 && records[count-1].Signed:
+		return records, fmt.Errorf("%w: the head file is missing", ErrTampered)
+	case h == nil:
+		return records, nil
+	case h.MAC == "" && count > 0 && records[count-1].Signed:
+		return records, fmt.Errorf("%w: the head file is not signed", ErrTampered)
+	case h.Seq > count:
+		return records, fmt.Errorf("%w: the log has been truncated, it ends at entry %d but %d were written", ErrTampered, count, h.Seq)
+	case h.Seq < count || h.Hash != records[count-1].Hash:
+		return records, fmt.Errorf("%w: the latest entry does not match the head file", ErrTampered)
+	}
+
+	return records, nil
+}
+
+func readRecords(r io.Reader) ([]Record, error) {
+	var records []Record
+	err := scanLines(r, func(number int, raw line, entry Entry) error {
+		records = append(records, Record{Entry: entry, Hash: raw.Hash})
+		return nil
+	})
+	return records, err
+}
+
+func scanLines(r io.Reader, fn func(number int, raw line, entry Entry) error) error {
+	reader := bufio.NewReader(r)
+	for number := 1; ; number++ {
+		data, err := reader.ReadBytes('\n')
+		if len(bytes.TrimSpace(data)) > 0 {
+			var raw line
+			if jsonErr := json.Unmarshal(data, &raw); jsonErr != nil {
+				return fmt.Errorf("%w: line %d: %v", ErrTampered, number, jsonErr)
+			}
+			var entry Entry
+			if jsonErr := json.Unmarshal(raw.Entry, &entry); jsonErr != nil {
+				return fmt.Errorf("%w: line %d: %v", ErrTampered, number, jsonErr)
+			}
+			if fnErr := fn(number, raw, entry); fnErr != nil {
+				return fnErr
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read audit log: %w", err)
+		}
+	}
+}
+
+func HashBytes(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// HashFile returns the SHA-256 of the file at path, or "" if it does not
+// exist or cannot be read.
+func HashFile(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return HashBytes(data)
+}
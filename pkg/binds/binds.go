@@ -0,0 +1,349 @@
+// Package binds collects the keybindings of a Hyprland config and finds
+// duplicated, conflicting and broken ones.
+package binds
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/saat-sy/hyprlander/pkg/hyprlang"
+	"github.com/saat-sy/hyprlander/pkg/schema"
+)
+
+const globalSubmap = "global"
+
+// bindFlags are the letters that may follow "bind", see the Binds page.
+const bindFlags = "lroenmtisdp"
+
+type Bind struct {
+	File string
+	Line int
+	// Flags are the letters after "bind", e.g. "el" for bindel.
+	Flags       string
+	Mods        []string
+	Key         string
+	Description string
+	Dispatcher  string
+	Args        string
+	Submap      string
+}
+
+func (b Bind) HasFlag(flag byte) bool {
+	return strings.IndexByte(b.Flags, flag) >= 0
+}
+
+// Combo is the key combination, e.g. SUPER+SHIFT+Q.
+func (b Bind) Combo() string {
+	return FormatCombo(b.Mods, b.Key)
+}
+
+func (b Bind) Action() string {
+	if b.Args == "" {
+		return b.Dispatcher
+	}
+	return b.Dispatcher + " " + b.Args
+}
+
+func (b Bind) Location() string {
+	return fmt.Sprintf("%s:%d", filepath.Base(b.File), b.Line)
+}
+
+// trigger identifies when a bind fires. Release and mouse binds fire on
+// different events than plain binds on the same keys.
+func (b Bind) trigger() string {
+	return strings.Join([]string{
+		b.Submap,
+		strings.Join(b.Mods, "+"),
+		strings.ToLower(b.Key),
+		fmt.Sprint(b.HasFlag('r')),
+		fmt.Sprint(b.HasFlag('m')),
+	}, "|")
+}
+
+type IssueKind string
+
+const (
+	Duplicate         IssueKind = "duplicate"
+	Conflict          IssueKind = "conflict"
+	UnknownDispatcher IssueKind = "unknown dispatcher"
+	Malformed         IssueKind = "malformed"
+)
+
+type Issue struct {
+	Kind IssueKind
+	File string
+	Line int
+	// Other is the earlier bind a duplicate or conflict collides with.
+	Other   *Bind
+	Message string
+}
+
+func (i Issue) String() string {
+	return fmt.Sprintf("%s:%d: %s: %s", i.File, i.Line, i.Kind, i.Message)
+}
+
+type Inventory struct {
+	Binds  []Bind
+	Issues []Issue
+}
+
+// Load reads the binds of the config rooted at mainPath, following source
+// lines.
+func Load(mainPath string) (*Inventory, error) {
+	cfg, err := hyprlang.LoadConfig(mainPath)
+	if err != nil {
+		return nil, err
+	}
+	return Collect(cfg), nil
+}
+
+// Collect gathers binds in the order Hyprland reads them, tracking submaps
+// and unbind lines, and reports problems along the way.
+func Collect(cfg *hyprlang.Config) *Inventory {
+	inventory := &Inventory{}
+	submap := globalSubmap
+	active := make(map[string]int)
+
+	for _, line := range cfg.Lines {
+		if line.Kind != hyprlang.Assignment || len(line.Category) > 0 {
+			continue
+		}
+		value := cfg.Expand(line.Value)
+
+		switch {
+		case line.Key == "submap":
+			submap = strings.TrimSpace(value)
+			if submap == "reset" || submap == "" {
+				submap = globalSubmap
+			}
+		case line.Key == "unbind":
+			mods, key, err := ParseCombo(value)
+			if err != nil {
+				inventory.addIssue(Malformed, line, nil, err.Error())
+				continue
+			}
+			// unbind removes the combo whatever flags it was bound with.
+			for _, flags := range []string{"", "r", "m", "rm"} {
+				probe := Bind{Flags: flags, Mods: mods, Key: key, Submap: submap}
+				delete(active, probe.trigger())
+			}
+		case strings.HasPrefix(line.Key, "bind"):
+			bind, err := parseBind(line, value, submap)
+			if err != nil {
+				inventory.addIssue(Malformed, line, nil, err.Error())
+				continue
+			}
+			inventory.check(bind, line, active)
+			active[bind.trigger()] = len(inventory.Binds)
+			inventory.Binds = append(inventory.Binds, bind)
+		}
+	}
+
+	return inventory
+}
+
+func (inv *Inventory) check(bind Bind, line hyprlang.ConfigLine, active map[string]int) {
+	if !schema.IsDispatcher(bind.Dispatcher, bind.HasFlag('m')) {
+		inv.addIssue(UnknownDispatcher, line, nil, fmt.Sprintf("%s binds to %q, which is not a known dispatcher", bind.Combo(), bind.Dispatcher))
+	}
+
+	index, ok := active[bind.trigger()]
+	if !ok {
+		return
+	}
+	other := inv.Binds[index]
+	if strings.EqualFold(other.Action(), bind.Action()) {
+		inv.addIssue(Duplicate, line, &other, fmt.Sprintf("%s %s is already bound at %s", bind.Combo(), bind.Action(), other.Location()))
+		return
+	}
+	inv.addIssue(Conflict, line, &other, fmt.Sprintf("%s runs %s, but it also runs %s from %s", bind.Combo(), bind.Action(), other.Action(), other.Location()))
+}
+
+func (inv *Inventory) addIssue(kind IssueKind, line hyprlang.ConfigLine, other *Bind, message string) {
+	inv.Issues = append(inv.Issues, Issue{Kind: kind, File: line.File, Line: line.Number, Other: other, Message: message})
+}
+
+func parseBind(line hyprlang.ConfigLine, value, submap string) (Bind, error) {
+	bind := Bind{File: line.File, Line: line.Number, Flags: strings.TrimPrefix(line.Key, "bind"), Submap: submap}
+	for _, flag := range bind.Flags {
+		if !strings.ContainsRune(bindFlags, flag) {
+			return Bind{}, fmt.Errorf("%s has unknown flag %q", line.Key, flag)
+		}
+	}
+
+	fields := 4
+	if bind.HasFlag('d') {
+		fields = 5
+	}
+	parts := strings.SplitN(value, ",", fields)
+	for i := range parts {
+		parts[i] = strings.TrimSpace(parts[i])
+	}
+	if len(parts) < fields-1 {
+		return Bind{}, fmt.Errorf("%s needs at least %d comma separated fields, found %d", line.Key, fields-1, len(parts))
+	}
+
+	bind.Mods = ParseMods(parts[0])
+	bind.Key = parts[1]
+	if bind.Key == "" {
+		return Bind{}, fmt.Errorf("%s has no key", line.Key)
+	}
+	rest := parts[2:]
+	if bind.HasFlag('d') {
+		bind.Description, rest = rest[0], rest[1:]
+	}
+	bind.Dispatcher = rest[0]
+	if len(rest) > 1 {
+		bind.Args = rest[1]
+	}
+
+	return bind, nil
+}
+
+// modifiers maps each modifier to its spellings, in display order.
+var modifiers = []struct {
+	name    string
+	aliases []string
+}{
+	{"SUPER", []string{"SUPER", "WIN", "LOGO", "MOD4"}},
+	{"CTRL", []string{"CTRL", "CONTROL"}},
+	{"ALT", []string{"ALT"}},
+	{"SHIFT", []string{"SHIFT"}},
+	{"CAPS", []string{"CAPS"}},
+	{"MOD2", []string{"MOD2"}},
+	{"MOD3", []string{"MOD3"}},
+	{"MOD5", []string{"MOD5"}},
+}
+
+// ParseMods normalizes a modifier string. Like Hyprland it looks for each
+// modifier name anywhere in the string, so SUPER_SHIFT, SUPER SHIFT and
+// SUPERSHIFT are the same.
+func ParseMods(value string) []string {
+	value = strings.ToUpper(value)
+	var mods []string
+	for _, modifier := range modifiers {
+		for _, alias := range modifier.aliases {
+			if strings.Contains(value, alias) {
+				mods = append(mods, modifier.name)
+				break
+			}
+		}
+	}
+	return mods
+}
+
+// ParseCombo reads a key combination written either like a bind
+// ("SUPER SHIFT, Q") or as a shortcut ("SUPER+SHIFT+Q").
+func ParseCombo(value string) ([]string, string, error) {
+	var mods, key string
+	if before, after, ok := strings.Cut(value, ","); ok {
+		mods, key = before, after
+		if i := strings.Index(key, ","); i >= 0 {
+			key = key[:i]
+		}
+	} else {
+		parts := strings.Split(value, "+")
+		key = parts[len(parts)-1]
+		mods = strings.Join(parts[:len(parts)-1], " ")
+	}
+
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return nil, "", fmt.Errorf("%q has no key", value)
+	}
+	return ParseMods(mods), key, nil
+}
+
+func FormatCombo(mods []string, key string) string {
+	return strings.Join(append(append([]string(nil), mods...), key), "+")
+}
+
+// Find returns the binds on a key combination in a submap, ignoring flags.
+func (inv *Inventory) Find(mods []string, key, submap string) []Bind {
+	if submap == "" {
+		submap = globalSubmap
+	}
+	combo := FormatCombo(mods, key)
+	var found []Bind
+	for _, bind := range inv.Binds {
+		if bind.Submap == submap && strings.EqualFold(bind.Combo(), combo) {
+			found = append(found, bind)
+		}
+	}
+	return found
+}
+
+// Format lists the binds grouped by submap and modifiers. File paths are
+// shown relative to root.
+func (inv *Inventory) Format(root string) string {
+	groups := make(map[string]map[string][]Bind)
+	for _, bind := range inv.Binds {
+		mods := strings.Join(bind.Mods, "+")
+		if mods == "" {
+			mods = "(no modifier)"
+		}
+		if groups[bind.Submap] == nil {
+			groups[bind.Submap] = make(map[string][]Bind)
+		}
+		groups[bind.Submap][mods] = append(groups[bind.Submap][mods], bind)
+	}
+
+	var builder strings.Builder
+	for _, submap := range sortedKeys(groups, globalSubmap) {
+		fmt.Fprintf(&builder, "submap %s\n", submap)
+		for _, mods := range sortedKeys(groups[submap], "") {
+			fmt.Fprintf(&builder, "  %s\n", mods)
+			group := groups[submap][mods]
+			sort.SliceStable(group, func(i, j int) bool {
+				return strings.ToLower(group[i].Key) < strings.ToLower(group[j].Key)
+			})
+			for _, bind := range group {
+				line := fmt.Sprintf("    %-16s %s", bind.Key, bind.Action())
+				if bind.Flags != "" {
+					line += fmt.Sprintf(" [%s]", bind.Flags)
+				}
+				if bind.Description != "" {
+					line += " - " + bind.Description
+				}
+				fmt.Fprintf(&builder, "%s  (%s:%d)\n", line, relative(root, bind.File), bind.Line)
+			}
+		}
+	}
+
+	return strings.TrimRight(builder.String(), "\n")
+}
+
+func FormatIssues(issues []Issue, root string) string {
+	lines := make([]string, len(issues))
+	for i, issue := range issues {
+		lines[i] = fmt.Sprintf("%s:%d: %s: %s", relative(root, issue.File), issue.Line, issue.Kind, issue.Message)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func relative(root, path string) string {
+	if root == "" {
+		return path
+	}
+	if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
+
+// sortedKeys sorts map keys alphabetically with first, if present, in front.
+func sortedKeys[V any](m map[string]V, first string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i] == first || keys[j] == first {
+			return keys[i] == first && keys[j] != first
+		}
+		return keys[i] < keys[j]
+	})
+	return keys
+}
@@ -0,0 +1,185 @@
+// Package color parses the color formats Hyprland accepts, converts them to
+// hex, HSL and OKLCH, and adjusts them so color changes are computed rather
+// than guessed.
+package color
+
+import (
+	"fmt"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Color is a straight (not premultiplied) sRGB color with components in 0-1.
+type Color struct {
+	R, G, B, A float64
+}
+
+var (
+	hexFuncPattern = regexp.MustCompile(`^(?i)(rgba|rgb)\(([0-9a-f]+)\)$`)
+	legacyPattern  = regexp.MustCompile(`^(?i)0x([0-9a-f]{8})$`)
+	funcPattern    = regexp.MustCompile(`^(?i)(rgba?)\(([^)]*)\)$`)
+	cssHexPattern  = regexp.MustCompile(`^(?i)#([0-9a-f]{6}|[0-9a-f]{8})$`)
+)
+
+// Parse reads a color in one of the formats Hyprland accepts: rgba(RRGGBBAA),
+// rgb(RRGGBB), 0xAARRGGBB, rgba(r, g, b, a) and rgb(r, g, b).
+func Parse(value string) (Color, error) {
+	value = strings.TrimSpace(value)
+
+	if match := hexFuncPattern.FindStringSubmatch(value); match != nil {
+		want := 6
+		if strings.EqualFold(match[1], "rgba") {
+			want = 8
+		}
+		if len(match[2]) == want {
+			return fromHex(match[2]), nil
+		}
+	}
+
+	if match := legacyPattern.FindStringSubmatch(value); match != nil {
+		// 0xAARRGGBB puts the alpha first.
+		return fromHex(match[1][2:] + match[1][:2]), nil
+	}
+
+	if match := funcPattern.FindStringSubmatch(value); match != nil {
+		parts := strings.Split(match[2], ",")
+		want := 3
+		if strings.EqualFold(match[1], "rgba") {
+			want = 4
+		}
+		if len(parts) == want {
+			components := []float64{0, 0, 0, 1}
+			valid := true
+			for i, part := range parts {
+				number, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+				if err != nil || number < 0 || (i < 3 && number > 255) || (i == 3 && number > 1) {
+					valid = false
+					break
+				}
+				if i < 3 {
+					number /= 255
+				}
+				components[i] = number
+			}
+			if valid {
+				return Color{components[0], components[1], components[2], components[3]}, nil
+			}
+		}
+	}
+
+	return Color{}, fmt.Errorf("%q is not a color, expected e.g. rgba(33ccffee), rgb(33ccff) or 0xee33ccff", value)
+}
+
+// ParseAny also accepts CSS hex colors (#RRGGBB or #RRGGBBAA), which is what
+// people and other programs usually write.
+func ParseAny(value string) (Color, error) {
+	if match := cssHexPattern.FindStringSubmatch(strings.TrimSpace(value)); match != nil {
+		if len(match[1]) == 6 {
+			return fromHex(match[1] + "ff"), nil
+		}
+		return fromHex(match[1]), nil
+	}
+	return Parse(value)
+}
+
+// fromHex reads RRGGBB or RRGGBBAA.
+func fromHex(hex string) Color {
+	component := func(i int) float64 {
+		value, _ := strconv.ParseUint(hex[i:i+2], 16, 8)
+		return float64(value) / 255
+	}
+	c := Color{R: component(0), G: component(2), B: component(4), A: 1}
+	if len(hex) == 8 {
+		c.A = component(6)
+	}
+	return c
+}
+
+// String returns the color as rgba(RRGGBBAA), the form hyprlander writes.
+func (c Color) String() string {
+	return fmt.Sprintf("rgba(%02x%02x%02x%02x)", to8(c.R), to8(c.G), to8(c.B), to8(c.A))
+}
+
+// Hex returns #RRGGBB, or #RRGGBBAA for translucent colors.
+func (c Color) Hex() string {
+	if to8(c.A) == 255 {
+		return fmt.Sprintf("#%02x%02x%02x", to8(c.R), to8(c.G), to8(c.B))
+	}
+	return fmt.Sprintf("#%02x%02x%02x%02x", to8(c.R), to8(c.G), to8(c.B), to8(c.A))
+}
+
+func to8(component float64) uint8 {
+	return uint8(math.Round(clamp01(component) * 255))
+}
+
+func clamp01(value float64) float64 {
+	return math.Max(0, math.Min(1, value))
+}
+
+func (c Color) WithAlpha(alpha float64) Color {
+	c.A = clamp01(alpha)
+	return c
+}
+
+// Lighten moves the HSL lightness the given fraction of the way to white, so
+// Lighten(0.2) is "20% lighter".
+func (c Color) Lighten(amount float64) Color {
+	h, s, l := c.HSL()
+	return FromHSL(h, s, l+(1-l)*amount, c.A)
+}
+
+// Darken scales the HSL lightness down by the given fraction.
+func (c Color) Darken(amount float64) Color {
+	h, s, l := c.HSL()
+	return FromHSL(h, s, l*(1-amount), c.A)
+}
+
+func (c Color) Saturate(amount float64) Color {
+	h, s, l := c.HSL()
+	return FromHSL(h, s+(1-s)*amount, l, c.A)
+}
+
+func (c Color) Desaturate(amount float64) Color {
+	h, s, l := c.HSL()
+	return FromHSL(h, s*(1-amount), l, c.A)
+}
+
+func (c Color) RotateHue(degrees float64) Color {
+	h, s, l := c.HSL()
+	return FromHSL(h+degrees, s, l, c.A)
+}
+
+// Mix blends a and b in sRGB; weight 0 is a and 1 is b.
+func Mix(a, b Color, weight float64) Color {
+	mix := func(x, y float64) float64 { return x + (y-x)*weight }
+	return Color{mix(a.R, b.R), mix(a.G, b.G), mix(a.B, b.B), mix(a.A, b.A)}
+}
+
+// Over composites c on top of background.
+func (c Color) Over(background Color) Color {
+	alpha := c.A + background.A*(1-c.A)
+	if alpha == 0 {
+		return Color{}
+	}
+	blend := func(x, y float64) float64 { return (x*c.A + y*background.A*(1-c.A)) / alpha }
+	return Color{blend(c.R, background.R), blend(c.G, background.G), blend(c.B, background.B), alpha}
+}
+
+// Luminance is the WCAG relative luminance.
+func (c Color) Luminance() float64 {
+	return 0.2126*linear(c.R) + 0.7152*linear(c.G) + 0.0722*linear(c.B)
+}
+
+// ContrastRatio is the WCAG contrast ratio, from 1 to 21. A translucent
+// foreground is composited over the background first.
+func ContrastRatio(foreground, background Color) float64 {
+	background = background.WithAlpha(1)
+	foreground = foreground.Over(background)
+	lighter, darker := foreground.Luminance(), background.Luminance()
+	if darker > lighter {
+		lighter, darker = darker, lighter
+	}
+	return (lighter + 0.05) / (darker + 0.05)
+}
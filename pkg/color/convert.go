@@ -0,0 +1,125 @@
+package color
+
+import (
+	"fmt"
+	"math"
+)
+
+// HSL returns hue in degrees and saturation and lightness in 0-1.
+func (c Color) HSL() (float64, float64, float64) {
+	maxValue := math.Max(c.R, math.Max(c.G, c.B))
+	minValue := math.Min(c.R, math.Min(c.G, c.B))
+	l := (maxValue + minValue) / 2
+	if maxValue == minValue {
+		return 0, 0, l
+	}
+
+	d := maxValue - minValue
+	s := d / (1 - math.Abs(2*l-1))
+	var h float64
+	switch maxValue {
+	case c.R:
+		h = math.Mod((c.G-c.B)/d, 6)
+	case c.G:
+		h = (c.B-c.R)/d + 2
+	default:
+		h = (c.R-c.G)/d + 4
+	}
+	return normalizeHue(h * 60), s, l
+}
+
+func FromHSL(h, s, l, alpha float64) Color {
+	h, s, l = normalizeHue(h), clamp01(s), clamp01(l)
+	chroma := (1 - math.Abs(2*l-1)) * s
+	x := chroma * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	m := l - chroma/2
+
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g, b = chroma, x, 0
+	case h < 120:
+		r, g, b = x, chroma, 0
+	case h < 180:
+		r, g, b = 0, chroma, x
+	case h < 240:
+		r, g, b = 0, x, chroma
+	case h < 300:
+		r, g, b = x, 0, chroma
+	default:
+		r, g, b = chroma, 0, x
+	}
+	return Color{r + m, g + m, b + m, clamp01(alpha)}
+}
+
+// OKLCH returns perceptual lightness (0-1), chroma and hue in degrees.
+func (c Color) OKLCH() (float64, float64, float64) {
+	r, g, b := linear(c.R), linear(c.G), linear(c.B)
+
+	l := math.Cbrt(0.4122214708*r + 0.5363325363*g + 0.0514459929*b)
+	m := math.Cbrt(0.2119034982*r + 0.6806995451*g + 0.1073969566*b)
+	s := math.Cbrt(0.0883024619*r + 0.2817188376*g + 0.6299787005*b)
+
+	okL := 0.2104542553*l + 0.7936177850*m - 0.0040720468*s
+	okA := 1.9779984951*l - 2.4285922050*m + 0.4505937099*s
+	okB := 0.0259040371*l + 0.7827717662*m - 0.8086757660*s
+
+	chroma := math.Hypot(okA, okB)
+	hue := 0.0
+	if chroma > 1e-6 {
+		hue = normalizeHue(math.Atan2(okB, okA) * 180 / math.Pi)
+	}
+	return okL, chroma, hue
+}
+
+// FromOKLCH converts back to sRGB. Colors outside the sRGB gamut are clipped.
+func FromOKLCH(lightness, chroma, hue, alpha float64) Color {
+	radians := hue * math.Pi / 180
+	okA, okB := chroma*math.Cos(radians), chroma*math.Sin(radians)
+
+	l := lightness + 0.3963377774*okA + 0.2158037573*okB
+	m := lightness - 0.1055613458*okA - 0.0638541728*okB
+	s := lightness - 0.0894841775*okA - 1.2914855480*okB
+	l, m, s = l*l*l, m*m*m, s*s*s
+
+	r := 4.0767416621*l - 3.3077115913*m + 0.2309699292*s
+	g := -1.2684380046*l + 2.6097574011*m - 0.3413193965*s
+	b := -0.0041960863*l - 0.7034186147*m + 1.7076147010*s
+
+	return Color{clamp01(gamma(r)), clamp01(gamma(g)), clamp01(gamma(b)), clamp01(alpha)}
+}
+
+// FormatHSL and FormatOKLCH print the CSS notation of a color.
+func (c Color) FormatHSL() string {
+	h, s, l := c.HSL()
+	return fmt.Sprintf("hsl(%.0f %.0f%% %.0f%%)", h, s*100, l*100)
+}
+
+func (c Color) FormatOKLCH() string {
+	l, chroma, h := c.OKLCH()
+	return fmt.Sprintf("oklch(%.1f%% %.3f %.0f)", l*100, chroma, h)
+}
+
+// linear converts an sRGB component to linear light.
+func linear(component float64) float64 {
+	if component <= 0.04045 {
+		return component / 12.92
+	}
+	return math.Pow((component+0.055)/1.055, 2.4)
+}
+
+// gamma converts a linear component back to sRGB.
+func gamma(component float64) float64 {
+	if component <= 0.0031308 {
+		return component * 12.92
+	}
+	return 1.055*math.Pow(component, 1/2.4) - 0.055
+}
+
+func normalizeHue(hue float64) float64 {
+	hue = math.Mod(hue, 360)
+	if hue < 0 {
+		hue += 360
+	}
+	return hue
+}
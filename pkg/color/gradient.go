@@ -0,0 +1,85 @@
+package color
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+var anglePattern = regexp.MustCompile(`^-?\d+(\.\d+)?deg$`)
+
+// Gradient is a value such as col.active_border: one or more colors and an
+// optional angle like "45deg".
+type Gradient struct {
+	Colors []Color
+	Angle  string
+}
+
+// ParseGradient reads a gradient. Spaces inside rgba(...) do not separate
+// colors.
+func ParseGradient(value string) (Gradient, error) {
+	tokens := splitTokens(value)
+
+	var gradient Gradient
+	if len(tokens) > 0 && anglePattern.MatchString(tokens[len(tokens)-1]) {
+		gradient.Angle = tokens[len(tokens)-1]
+		tokens = tokens[:len(tokens)-1]
+	}
+	if len(tokens) == 0 {
+		return Gradient{}, fmt.Errorf("expected one or more colors, optionally followed by an angle such as 45deg")
+	}
+
+	for _, token := range tokens {
+		c, err := Parse(token)
+		if err != nil {
+			return Gradient{}, fmt.Errorf("%q is not a color, expected e.g. rgba(33ccffee) rgba(00ff99ee) 45deg", token)
+		}
+		gradient.Colors = append(gradient.Colors, c)
+	}
+	return gradient, nil
+}
+
+func splitTokens(value string) []string {
+	var tokens []string
+	var current strings.Builder
+	depth := 0
+	for _, r := range value {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case (r == ' ' || r == '\t') && depth == 0:
+			if current.Len() > 0 {
+				tokens = append(tokens, current.String())
+				current.Reset()
+			}
+			continue
+		}
+		current.WriteRune(r)
+	}
+	if current.Len() > 0 {
+		tokens = append(tokens, current.String())
+	}
+	return tokens
+}
+
+// Map applies fn to every color and keeps the angle.
+func (g Gradient) Map(fn func(Color) Color) Gradient {
+	mapped := Gradient{Angle: g.Angle, Colors: make([]Color, len(g.Colors))}
+	for i, c := range g.Colors {
+		mapped.Colors[i] = fn(c)
+	}
+	return mapped
+}
+
+func (g Gradient) String() string {
+	parts := make([]string, 0, len(g.Colors)+1)
+	for _, c := range g.Colors {
+		parts = append(parts, c.String())
+	}
+	if g.Angle != "" {
+		parts = append(parts, g.Angle)
+	}
+	return strings.Join(parts, " ")
+}
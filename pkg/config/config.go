@@ -1,10 +1,28 @@
 package config
 
 const (
-	AppName         = ".hyprlander"
-	SecretFileName  = "secrets.ini"
-	APIKeyName      = "API_KEY"
-	HyprlandDirName = "HYPRLAND_DIR"
-	MaxTurns        = 10
-	GeminiModel     = "gemini-2.5-flash"
+	AppName            = ".hyprlander"
+	SecretFileName     = "secrets.ini"
+	APIKeyName         = "API_KEY"
+	HyprlandDirName    = "HYPRLAND_DIR"
+	IgnoreFileName     = ".hyprlanderignore"
+	MainConfigFileName = "hyprland.conf"
+	SettingsDirName    = "hyprlander"
+	SettingsFileName   = "config.toml"
+	DefaultMaxTurns    = 10
+	DefaultModel       = "gemini-2.5-flash"
+	KeyringService     = "hyprlander"
+	DefaultProfile     = "default"
+	AuditDirName       = "audit"
+	AuditFileName      = "audit.log"
+	AuditKeyName       = "AUDIT_KEY"
+	SnapshotsDirName   = "snapshots"
+
+	EnvModel        = "HYPRLANDER_MODEL"
+	EnvMaxTurns     = "HYPRLANDER_MAX_TURNS"
+	EnvAssumeYes    = "HYPRLANDER_YES"
+	EnvHyprlandDir  = "HYPRLANDER_DIR"
+	EnvProfile      = "HYPRLANDER_PROFILE"
+	EnvAPIKey       = "HYPRLANDER_API_KEY"
+	EnvGeminiAPIKey = "GEMINI_API_KEY"
 )
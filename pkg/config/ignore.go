@@ -0,0 +1,153 @@
+package config
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var defaultIgnorePatterns = []string{
+	".git/",
+	IgnoreFileName,
+	"node_modules/",
+	"__pycache__/",
+	"*.png",
+	"*.jpg",
+	"*.jpeg",
+	"*.gif",
+	"*.webp",
+	"*.bmp",
+	"*.svg",
+	"*.mp4",
+	"*.bak",
+	"*.orig",
+	"*.swp",
+	"*~",
+}
+
+type ignorePattern struct {
+	regex   *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+type IgnoreMatcher struct {
+	patterns []ignorePattern
+}
+
+// LoadIgnore builds a matcher from the built-in defaults followed by the rules
+// in root/.hyprlanderignore, if present. Later rules win, as in gitignore.
+func LoadIgnore(root string) (*IgnoreMatcher, error) {
+	matcher := &IgnoreMatcher{}
+	for _, pattern := range defaultIgnorePatterns {
+		matcher.Add(pattern)
+	}
+
+	file, err := os.Open(filepath.Join(root, IgnoreFileName))
+	if os.IsNotExist(err) {
+		return matcher, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		matcher.Add(scanner.Text())
+	}
+
+	return matcher, scanner.Err()
+}
+
+// Add appends a single gitignore-style rule. Blank lines and comments are ignored.
+func (m *IgnoreMatcher) Add(rule string) {
+	rule = strings.TrimRight(rule, " \t\r")
+	if rule == "" || strings.HasPrefix(rule, "#") {
+		return
+	}
+
+	pattern := ignorePattern{}
+	if strings.HasPrefix(rule, "!") {
+		pattern.negate = true
+		rule = rule[1:]
+	}
+	rule = strings.TrimPrefix(rule, "\\")
+
+	if strings.HasSuffix(rule, "/") {
+		pattern.dirOnly = true
+		rule = strings.TrimSuffix(rule, "/")
+	}
+
+	anchored := strings.Contains(rule, "/")
+	rule = strings.TrimPrefix(rule, "/")
+
+	expr := globToRegexp(rule)
+	if anchored {
+		expr = "^" + expr + "$"
+	} else {
+		expr = "(^|/)" + expr + "$"
+	}
+
+	regex, err := regexp.Compile(expr)
+	if err != nil {
+		return
+	}
+	pattern.regex = regex
+	m.patterns = append(m.patterns, pattern)
+}
+
+// Match reports whether relPath (slash separated, relative to the root) is ignored.
+func (m *IgnoreMatcher) Match(relPath string, isDir bool) bool {
+	relPath = filepath.ToSlash(relPath)
+	ignored := false
+	for _, pattern := range m.patterns {
+		if pattern.dirOnly && !isDir {
+			continue
+		}
+		if pattern.regex.MatchString(relPath) {
+			ignored = !pattern.negate
+		}
+	}
+	return ignored
+}
+
+func globToRegexp(glob string) string {
+	var expr strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				i++
+				if i+1 < len(glob) && glob[i+1] == '/' {
+					i++
+					expr.WriteString("(.*/)?")
+				} else {
+					expr.WriteString(".*")
+				}
+			} else {
+				expr.WriteString("[^/]*")
+			}
+		case '?':
+			expr.WriteString("[^/]")
+		case '[':
+			end := strings.IndexByte(glob[i:], ']')
+			if end < 0 {
+				expr.WriteString(regexp.QuoteMeta(string(c)))
+				continue
+			}
+			class := glob[i+1 : i+end]
+			if strings.HasPrefix(class, "!") {
+				class = "^" + class[1:]
+			}
+			expr.WriteString("[" + class + "]")
+			i += end
+		default:
+			expr.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return expr.String()
+}
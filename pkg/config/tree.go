@@ -0,0 +1,193 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/saat-sy/hyprlander/pkg/hyprlang"
+)
+
+const binarySniffLength = 8000
+
+type TreeEntry struct {
+	Path string
+	Size int64
+	Role string
+}
+
+func (e TreeEntry) String() string {
+	if e.Role == "" {
+		return fmt.Sprintf("%s (%s)", e.Path, FormatSize(e.Size))
+	}
+	return fmt.Sprintf("%s (%s) - %s", e.Path, FormatSize(e.Size), e.Role)
+}
+
+// GetTreeFromDir lists the text files under root that are not excluded by the
+// ignore rules, annotated with their size and role in the Hyprland config.
+func GetTreeFromDir(root string) ([]TreeEntry, error) {
+	matcher, err := LoadIgnore(root)
+	if err != nil {
+		return nil, fmt.Errorf("could not load ignore rules: %w", err)
+	}
+
+	var entries []TreeEntry
+
+	err = filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		relPath, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		if relPath == "." {
+			return nil
+		}
+
+		if matcher.Match(relPath, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		binary, err := IsBinaryFile(path)
+		if err != nil || binary {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		absPath, err := filepath.Abs(path)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, TreeEntry{Path: absPath, Size: info.Size()})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	annotateRoles(root, entries)
+	return entries, nil
+}
+
+// IsBinaryFile sniffs the start of a file for NUL bytes or invalid UTF-8.
+func IsBinaryFile(path string) (bool, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer file.Close()
+
+	buf := make([]byte, binarySniffLength)
+	n, err := io.ReadFull(file, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return false, err
+	}
+	buf = buf[:n]
+
+	if bytes.IndexByte(buf, 0) >= 0 {
+		return true, nil
+	}
+	// Allow a rune to be cut off at the end of the sniffed window.
+	if n == binarySniffLength && n > utf8.UTFMax {
+		buf = buf[:n-utf8.UTFMax]
+	}
+	return !utf8.Valid(buf), nil
+}
+
+func FormatSize(size int64) string {
+	switch {
+	case size < 1024:
+		return fmt.Sprintf("%d B", size)
+	case size < 1024*1024:
+		return fmt.Sprintf("%.1f KB", float64(size)/1024)
+	default:
+		return fmt.Sprintf("%.1f MB", float64(size)/(1024*1024))
+	}
+}
+
+// RoleNotSourced marks .conf files that neither the main config nor the
+// other hypr configs reach.
+const RoleNotSourced = "not sourced"
+
+var knownConfigRoles = map[string]string{
+	MainConfigFileName: "main config",
+	"hyprpaper.conf":   "hyprpaper config",
+	"hyprlock.conf":    "hyprlock config",
+	"hypridle.conf":    "hypridle config",
+	"hyprsunset.conf":  "hyprsunset config",
+}
+
+func annotateRoles(root string, entries []TreeEntry) {
+	includedBy := make(map[string]string)
+
+	// Sources are followed from the main config and from the configs of the
+	// other hypr programs, which may source files of their own.
+	mainConfig, err := filepath.Abs(filepath.Join(root, MainConfigFileName))
+	if err == nil {
+		queue := []string{mainConfig}
+		visited := map[string]bool{mainConfig: true}
+		for name := range knownConfigRoles {
+			path := filepath.Join(filepath.Dir(mainConfig), name)
+			if !visited[path] {
+				queue = append(queue, path)
+				visited[path] = true
+			}
+		}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+
+			doc, err := hyprlang.ParseFile(current)
+			if err != nil {
+				continue
+			}
+			for _, target := range doc.SourceTargets() {
+				if visited[target] {
+					continue
+				}
+				visited[target] = true
+				includedBy[target] = current
+				queue = append(queue, target)
+			}
+		}
+	}
+
+	for i := range entries {
+		path := entries[i].Path
+		name := filepath.Base(path)
+
+		switch {
+		case includedBy[path] != "":
+			entries[i].Role = "sourced from " + relativeTo(root, includedBy[path])
+		case knownConfigRoles[name] != "" && filepath.Dir(path) == filepath.Dir(mainConfig):
+			entries[i].Role = knownConfigRoles[name]
+		case strings.HasSuffix(name, ".conf"):
+			entries[i].Role = RoleNotSourced
+		case strings.HasSuffix(name, ".sh"):
+			entries[i].Role = "script"
+		}
+	}
+}
+
+func relativeTo(root, path string) string {
+	if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
@@ -32,22 +32,38 @@ func GetSecretFilePath() (string, error) {
 	return filepath.Join(homeDir, SecretFileName), nil
 }
 
-func GetTreeFromDir(root string) ([]string, error) {
-	var files []string
-
-	err := filepath.WalkDir(root, func(path string, d os.DirEntry, err error) error {
-		if err != nil {
-			return err
-		}
-		if !d.IsDir() {
-			absPath, err := filepath.Abs(path)
-			if err != nil {
-				return err
-			}
-			files = append(files, absPath)
-		}
-		return nil
-	})
-
-	return files, err
+// GetSettingsDirectory follows the XDG base directory spec, falling back to ~/.config.
+func GetSettingsDirectory() (string, error) {
+	if xdgConfig := os.Getenv("XDG_CONFIG_HOME"); xdgConfig != "" {
+		return filepath.Join(xdgConfig, SettingsDirName), nil
+	}
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, ".config", SettingsDirName), nil
+}
+
+func GetSettingsFilePath() (string, error) {
+	settingsDir, err := GetSettingsDirectory()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(settingsDir, SettingsFileName), nil
+}
+
+func GetAuditFilePath() (string, error) {
+	homeDir, err := GetUserHomeDirectory()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, AuditDirName, AuditFileName), nil
+}
+
+func GetSnapshotsDirectory() (string, error) {
+	homeDir, err := GetUserHomeDirectory()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(homeDir, SnapshotsDirName), nil
 }
@@ -3,10 +3,13 @@ package agent
 import (
 	"context"
 	"fmt"
-	"log"
 
+	"github.com/saat-sy/hyprlander/pkg/audit"
 	"github.com/saat-sy/hyprlander/pkg/config"
 	"github.com/saat-sy/hyprlander/pkg/core/tools"
+	"github.com/saat-sy/hyprlander/pkg/daemon"
+	"github.com/saat-sy/hyprlander/pkg/secrets"
+	"github.com/saat-sy/hyprlander/pkg/settings"
 	"github.com/saat-sy/hyprlander/pkg/setup"
 	"github.com/saat-sy/hyprlander/pkg/ui"
 	"google.golang.org/genai"
@@ -17,23 +20,68 @@ type Agent struct {
 	chatSession *genai.Chat
 	history     []*genai.Content
 	maxTurns    int
+	hyprlandDir string
+	settings    *settings.Settings
+	options     Options
 	ui          ui.UI
+	usage       ui.Usage
+
+	// proposed holds the writes and shell commands captured in dry-run mode,
+	// and proposedFiles the content each captured write would leave on disk.
+	proposed      []*genai.FunctionCall
+	proposedFiles map[string]string
+
+	// sessionTools are the tools the user allowed for the rest of the session.
+	sessionTools map[string]bool
+
+	audit *audit.Log
+
+	daemons *daemon.Controller
 }
 
-const defaultMaxTurns = 10
+type Options struct {
+	// DryRun executes reads but only records writes and shell commands.
+	DryRun bool
+	// UI overrides the console UI when set.
+	UI ui.UI
+	// Runner overrides how restartDaemon runs commands when set.
+	Runner daemon.Runner
+}
 
-func NewAgent() *Agent {
+func NewAgent(s *settings.Settings, options Options) (*Agent, error) {
 	agent := &Agent{
-		context:  context.Background(),
-		maxTurns: defaultMaxTurns,
-		ui:       ui.New(),
+		context:       context.Background(),
+		maxTurns:      s.MaxTurns,
+		settings:      s,
+		options:       options,
+		ui:            options.UI,
+		proposedFiles: make(map[string]string),
+		sessionTools:  make(map[string]bool),
+	}
+	if agent.ui == nil {
+		agent.ui = ui.New()
+	}
+	runner := options.Runner
+	if runner == nil {
+		runner = daemon.ExecRunner{}
 	}
+	agent.daemons = daemon.New(runner)
+
+	auditPath, err := config.GetAuditFilePath()
+	if err != nil {
+		return nil, fmt.Errorf("%w: failed to locate audit log: %w", ErrConfiguration, err)
+	}
+	auditKey, err := secrets.LoadOrCreateKey(config.AuditKeyName)
+	if err != nil {
+		agent.ui.PrintWarning(fmt.Sprintf("The audit log will not be signed: %v", err))
+	}
+	agent.audit = audit.NewLog(auditPath, s.ProfileName(), auditKey)
 
 	if err := agent.initialize(); err != nil {
-		log.Fatal("Failed to initialize agent:", err)
+		return nil, fmt.Errorf("%w: failed to initialize agent: %w", ErrConfiguration, err)
 	}
 
-	return agent
+	return agent, nil
 }
 
 func (a *Agent) initialize() error {
@@ -46,8 +94,17 @@ func (a *Agent) initialize() error {
 	if err != nil {
 		return fmt.Errorf("directory validation failed: %w", err)
 	}
+	a.hyprlandDir = hyprlandDir
+	if viewer, ok := a.ui.(ui.TreeViewer); ok {
+		viewer.SetTree(hyprlandDir, tree)
+	}
 
-	if err := a.createChatSession(keys[config.APIKeyName], tree); err != nil {
+	apiKey, err := a.resolveAPIKey(keys)
+	if err != nil {
+		return err
+	}
+
+	if err := a.createChatSession(apiKey, tree); err != nil {
 		return fmt.Errorf("chat session creation failed: %w", err)
 	}
 
@@ -56,20 +113,49 @@ func (a *Agent) initialize() error {
 
 func (a *Agent) setupConfiguration() (map[string]string, string, error) {
 	set := setup.NewSetup()
-	keys, err := set.FetchConfig()
+	keys, err := set.FetchProfileConfig(a.settings.ProfileName())
 	if err != nil {
-		return nil, "", fmt.Errorf("error fetching config: %w", err)
+		// A profile that names its own directory does not need secrets.ini.
+		if a.settings.HyprlandDir == "" {
+			return nil, "", fmt.Errorf("error fetching config: %w", err)
+		}
+		keys = map[string]string{}
 	}
 
-	hyprlandDir := keys[config.HyprlandDirName]
+	hyprlandDir := a.settings.HyprlandDir
 	if hyprlandDir == "" {
-		return nil, "", fmt.Errorf("hyprland directory name not configured")
+		hyprlandDir = keys[config.HyprlandDirName]
+	}
+	if hyprlandDir == "" {
+		return nil, "", fmt.Errorf("hyprland directory not configured for profile %s", a.settings.ProfileName())
 	}
 
 	return keys, hyprlandDir, nil
 }
 
-func (a *Agent) validateAndGetDirectoryTree(hyprlandDir string) ([]string, error) {
+func (a *Agent) resolveAPIKey(keys map[string]string) (string, error) {
+	apiKey, _, err := secrets.LoadAPIKey(a.settings.APIKeyName())
+	if err == nil {
+		return apiKey, nil
+	}
+
+	// Profiles without their own key share the default one.
+	if a.settings.APIKeyName() != config.APIKeyName {
+		if apiKey, _, defaultErr := secrets.LoadAPIKey(config.APIKeyName); defaultErr == nil {
+			return apiKey, nil
+		}
+	}
+
+	if legacyKey := keys[config.APIKeyName]; legacyKey != "" {
+		secrets.Register(legacyKey)
+		a.ui.PrintWarning("Your API key is stored in plain text. Run 'hyprlander update' to move it into the keyring.")
+		return legacyKey, nil
+	}
+
+	return "", err
+}
+
+func (a *Agent) validateAndGetDirectoryTree(hyprlandDir string) ([]config.TreeEntry, error) {
 	exists, err := config.DirExists(hyprlandDir)
 	if err != nil {
 		return nil, fmt.Errorf("error checking directory existence: %w", err)
@@ -86,7 +172,7 @@ func (a *Agent) validateAndGetDirectoryTree(hyprlandDir string) ([]string, error
 	return tree, nil
 }
 
-func (a *Agent) createChatSession(apiKey string, tree []string) error {
+func (a *Agent) createChatSession(apiKey string, tree []config.TreeEntry) error {
 	client, err := genai.NewClient(a.context, &genai.ClientConfig{
 		APIKey:  apiKey,
 		Backend: genai.BackendGeminiAPI,
@@ -102,7 +188,7 @@ func (a *Agent) createChatSession(apiKey string, tree []string) error {
 	tools := tools.NewConfigForTools()
 	chat, err := client.Chats.Create(
 		a.context,
-		config.GeminiModel,
+		a.settings.Model,
 		tools.Config,
 		a.history,
 	)
@@ -0,0 +1,163 @@
+package agent
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/saat-sy/hyprlander/pkg/policy"
+	"github.com/saat-sy/hyprlander/pkg/settings"
+	"github.com/saat-sy/hyprlander/pkg/ui"
+	"google.golang.org/genai"
+)
+
+var toolLabels = map[string]string{
+	"readFile":            "reads",
+	"writeFile":           "writes",
+	"shellExecute":        "shell commands",
+	"applyTheme":          "theme changes",
+	"restartDaemon":       "daemon restarts",
+	"setWaybarOption":     "waybar option changes",
+	"addWaybarModule":     "waybar module changes",
+	"removeWaybarModule":  "waybar module changes",
+	"getWallpaperPalette": "image reads",
+}
+
+// offlineTools only read data embedded in hyprlander, the Hyprland config the
+// agent was started on, or the app configs hyprlander themes, such as
+// waybar's, so they never need confirmation. They never read a path the model
+// chooses; see readsChosenPath.
+var offlineTools = map[string]bool{
+	"searchDocs":          true,
+	"getDocPage":          true,
+	"getOptionInfo":       true,
+	"listBinds":           true,
+	"explainOption":       true,
+	"convertColor":        true,
+	"adjustColor":         true,
+	"contrastRatio":       true,
+	"getWallpaperPalette": true,
+	"getWaybarConfig":     true,
+}
+
+// confirmExecution decides whether a call may run. Policy and session rules
+// are checked first and the user is only asked when none of them apply; the
+// rule that settled the call is shown to the user and returned for the audit
+// log.
+func (a *Agent) confirmExecution(funcCall *genai.FunctionCall) (ui.Decision, string, error) {
+	toolName := funcCall.Name
+
+	if slices.Contains(a.settings.Policy.Deny, toolName) {
+		a.ui.PrintWarning(fmt.Sprintf("%s is denied by policy", toolName))
+		return ui.Reject, "policy.deny", nil
+	}
+
+	if rule := a.autoApprovalRule(funcCall); rule != "" {
+		a.ui.Print(fmt.Sprintf("Auto-approved by %s", rule))
+		return ui.Approve, rule, nil
+	}
+
+	var extra []ui.Decision
+	switch toolName {
+	case "writeFile":
+		extra = []ui.Decision{ui.Edit, ui.Review}
+	case "shellExecute":
+		extra = []ui.Decision{ui.Edit}
+	}
+	// Allowing every later call for the session is only offered for reads;
+	// changes are approved one by one or by a saved rule.
+	if !isMutatingTool(toolName) {
+		extra = append(extra, ui.ApproveSession)
+	}
+	extra = append(extra, ui.ApproveAlways)
+
+	decision, err := a.ui.ConfirmTool("Do you want to proceed?", extra...)
+	if err != nil {
+		return ui.Reject, "", err
+	}
+
+	switch decision {
+	case ui.ApproveSession:
+		a.sessionTools[toolName] = true
+		a.ui.Print(fmt.Sprintf("All %s are allowed for the rest of this session.", toolLabel(toolName)))
+		return ui.Approve, "user (for this session)", nil
+	case ui.ApproveAlways:
+		rule, err := a.addAlwaysRule(funcCall)
+		if err != nil {
+			a.ui.PrintError(fmt.Errorf("could not save the rule, allowing this call once: %w", err))
+			return ui.Approve, "user", nil
+		}
+		return ui.Approve, "user (always: " + rule.String() + ")", nil
+	}
+
+	return decision, "user", nil
+}
+
+// autoApprovalRule names the rule that allows the call without asking, or
+// returns "" if the user has to be asked.
+func (a *Agent) autoApprovalRule(funcCall *genai.FunctionCall) string {
+	toolName := funcCall.Name
+	subject := policy.Subject(toolName, funcCall.Args)
+
+	switch {
+	case offlineTools[toolName] && !readsChosenPath(funcCall):
+		return "built-in offline tool"
+	case a.settings.AssumeYes:
+		return "--yes"
+	case a.options.DryRun && toolName == "readFile" && a.inHyprlandDir(subject):
+		// Nothing touches the disk in a dry run, so reads of the config need
+		// no confirmation. Other files still go to the model only if the
+		// user agrees.
+		return "--dry-run"
+	case slices.Contains(a.settings.Policy.AutoApprove, toolName):
+		return "policy.auto_approve: " + toolName
+	case a.sessionTools[toolName]:
+		return "session rule: all " + toolLabel(toolName)
+	}
+
+	for _, rule := range a.settings.Policy.Rules {
+		if rule.Matches(toolName, subject) {
+			return "policy rule: " + rule.String()
+		}
+	}
+
+	return ""
+}
+
+func (a *Agent) addAlwaysRule(funcCall *genai.FunctionCall) (policy.Rule, error) {
+	suggestion := policy.Suggest(funcCall.Name, policy.Subject(funcCall.Name, funcCall.Args))
+
+	pattern, err := a.ui.Input(fmt.Sprintf("Always allow %s matching (default: %s): ", toolLabel(funcCall.Name), suggestion))
+	if err != nil {
+		return policy.Rule{Tool: funcCall.Name, Pattern: suggestion}, err
+	}
+	if pattern == "" {
+		pattern = suggestion
+	}
+
+	rule := policy.Rule{Tool: funcCall.Name, Pattern: pattern}
+	if err := rule.Check(); err != nil {
+		return rule, err
+	}
+	if err := settings.AddPolicyRule(a.settings.ProfileName(), rule); err != nil {
+		return rule, err
+	}
+	a.settings.Policy.Rules = append(a.settings.Policy.Rules, rule)
+
+	a.ui.Print(fmt.Sprintf("Saved policy rule %s", rule))
+	return rule, nil
+}
+
+// readsChosenPath reports whether an offline tool was asked to read a file the
+// model picked, such as an image other than the wallpaper, which needs
+// confirmation like readFile.
+func readsChosenPath(funcCall *genai.FunctionCall) bool {
+	image, _ := funcCall.Args["image"].(string)
+	return funcCall.Name == "getWallpaperPalette" && image != ""
+}
+
+func toolLabel(toolName string) string {
+	if label, ok := toolLabels[toolName]; ok {
+		return label
+	}
+	return toolName + " calls"
+}
@@ -0,0 +1,78 @@
+package agent
+
+import (
+	"fmt"
+	"maps"
+	"time"
+
+	"github.com/saat-sy/hyprlander/pkg/audit"
+	"github.com/saat-sy/hyprlander/pkg/ui"
+	"google.golang.org/genai"
+)
+
+var auditDecisions = map[ui.Decision]string{
+	ui.Approve: "approved",
+	ui.Reject:  "rejected",
+	ui.Edit:    "edited",
+	ui.Review:  "reviewed",
+}
+
+// runFunctionCall executes the call and records it in the audit log together
+// with the decision that allowed it and the hashes of the files it touched.
+func (a *Agent) runFunctionCall(funcCall *genai.FunctionCall, decision ui.Decision, approvedBy string) (string, error) {
+	entry := a.newAuditEntry(funcCall, decision, approvedBy)
+
+	paths := a.touchedFiles(funcCall)
+	before := make([]string, len(paths))
+	for i, path := range paths {
+		before[i] = audit.HashFile(path)
+	}
+
+	output, err := a.executeFunctionCall(funcCall)
+
+	entry.Finished = time.Now().UTC()
+	if err != nil {
+		entry.Error = err.Error()
+	} else {
+		entry.OutputHash = audit.HashBytes([]byte(output))
+	}
+	for i, path := range paths {
+		entry.Files = append(entry.Files, audit.FileHash{Path: path, Before: before[i], After: audit.HashFile(path)})
+	}
+	a.appendAudit(entry)
+
+	return output, err
+}
+
+// auditRejected records a call that was not run.
+func (a *Agent) auditRejected(funcCall *genai.FunctionCall, approvedBy string) {
+	a.auditNotRun(funcCall, auditDecisions[ui.Reject], approvedBy)
+}
+
+// auditNotRun records a call that ended without running, such as a change
+// proposed in a dry run or a write sent back by validation.
+func (a *Agent) auditNotRun(funcCall *genai.FunctionCall, decision, approvedBy string) {
+	entry := a.newAuditEntry(funcCall, ui.Reject, approvedBy)
+	entry.Decision = decision
+	entry.Finished = entry.Started
+	a.appendAudit(entry)
+}
+
+func (a *Agent) newAuditEntry(funcCall *genai.FunctionCall, decision ui.Decision, approvedBy string) audit.Entry {
+	return audit.Entry{
+		Started:    time.Now().UTC(),
+		Tool:       funcCall.Name,
+		Args:       maps.Clone(funcCall.Args),
+		Decision:   auditDecisions[decision],
+		ApprovedBy: approvedBy,
+	}
+}
+
+func (a *Agent) appendAudit(entry audit.Entry) {
+	if a.audit == nil {
+		return
+	}
+	if _, err := a.audit.Append(entry); err != nil {
+		a.ui.PrintError(fmt.Errorf("failed to write audit log %s: %w", a.audit.Path(), err))
+	}
+}
@@ -1,29 +1,57 @@
 package agent
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
+	"github.com/saat-sy/hyprlander/pkg/ui"
 	"google.golang.org/genai"
 )
 
-func (a *Agent) InvokeAgent(prompt string) {
+type turnState int
+
+const (
+	turnContinue turnState = iota
+	turnConcluded
+	turnDeclined
+)
+
+const maxProviderFailures = 3
+
+// InvokeAgent runs the conversation until the model concludes, the user
+// declines a tool call, or the turn limit is reached. The returned error wraps
+// ErrDeclined, ErrMaxTurns or ErrProvider so callers can tell these apart.
+func (a *Agent) InvokeAgent(prompt string) error {
 	currentPrompt := prompt
 	var pendingFunctionResponse *genai.FunctionResponse
+	providerFailures := 0
+
+	defer a.printProposedChanges()
+	defer a.printUsage()
 
 	for turn := 1; turn <= a.maxTurns; turn++ {
 		response, err := a.sendMessage(currentPrompt, pendingFunctionResponse)
 		if err != nil {
 			a.ui.PrintError(fmt.Errorf("error in turn %d: %w", turn, err))
+			providerFailures++
+			if providerFailures >= maxProviderFailures {
+				return fmt.Errorf("%w: %w", ErrProvider, err)
+			}
 			continue
 		}
+		providerFailures = 0
+		a.recordUsage(response)
 
 		currentPrompt = ""
 		pendingFunctionResponse = nil
 
-		nextPrompt, functionResponse, shouldContinue := a.processResponse(response)
-		if !shouldContinue {
-			return
+		nextPrompt, functionResponse, state := a.processResponse(response)
+		switch state {
+		case turnConcluded:
+			return nil
+		case turnDeclined:
+			return ErrDeclined
 		}
 
 		currentPrompt = nextPrompt
@@ -31,6 +59,22 @@ func (a *Agent) InvokeAgent(prompt string) {
 	}
 
 	a.ui.Print("Maximum number of turns reached. Ending conversation.")
+	return ErrMaxTurns
+}
+
+func (a *Agent) recordUsage(response *genai.GenerateContentResponse) {
+	if response.UsageMetadata == nil {
+		return
+	}
+	a.usage.PromptTokens += response.UsageMetadata.PromptTokenCount
+	a.usage.ResponseTokens += response.UsageMetadata.CandidatesTokenCount
+	a.usage.TotalTokens += response.UsageMetadata.TotalTokenCount
+}
+
+func (a *Agent) printUsage() {
+	if a.usage.TotalTokens > 0 {
+		a.ui.PrintUsage(a.usage)
+	}
 }
 
 func (a *Agent) sendMessage(prompt string, functionResponse *genai.FunctionResponse) (*genai.GenerateContentResponse, error) {
@@ -52,16 +96,16 @@ func (a *Agent) sendMessage(prompt string, functionResponse *genai.FunctionRespo
 	return response, nil
 }
 
-func (a *Agent) processResponse(response *genai.GenerateContentResponse) (string, *genai.FunctionResponse, bool) {
+func (a *Agent) processResponse(response *genai.GenerateContentResponse) (string, *genai.FunctionResponse, turnState) {
 	if len(response.Candidates) == 0 {
 		a.ui.Print("No response from the model. Trying again...")
-		return "", nil, true
+		return "", nil, turnContinue
 	}
 
 	candidate := response.Candidates[0]
 	if len(candidate.Content.Parts) == 0 {
 		a.ui.Print("No content parts in response. Trying again...")
-		return "", nil, true
+		return "", nil, turnContinue
 	}
 
 	part := candidate.Content.Parts[0]
@@ -75,42 +119,43 @@ func (a *Agent) processResponse(response *genai.GenerateContentResponse) (string
 	}
 
 	a.ui.Print("Unexpected response format. Trying again...")
-	return "", nil, true
+	return "", nil, turnContinue
 }
 
-func (a *Agent) handleTextResponse(text string) (string, *genai.FunctionResponse, bool) {
-	a.ui.PrintAgent(text)
-
+func (a *Agent) handleTextResponse(text string) (string, *genai.FunctionResponse, turnState) {
 	if a.isUserInputRequested(text) {
+		a.ui.PrintAgent(text)
 		userInput, err := a.getUserInput()
 		if err != nil {
 			a.ui.PrintError(fmt.Errorf("error during user interaction: %w", err))
-			return "", nil, true
+			return NoUserInputPrompt, nil, turnContinue
 		}
-		return GetUserInputPrompt(userInput), nil, true
+		return GetUserInputPrompt(userInput), nil, turnContinue
+	}
+
+	if strings.Contains(text, "**Conclusion:**") {
+		a.ui.PrintConclusion(text)
+		return "", nil, turnConcluded
 	}
 
+	a.ui.PrintAgent(text)
+
 	textLower := strings.ToLower(text)
-	if (strings.Contains(textLower, "i will change") ||
+	if strings.Contains(textLower, "i will change") ||
 		strings.Contains(textLower, "i will modify") ||
 		strings.Contains(textLower, "i will update") ||
 		strings.Contains(textLower, "has been changed") ||
 		strings.Contains(textLower, "has been modified") ||
-		strings.Contains(textLower, "has been updated")) &&
-		!strings.Contains(text, "**Conclusion:**") {
+		strings.Contains(textLower, "has been updated") {
 
 		promptForAction := "You mentioned making changes but didn't use the writeFile function. You MUST use writeFile to actually implement the changes. Please call writeFile now with the modified content."
-		return promptForAction, nil, true
+		return promptForAction, nil, turnContinue
 	}
 
-	if strings.Contains(text, "**Conclusion:**") {
-		return "", nil, false
-	}
-
-	return "", nil, true
+	return "", nil, turnContinue
 }
 
-func (a *Agent) handleFunctionCall(funcCall *genai.FunctionCall) (string, *genai.FunctionResponse, bool) {
+func (a *Agent) printFunctionCall(funcCall *genai.FunctionCall) {
 	switch funcCall.Name {
 	case "readFile":
 		a.ui.PrintReadTool(funcCall.Args)
@@ -118,36 +163,104 @@ func (a *Agent) handleFunctionCall(funcCall *genai.FunctionCall) (string, *genai
 		a.ui.PrintWriteTool(funcCall.Args)
 	case "shellExecute":
 		a.ui.PrintShellTool(funcCall.Args)
+	case "applyTheme", "setWaybarOption", "addWaybarModule", "removeWaybarModule":
+		a.ui.PrintTool(funcCall.Name, funcCall.Args)
+		// Show the diff of every file the call changes. Planning errors are
+		// reported when the call runs.
+		changes, _ := a.planChanges(funcCall)
+		for _, change := range changes {
+			a.ui.PrintWriteTool(map[string]interface{}{"path": change.Path, "content": change.Content})
+		}
 	default:
 		a.ui.PrintTool(funcCall.Name, funcCall.Args)
 	}
+}
+
+func (a *Agent) handleFunctionCall(funcCall *genai.FunctionCall) (string, *genai.FunctionResponse, turnState) {
+	if funcCall.Name == "writeFile" {
+		a.formatWrite(funcCall)
+	}
+	a.printFunctionCall(funcCall)
+
+	if funcCall.Name == "writeFile" {
+		if prompt, ok := a.validateWrite(funcCall); !ok {
+			a.auditNotRun(funcCall, "rejected by validation", "")
+			return prompt, nil, turnContinue
+		}
+	}
 
-	confirmed, err := a.confirmExecution()
+	if a.options.DryRun && isMutatingTool(funcCall.Name) {
+		return "", a.proposeFunctionCall(funcCall), turnContinue
+	}
+
+	decision, approvedBy, err := a.confirmExecution(funcCall)
+	if errors.Is(err, ui.ErrTUIClosed) {
+		return "", nil, turnDeclined
+	}
 	if err != nil {
 		a.ui.PrintError(fmt.Errorf("error during confirmation: %w", err))
-		return "Could not run the tool. Try again.", nil, true
+		return "Could not run the tool. Try again.", nil, turnContinue
 	}
 
-	if !confirmed {
+	if decision == ui.Reject {
+		a.auditRejected(funcCall, approvedBy)
 		a.ui.Print("Function execution cancelled by user.")
-		return GetPermissionDeniedPrompt(funcCall.Name, fmt.Sprintf("%v", funcCall.Args)), nil, false
+		return GetPermissionDeniedPrompt(funcCall.Name, fmt.Sprintf("%v", funcCall.Args)), nil, turnDeclined
+	}
+
+	response := map[string]interface{}{}
+	switch decision {
+	case ui.Edit:
+		note, proceed, err := a.editFunctionCall(funcCall)
+		if err != nil {
+			a.ui.PrintError(fmt.Errorf("error editing function call: %w", err))
+			return "Could not run the tool. Try again.", nil, turnContinue
+		}
+		if !proceed {
+			a.auditRejected(funcCall, approvedBy)
+			a.ui.Print("Function execution cancelled by user.")
+			return GetPermissionDeniedPrompt(funcCall.Name, fmt.Sprintf("%v", funcCall.Args)), nil, turnDeclined
+		}
+		if note != "" {
+			response["note"] = note
+			a.printFunctionCall(funcCall)
+		}
+	case ui.Review:
+		review, err := a.reviewHunks(funcCall)
+		if err != nil {
+			a.ui.PrintError(fmt.Errorf("error reviewing hunks: %w", err))
+			return "Could not run the tool. Try again.", nil, turnContinue
+		}
+		if review.rejectedAll() {
+			a.auditRejected(funcCall, approvedBy)
+			a.ui.Print("All hunks rejected by user.")
+			return GetPermissionDeniedPrompt(funcCall.Name, fmt.Sprintf("%v", funcCall.Args)), nil, turnDeclined
+		}
+		response["note"] = review.note()
+		response["applied_content"] = review.content
 	}
 
-	output, err := a.executeFunctionCall(funcCall)
+	output, err := a.runFunctionCall(funcCall, decision, approvedBy)
 	if err != nil {
 		a.ui.PrintError(fmt.Errorf("error executing function call: %w", err))
 		errorPrompt := fmt.Sprintf("The function call failed with error: %v. Please provide an alternative solution.", err)
-		return errorPrompt, nil, true
+		return errorPrompt, nil, turnContinue
 	}
 
-	a.ui.PrintSuccess("Function successfully executed")
+	a.ui.PrintToolResult(funcCall.Name, output)
 
+	response["result"] = output
+	if funcCall.Name == "writeFile" {
+		if findings := a.lintWrite(funcCall); findings != "" {
+			response["lint"] = findings
+		}
+	}
 	functionResponse := &genai.FunctionResponse{
 		Name:     funcCall.Name,
-		Response: map[string]interface{}{"result": output},
+		Response: response,
 	}
 
-	return "", functionResponse, true
+	return "", functionResponse, turnContinue
 }
 
 func (a *Agent) getUserInput() (string, error) {
@@ -155,10 +268,6 @@ func (a *Agent) getUserInput() (string, error) {
 	return a.ui.Input("Please provide any necessary suggestion or leave blank: ")
 }
 
-func (a *Agent) confirmExecution() (bool, error) {
-	return a.ui.Confirm("Do you want to proceed?")
-}
-
 func (a *Agent) isUserInputRequested(text string) bool {
 	return strings.Contains(strings.ToLower(text), UserInputSignature)
 }
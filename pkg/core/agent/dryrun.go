@@ -0,0 +1,111 @@
+package agent
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"google.golang.org/genai"
+)
+
+func isMutatingTool(name string) bool {
+	return name == "writeFile" || name == "shellExecute" || name == "restartDaemon" || plansChanges(name)
+}
+
+// proposeFunctionCall records a write, theme, waybar edit, restart or shell
+// command instead of running it and tells the model the change was accepted into the
+// change set.
+func (a *Agent) proposeFunctionCall(funcCall *genai.FunctionCall) *genai.FunctionResponse {
+	if slices.Contains(a.settings.Policy.Deny, funcCall.Name) {
+		a.ui.PrintWarning(fmt.Sprintf("%s is denied by policy", funcCall.Name))
+		a.auditRejected(funcCall, "policy.deny")
+		return &genai.FunctionResponse{
+			Name:     funcCall.Name,
+			Response: map[string]interface{}{"error": fmt.Sprintf("%s is denied by policy", funcCall.Name)},
+		}
+	}
+
+	if plansChanges(funcCall.Name) {
+		changes, err := a.planChanges(funcCall)
+		if err != nil {
+			return &genai.FunctionResponse{
+				Name:     funcCall.Name,
+				Response: map[string]interface{}{"error": err.Error()},
+			}
+		}
+		for _, change := range changes {
+			a.proposedFiles[absPath(change.Path)] = change.Content
+		}
+	}
+
+	a.proposed = append(a.proposed, funcCall)
+	if funcCall.Name == "writeFile" {
+		path, _ := funcCall.Args["path"].(string)
+		content, _ := funcCall.Args["content"].(string)
+		a.proposedFiles[absPath(path)] = content
+	}
+
+	a.auditNotRun(funcCall, "proposed", "--dry-run")
+	a.ui.Print("Dry run: recorded, not applied.")
+
+	return &genai.FunctionResponse{
+		Name:     funcCall.Name,
+		Response: map[string]interface{}{"result": DryRunResult},
+	}
+}
+
+func (a *Agent) printProposedChanges() {
+	if !a.options.DryRun {
+		return
+	}
+
+	a.ui.PrintTitle("Proposed changes (dry run)")
+	if len(a.proposed) == 0 {
+		a.ui.Print("No changes proposed.")
+		return
+	}
+
+	printed := make(map[string]bool)
+	for _, funcCall := range a.proposed {
+		switch funcCall.Name {
+		case "writeFile", "applyTheme", "setWaybarOption", "addWaybarModule", "removeWaybarModule":
+			for _, path := range a.touchedFiles(funcCall) {
+				if printed[path] {
+					continue
+				}
+				printed[path] = true
+				a.ui.PrintWriteTool(map[string]interface{}{
+					"path":    path,
+					"content": a.proposedFiles[absPath(path)],
+				})
+			}
+		case "shellExecute":
+			a.ui.PrintShellTool(funcCall.Args)
+		case "restartDaemon":
+			a.ui.PrintTool(funcCall.Name, funcCall.Args)
+		}
+	}
+}
+
+// absPath is the key of path in proposedFiles, so "./hyprland.conf" and the
+// absolute path of the same file share their proposed content.
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// inHyprlandDir reports whether path is inside the Hyprland config directory.
+func (a *Agent) inHyprlandDir(path string) bool {
+	if a.hyprlandDir == "" || path == "" {
+		return false
+	}
+	dir, err := filepath.Abs(a.hyprlandDir)
+	if err != nil {
+		return false
+	}
+	rel, err := filepath.Rel(dir, absPath(path))
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, "../")
+}
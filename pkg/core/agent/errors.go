@@ -0,0 +1,10 @@
+package agent
+
+import "errors"
+
+var (
+	ErrDeclined      = errors.New("the user declined a tool call")
+	ErrMaxTurns      = errors.New("maximum number of turns reached")
+	ErrProvider      = errors.New("model provider error")
+	ErrConfiguration = errors.New("agent configuration error")
+)
@@ -2,8 +2,12 @@ package agent
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/saat-sy/hyprlander/pkg/core/tools"
+	"github.com/saat-sy/hyprlander/pkg/diff"
+	"github.com/saat-sy/hyprlander/pkg/hyprlang"
+	"github.com/saat-sy/hyprlander/pkg/waybar"
 	"google.golang.org/genai"
 )
 
@@ -15,6 +19,32 @@ func (a *Agent) executeFunctionCall(funcCall *genai.FunctionCall) (string, error
 		return a.executeWriteFile(funcCall.Args)
 	case "shellExecute":
 		return a.executeShellCommand(funcCall.Args)
+	case "searchDocs":
+		return a.executeSearchDocs(funcCall.Args)
+	case "getDocPage":
+		return a.executeGetDocPage(funcCall.Args)
+	case "getOptionInfo":
+		return a.executeGetOptionInfo(funcCall.Args)
+	case "listBinds":
+		return a.executeListBinds(funcCall.Args)
+	case "explainOption":
+		return a.executeExplainOption(funcCall.Args)
+	case "convertColor":
+		return a.executeConvertColor(funcCall.Args)
+	case "adjustColor":
+		return a.executeAdjustColor(funcCall.Args)
+	case "contrastRatio":
+		return a.executeContrastRatio(funcCall.Args)
+	case "applyTheme":
+		return a.executeApplyTheme(funcCall.Args)
+	case "getWallpaperPalette":
+		return a.executeGetWallpaperPalette(funcCall.Args)
+	case "restartDaemon":
+		return a.executeRestartDaemon(funcCall.Args)
+	case "getWaybarConfig":
+		return tools.DescribeWaybar(a.settings.AppDirs)
+	case "setWaybarOption", "addWaybarModule", "removeWaybarModule":
+		return a.executeWaybarEdit(funcCall)
 	default:
 		return "", fmt.Errorf("unknown function: %s", funcCall.Name)
 	}
@@ -26,6 +56,11 @@ func (a *Agent) executeReadFile(args map[string]interface{}) (string, error) {
 		return "", fmt.Errorf("invalid path parameter for readFile")
 	}
 
+	// Reads in a dry run see the writes proposed earlier in the conversation.
+	if content, ok := a.proposedFiles[absPath(path)]; ok {
+		return content, nil
+	}
+
 	content, err := tools.ReadFile(path)
 	if err != nil {
 		return "", fmt.Errorf("failed to read file %s: %w", path, err)
@@ -65,3 +100,310 @@ func (a *Agent) executeShellCommand(args map[string]interface{}) (string, error)
 
 	return output, nil
 }
+
+func (a *Agent) executeSearchDocs(args map[string]interface{}) (string, error) {
+	query, ok := args["query"].(string)
+	if !ok {
+		return "", fmt.Errorf("invalid query parameter for searchDocs")
+	}
+
+	return tools.SearchDocs(query)
+}
+
+func (a *Agent) executeGetDocPage(args map[string]interface{}) (string, error) {
+	name, ok := args["name"].(string)
+	if !ok {
+		return "", fmt.Errorf("invalid name parameter for getDocPage")
+	}
+
+	return tools.GetDocPage(name)
+}
+
+func (a *Agent) executeGetOptionInfo(args map[string]interface{}) (string, error) {
+	name, ok := args["name"].(string)
+	if !ok {
+		return "", fmt.Errorf("invalid name parameter for getOptionInfo")
+	}
+
+	program, _ := args["program"].(string)
+
+	return tools.GetOptionInfo(name, program)
+}
+
+func (a *Agent) executeListBinds(args map[string]interface{}) (string, error) {
+	combo, _ := args["combo"].(string)
+	submap, _ := args["submap"].(string)
+
+	return tools.ListBinds(a.hyprlandDir, combo, submap)
+}
+
+func (a *Agent) executeExplainOption(args map[string]interface{}) (string, error) {
+	option, ok := args["option"].(string)
+	if !ok {
+		return "", fmt.Errorf("invalid option parameter for explainOption")
+	}
+
+	program, _ := args["program"].(string)
+
+	return tools.ExplainOption(a.hyprlandDir, option, program)
+}
+
+func (a *Agent) executeConvertColor(args map[string]interface{}) (string, error) {
+	value, ok := args["value"].(string)
+	if !ok {
+		return "", fmt.Errorf("invalid value parameter for convertColor")
+	}
+
+	return tools.ConvertColor(value)
+}
+
+func (a *Agent) executeAdjustColor(args map[string]interface{}) (string, error) {
+	value, ok := args["value"].(string)
+	if !ok {
+		return "", fmt.Errorf("invalid value parameter for adjustColor")
+	}
+	operation, ok := args["operation"].(string)
+	if !ok {
+		return "", fmt.Errorf("invalid operation parameter for adjustColor")
+	}
+	amount, ok := args["amount"].(float64)
+	if !ok {
+		return "", fmt.Errorf("invalid amount parameter for adjustColor")
+	}
+
+	return tools.AdjustColor(value, operation, amount)
+}
+
+func (a *Agent) executeContrastRatio(args map[string]interface{}) (string, error) {
+	foreground, ok := args["foreground"].(string)
+	if !ok {
+		return "", fmt.Errorf("invalid foreground parameter for contrastRatio")
+	}
+	background, ok := args["background"].(string)
+	if !ok {
+		return "", fmt.Errorf("invalid background parameter for contrastRatio")
+	}
+
+	return tools.ContrastRatio(foreground, background)
+}
+
+func (a *Agent) executeApplyTheme(args map[string]interface{}) (string, error) {
+	name, ok := args["name"].(string)
+	if !ok {
+		return "", fmt.Errorf("invalid name parameter for applyTheme")
+	}
+	image, _ := args["image"].(string)
+
+	return tools.ApplyTheme(a.themeTargets(args), name, image)
+}
+
+func (a *Agent) executeGetWallpaperPalette(args map[string]interface{}) (string, error) {
+	image, _ := args["image"].(string)
+
+	return tools.DescribeWallpaperPalette(a.hyprlandDir, image)
+}
+
+// planTheme computes the files an applyTheme call would change.
+func (a *Agent) planTheme(args map[string]interface{}) ([]hyprlang.FileChange, error) {
+	name, ok := args["name"].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid name parameter for applyTheme")
+	}
+	image, _ := args["image"].(string)
+
+	palette, err := tools.LoadPalette(a.hyprlandDir, name, image)
+	if err != nil {
+		return nil, err
+	}
+	return tools.PlanTheme(a.themeTargets(args), palette)
+}
+
+// themeTargets reads the apps an applyTheme call writes to.
+func (a *Agent) themeTargets(args map[string]interface{}) tools.ThemeTargets {
+	targets := tools.ThemeTargets{HyprlandDir: a.hyprlandDir, AppDirs: a.settings.AppDirs}
+	apps, _ := args["apps"].([]interface{})
+	for _, app := range apps {
+		if name, ok := app.(string); ok {
+			targets.Apps = append(targets.Apps, name)
+		}
+	}
+	return targets
+}
+
+// plansChanges reports whether a tool computes its file changes up front,
+// so they can be shown as diffs before the call is approved.
+func plansChanges(name string) bool {
+	switch name {
+	case "applyTheme", "setWaybarOption", "addWaybarModule", "removeWaybarModule":
+		return true
+	}
+	return false
+}
+
+// planChanges computes the files a theme or waybar call would change.
+func (a *Agent) planChanges(funcCall *genai.FunctionCall) ([]hyprlang.FileChange, error) {
+	if funcCall.Name == "applyTheme" {
+		return a.planTheme(funcCall.Args)
+	}
+	change, err := a.planWaybar(funcCall)
+	if change == nil {
+		return nil, err
+	}
+	return []hyprlang.FileChange{*change}, nil
+}
+
+// touchedFiles lists the files a call writes, so their hashes can be
+// recorded before and after it runs.
+func (a *Agent) touchedFiles(funcCall *genai.FunctionCall) []string {
+	switch {
+	case funcCall.Name == "writeFile":
+		if path, _ := funcCall.Args["path"].(string); path != "" {
+			return []string{path}
+		}
+	case plansChanges(funcCall.Name):
+		changes, _ := a.planChanges(funcCall)
+		var paths []string
+		for _, change := range changes {
+			paths = append(paths, change.Path)
+		}
+		return paths
+	}
+	return nil
+}
+
+// editFunctionCall opens the proposed file content or shell command in the
+// user's editor and rewrites the call with the result. It returns a note for
+// the model describing the change, or "" if the user saved it unchanged.
+// Emptying the editor cancels the call.
+func (a *Agent) editFunctionCall(funcCall *genai.FunctionCall) (string, bool, error) {
+	switch funcCall.Name {
+	case "writeFile":
+		path, ok := funcCall.Args["path"].(string)
+		if !ok {
+			return "", false, fmt.Errorf("invalid path parameter for writeFile")
+		}
+		content, ok := funcCall.Args["content"].(string)
+		if !ok {
+			return "", false, fmt.Errorf("invalid content parameter for writeFile")
+		}
+
+		edited, err := a.ui.Edit(path, content)
+		if err != nil {
+			return "", false, err
+		}
+		if strings.TrimSpace(edited) == "" {
+			return "", false, nil
+		}
+
+		funcCall.Args["content"] = edited
+		if edited == content {
+			return "", true, nil
+		}
+		return GetUserEditedFilePrompt(diff.Unified(path, content, edited)), true, nil
+
+	case "shellExecute":
+		command, ok := funcCall.Args["command"].(string)
+		if !ok {
+			return "", false, fmt.Errorf("invalid command parameter for shellExecute")
+		}
+
+		edited, err := a.ui.Edit("command.txt", command+"\n")
+		if err != nil {
+			return "", false, err
+		}
+		edited = strings.TrimSpace(edited)
+		if edited == "" {
+			return "", false, nil
+		}
+		// shellExecute splits the command on whitespace and runs it without
+		// a shell, so quotes and further lines would reach the program as
+		// literal arguments instead of doing what the user meant.
+		if strings.Contains(edited, "\n") || strings.ContainsAny(edited, "'\"`\\") {
+			return "", false, fmt.Errorf("commands run without a shell: keep the edit on one line and without quotes or backslashes")
+		}
+
+		funcCall.Args["command"] = edited
+		if edited == command {
+			return "", true, nil
+		}
+		return GetUserEditedCommandPrompt(command, edited), true, nil
+
+	default:
+		return "", false, fmt.Errorf("%s cannot be edited", funcCall.Name)
+	}
+}
+
+func (a *Agent) executeRestartDaemon(args map[string]interface{}) (string, error) {
+	program, ok := args["program"].(string)
+	if !ok {
+		return "", fmt.Errorf("invalid program parameter for restartDaemon")
+	}
+
+	return tools.RestartDaemon(a.context, a.daemons, program)
+}
+
+func (a *Agent) executeWaybarEdit(funcCall *genai.FunctionCall) (string, error) {
+	change, err := a.planWaybar(funcCall)
+	if err != nil {
+		return "", err
+	}
+	return tools.WriteWaybarChange(funcCall.Name, change, waybarBar(funcCall.Args))
+}
+
+// planWaybar computes the change a setWaybarOption, addWaybarModule or
+// removeWaybarModule call makes to waybar's config.
+func (a *Agent) planWaybar(funcCall *genai.FunctionCall) (*hyprlang.FileChange, error) {
+	args := funcCall.Args
+	bar := waybarBar(args)
+
+	var edit func(*waybar.Config) error
+	switch funcCall.Name {
+	case "setWaybarOption":
+		items, _ := args["path"].([]interface{})
+		var path []string
+		for _, item := range items {
+			if key, ok := item.(string); ok {
+				path = append(path, key)
+			}
+		}
+		if len(path) == 0 {
+			return nil, fmt.Errorf("invalid path parameter for setWaybarOption")
+		}
+		if remove, _ := args["remove"].(bool); remove {
+			edit = func(c *waybar.Config) error { return c.Delete(bar, path) }
+			break
+		}
+		value, ok := args["value"].(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid value parameter for setWaybarOption")
+		}
+		edit = func(c *waybar.Config) error { return c.Set(bar, path, waybar.ParseValue(value)) }
+	case "addWaybarModule", "removeWaybarModule":
+		module, ok := args["module"].(string)
+		if !ok {
+			return nil, fmt.Errorf("invalid module parameter for %s", funcCall.Name)
+		}
+		section, _ := args["section"].(string)
+		if funcCall.Name == "removeWaybarModule" {
+			edit = func(c *waybar.Config) error {
+				_, err := c.RemoveModule(bar, section, module)
+				return err
+			}
+			break
+		}
+		position := -1
+		if value, ok := args["position"].(float64); ok {
+			position = int(value)
+		}
+		edit = func(c *waybar.Config) error { return c.AddModule(bar, section, module, position) }
+	default:
+		return nil, fmt.Errorf("unknown function: %s", funcCall.Name)
+	}
+
+	return tools.PlanWaybarEdit(a.settings.AppDirs, edit)
+}
+
+func waybarBar(args map[string]interface{}) int {
+	bar, _ := args["bar"].(float64)
+	return int(bar)
+}
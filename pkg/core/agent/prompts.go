@@ -3,6 +3,8 @@ package agent
 import (
 	"fmt"
 	"strings"
+
+	"github.com/saat-sy/hyprlander/pkg/config"
 )
 
 const UserInputSignature = "**USER_INPUT_REQUIRED**"
@@ -17,6 +19,36 @@ You have access to the following tools to interact with the user's system and fi
 - readFile: Read the entire content of any file
 - writeFile: Write or overwrite file content completely  
 - shellExecute: Execute shell commands and get output
+- searchDocs: Search an offline snapshot of the Hyprland wiki
+- getDocPage: Read a full page of the offline Hyprland wiki
+- getOptionInfo: Get the type, default, range and deprecation of a config option
+- listBinds: List the keybindings and their conflicts, or check whether a key combination is free
+- explainOption: Show every file and line that sets an option and which assignment takes effect
+- convertColor, adjustColor, contrastRatio: Convert colors and gradients between notations, lighten, darken or otherwise adjust them, and check contrast
+- applyTheme: Apply a bundled color theme such as catppuccin-mocha or nord, or the wallpaper's colors, to the borders, groups, shadow and background, and to waybar, kitty, foot, alacritty, mako and wofi
+- getWallpaperPalette: Extract the dominant colors of the wallpaper and the roles derived from them
+- restartDaemon: Make hyprpaper, hypridle or hyprsunset apply a changed config, or reload Hyprland
+- getWaybarConfig: Show waybar's config: its bars, their modules and options
+- setWaybarOption: Set or remove an option of a waybar bar or module, such as the height or the clock's format
+- addWaybarModule, removeWaybarModule: Add a module to, or remove it from, the left, center or right of a waybar bar
+
+Before adding or changing a keybinding, use listBinds with the combo to make sure it does not conflict with an existing bind.
+
+Before writing an option, keyword, dispatcher or rule you are not certain about, check its exact name and type with getOptionInfo or searchDocs. Never invent option names.
+
+Never compute color values yourself. Use adjustColor for requests such as "make the borders darker" and contrastRatio to check that colors stay readable.
+
+When the user asks for a named color theme that applyTheme provides, use it instead of writing the colors by hand. You cannot see the wallpaper: when colors should match it, use getWallpaperPalette or applyTheme with "wallpaper" instead of guessing.
+
+Before changing an option, use explainOption to find the assignment that takes effect. Edit that line rather than adding a new one or changing an assignment that is overridden later.
+
+hyprpaper.conf, hyprlock.conf, hypridle.conf and hyprsunset.conf next to hyprland.conf configure the wallpaper daemon, the lock screen, the idle daemon and the blue light filter. They use the same syntax as hyprland.conf but have their own options: look them up with getOptionInfo and explainOption with the program set, and never mix them with Hyprland's options. Writes to them are checked against their own schema. After writing hyprpaper.conf, hypridle.conf or hyprsunset.conf, call restartDaemon for that program so the change takes effect.
+
+Requests about idling, locking or sleep go to hypridle.conf. For example "lock my screen after 5 minutes idle" is a listener block with timeout = 300 and on-timeout = loginctl lock-session, with lock_cmd = pidof hyprlock || hyprlock in the general block so only one hyprlock is started. If hypridle.conf does not exist, create it, and start hypridle with exec-once = hypridle in hyprland.conf unless it already runs as a service. The look of the lock screen is configured in hyprlock.conf.
+
+Edit waybar's config.jsonc with getWaybarConfig, setWaybarOption, addWaybarModule and removeWaybarModule instead of readFile and writeFile. They keep the user's comments and layout and check the result before it is written. A custom/ module needs its own config object with at least an exec or format, set with setWaybarOption.
+
+After a write to a Hyprland config the response may include a "lint" field listing problems in that file, such as undefined variables or missing source targets. Fix them before concluding.
 
 **CRITICAL WORKFLOW REQUIREMENT:** 
 When a user requests ANY configuration change that requires modifying files, you MUST follow this exact sequence:
@@ -71,9 +103,40 @@ The user has declined to allow this operation. Please respect their decision and
 
 Continue helping the user with their Hyprland configuration while respecting their preferences and security concerns.`
 
-func GetSystemPrompt(tree []string) string {
-	treeStr := strings.Join(tree, "\n")
-	return fmt.Sprintf(SystemPrompt, treeStr)
+const NoUserInputPrompt = `The user is not available to answer questions in this run. Continue with your best judgement, choosing the least invasive option, and explain your assumptions in the conclusion.`
+
+const UserEditedFilePrompt = `The user edited your proposed content before it was written. The file now contains the user's version, not yours. This is how the user changed your proposal:
+
+%s
+Respect these edits in any further changes to this file.`
+
+const UserEditedCommandPrompt = `The user edited the command before running it.
+
+**Proposed:** %s
+**Executed:** %s
+
+The result below is from the executed command.`
+
+const InvalidOptionsPrompt = `The write to %s was not applied and the user was not asked, because the program that reads it would reject these options:
+
+%s
+
+Check the options with getOptionInfo or searchDocs, fix the values and call writeFile again with the complete content.`
+
+const InvalidWaybarPrompt = `The write to %s was not applied and the user was not asked, because waybar could not read it:
+
+%s
+
+Use setWaybarOption, addWaybarModule and removeWaybarModule to edit waybar's config instead of writeFile.`
+
+const DryRunResult = `Dry run: the change was recorded in the proposed change set but has not been applied. Continue as if it succeeded.`
+
+func GetSystemPrompt(tree []config.TreeEntry) string {
+	lines := make([]string, len(tree))
+	for i, entry := range tree {
+		lines[i] = entry.String()
+	}
+	return fmt.Sprintf(SystemPrompt, strings.Join(lines, "\n"))
 }
 
 func GetUserInputPrompt(userResponse string) string {
@@ -83,3 +146,19 @@ func GetUserInputPrompt(userResponse string) string {
 func GetPermissionDeniedPrompt(toolName, parameters string) string {
 	return fmt.Sprintf(PermissionDeniedPrompt, toolName, parameters)
 }
+
+func GetUserEditedFilePrompt(userDiff string) string {
+	return fmt.Sprintf(UserEditedFilePrompt, userDiff)
+}
+
+func GetUserEditedCommandPrompt(proposed, executed string) string {
+	return fmt.Sprintf(UserEditedCommandPrompt, proposed, executed)
+}
+
+func GetInvalidOptionsPrompt(path string, problems []string) string {
+	return fmt.Sprintf(InvalidOptionsPrompt, path, strings.Join(problems, "\n"))
+}
+
+func GetInvalidWaybarPrompt(path string, err error) string {
+	return fmt.Sprintf(InvalidWaybarPrompt, path, err)
+}
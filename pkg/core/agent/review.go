@@ -0,0 +1,114 @@
+package agent
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/saat-sy/hyprlander/pkg/core/tools"
+	"github.com/saat-sy/hyprlander/pkg/diff"
+	"github.com/saat-sy/hyprlander/pkg/ui"
+	"google.golang.org/genai"
+)
+
+type hunkReview struct {
+	content  string
+	applied  []int
+	edited   []int
+	rejected []diff.Hunk
+}
+
+// reviewHunks walks the user through a proposed write one hunk at a time, like
+// git add -p, and rewrites the call's content to the accepted result.
+func (a *Agent) reviewHunks(funcCall *genai.FunctionCall) (*hunkReview, error) {
+	path, ok := funcCall.Args["path"].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid path parameter for writeFile")
+	}
+	content, ok := funcCall.Args["content"].(string)
+	if !ok {
+		return nil, fmt.Errorf("invalid content parameter for writeFile")
+	}
+
+	original, err := tools.ReadFile(path)
+	if err != nil {
+		original = ""
+	}
+
+	hunks := diff.Hunks(diff.Lines(original, content), diff.DefaultContext)
+	review := &hunkReview{}
+	replacements := make([][]string, len(hunks))
+	remaining := ui.Reject
+	answeredRemaining := false
+
+	for i, hunk := range hunks {
+		decision := remaining
+		if !answeredRemaining {
+			decision, err = a.ui.ConfirmHunk(path, hunk, i, len(hunks))
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		switch decision {
+		case ui.ApproveRemaining, ui.RejectRemaining:
+			answeredRemaining = true
+			remaining = decision
+		}
+
+		switch decision {
+		case ui.Approve, ui.ApproveRemaining:
+			replacements[i] = hunk.NewText()
+			review.applied = append(review.applied, i+1)
+		case ui.Edit:
+			edited, err := a.ui.Edit(path, strings.Join(hunk.NewText(), "\n")+"\n")
+			if err != nil {
+				return nil, err
+			}
+			// An emptied hunk deletes its lines rather than leaving one
+			// blank line behind.
+			replacements[i] = nil
+			if edited = strings.TrimSuffix(edited, "\n"); edited != "" {
+				replacements[i] = strings.Split(edited, "\n")
+			}
+			review.edited = append(review.edited, i+1)
+		default:
+			replacements[i] = hunk.OldText()
+			review.rejected = append(review.rejected, hunk)
+		}
+	}
+
+	review.content = diff.Apply(original, hunks, replacements)
+	funcCall.Args["content"] = review.content
+	return review, nil
+}
+
+func (r *hunkReview) rejectedAll() bool {
+	return len(r.applied) == 0 && len(r.edited) == 0
+}
+
+// note explains to the model which parts of its change were kept.
+func (r *hunkReview) note() string {
+	var note strings.Builder
+	note.WriteString("The user reviewed your change hunk by hunk. The file now contains the content in applied_content.")
+	if len(r.applied) > 0 {
+		note.WriteString(fmt.Sprintf(" Applied hunks: %s.", joinInts(r.applied)))
+	}
+	if len(r.edited) > 0 {
+		note.WriteString(fmt.Sprintf(" Hunks edited by the user before applying: %s.", joinInts(r.edited)))
+	}
+	if len(r.rejected) > 0 {
+		note.WriteString(" The user rejected these hunks; do not reapply them unless asked:\n")
+		for _, hunk := range r.rejected {
+			note.WriteString(hunk.String())
+		}
+	}
+	return note.String()
+}
+
+func joinInts(values []int) string {
+	parts := make([]string, len(values))
+	for i, value := range values {
+		parts[i] = fmt.Sprint(value)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,179 @@
+package agent
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/saat-sy/hyprlander/pkg/config"
+	"github.com/saat-sy/hyprlander/pkg/core/tools"
+	"github.com/saat-sy/hyprlander/pkg/diff"
+	"github.com/saat-sy/hyprlander/pkg/hyprlang"
+	"github.com/saat-sy/hyprlander/pkg/lint"
+	"github.com/saat-sy/hyprlander/pkg/schema"
+	"github.com/saat-sy/hyprlander/pkg/waybar"
+	"google.golang.org/genai"
+)
+
+// formatWrite formats the content of a write to a Hyprland config, or to the
+// config of another hypr program, when format_on_write is set, before the
+// user sees the diff.
+func (a *Agent) formatWrite(funcCall *genai.FunctionCall) {
+	path, _ := funcCall.Args["path"].(string)
+	content, ok := funcCall.Args["content"].(string)
+	if !a.settings.FormatOnWrite || !ok || schema.ProgramOf(path) == "" {
+		return
+	}
+	funcCall.Args["content"] = hyprlang.Format(content)
+}
+
+// validateWrite checks the options in a proposed write to a hyprlang config
+// against the option schema of the program that reads it, and a write to
+// waybar's config against what waybar accepts. Invalid values are sent back
+// to the model instead of being shown to the user, but only on the lines the
+// write adds or changes, so a mistake already in the file does not block
+// unrelated edits. Unknown options and existing mistakes are only warned
+// about.
+func (a *Agent) validateWrite(funcCall *genai.FunctionCall) (string, bool) {
+	path, _ := funcCall.Args["path"].(string)
+	content, _ := funcCall.Args["content"].(string)
+	// The same file may be named by a relative or an absolute path.
+	path = absPath(path)
+	if a.isWaybarConfig(path) {
+		if err := waybar.Check(content); err != nil {
+			a.ui.PrintWarning(fmt.Sprintf("The proposed write to %s was sent back because waybar cannot read it: %v", path, err))
+			return GetInvalidWaybarPrompt(path, err), false
+		}
+		return "", true
+	}
+	if schema.ProgramOf(path) == "" {
+		return "", true
+	}
+
+	problems := schema.ValidateDocument(hyprlang.Parse(path, content), a.configVariables(path))
+	if len(problems) == 0 {
+		return "", true
+	}
+
+	changed := changedLines(a.currentContent(path), content)
+	var messages, rejected []string
+	for _, problem := range problems {
+		messages = append(messages, problem.String())
+		if problem.Severity == schema.SeverityError && changed[problem.Line] {
+			rejected = append(rejected, problem.String())
+		}
+	}
+
+	if len(rejected) == 0 {
+		a.ui.PrintWarning(fmt.Sprintf("%s may contain mistakes:\n%s", path, strings.Join(messages, "\n")))
+		return "", true
+	}
+
+	a.ui.PrintWarning(fmt.Sprintf("The proposed write to %s was sent back because it has invalid options:\n%s", path, strings.Join(rejected, "\n")))
+	return GetInvalidOptionsPrompt(path, rejected), false
+}
+
+// currentContent is what the file at the absolute path holds before a write:
+// the content proposed earlier in a dry run, or the file on disk, or "" for a
+// new file.
+func (a *Agent) currentContent(path string) string {
+	if content, ok := a.proposedFiles[path]; ok {
+		return content
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return string(content)
+}
+
+// changedLines returns the line numbers of content, from 1, that are not in
+// original.
+func changedLines(original, content string) map[int]bool {
+	changed := make(map[int]bool)
+	line := 0
+	for _, op := range diff.Lines(original, content) {
+		switch op.Kind {
+		case diff.Equal:
+			line++
+		case diff.Insert:
+			line++
+			changed[line] = true
+		}
+	}
+	return changed
+}
+
+// isWaybarConfig reports whether path is the config waybar reads.
+func (a *Agent) isWaybarConfig(path string) bool {
+	waybarPath, err := tools.WaybarConfigPath(a.settings.AppDirs)
+	if err != nil {
+		return false
+	}
+	abs, err := filepath.Abs(path)
+	return err == nil && abs == waybarPath
+}
+
+// lintWrite lints the config tree after a write to a hyprlang config and
+// returns the findings in the written file, so the model can fix what it
+// broke without being distracted by problems that were already there.
+func (a *Agent) lintWrite(funcCall *genai.FunctionCall) string {
+	path, _ := funcCall.Args["path"].(string)
+	if a.hyprlandDir == "" || schema.ProgramOf(path) == "" {
+		return ""
+	}
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return ""
+	}
+
+	target, err := lint.Load(a.hyprlandDir)
+	if err != nil {
+		return ""
+	}
+
+	var findings []lint.Finding
+	for _, finding := range lint.Run(target, lint.Rules()) {
+		if finding.File == path {
+			findings = append(findings, finding)
+		}
+	}
+	if len(findings) == 0 {
+		return ""
+	}
+
+	report := lint.FormatText(target, findings)
+	a.ui.PrintWarning(fmt.Sprintf("lint found problems in %s:\n%s", path, report))
+	return report
+}
+
+// configVariables collects the $variables defined by the other config files,
+// since a file often uses variables declared in hyprland.conf. The other hypr
+// programs do not see Hyprland's variables, so none are shared with them.
+// skip is the absolute path of the file being written.
+func (a *Agent) configVariables(skip string) map[string]string {
+	vars := make(map[string]string)
+	if a.hyprlandDir == "" || !schema.IsHyprlandConfig(skip) {
+		return vars
+	}
+
+	tree, err := config.GetTreeFromDir(a.hyprlandDir)
+	if err != nil {
+		return vars
+	}
+	for _, entry := range tree {
+		if absPath(entry.Path) == skip || !schema.IsHyprlandConfig(entry.Path) {
+			continue
+		}
+		doc, err := hyprlang.ParseFile(entry.Path)
+		if err != nil {
+			continue
+		}
+		for name, value := range doc.Variables() {
+			vars[name] = value
+		}
+	}
+
+	return vars
+}
@@ -0,0 +1,66 @@
+package tools
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/saat-sy/hyprlander/pkg/binds"
+	"github.com/saat-sy/hyprlander/pkg/config"
+	"google.golang.org/genai"
+)
+
+// ListBinds describes the keybindings of the config in hyprlandDir. With a
+// combo it only reports what is already bound to that combination.
+func ListBinds(hyprlandDir, combo, submap string) (string, error) {
+	inventory, err := binds.Load(filepath.Join(hyprlandDir, config.MainConfigFileName))
+	if err != nil {
+		return "", err
+	}
+
+	if combo != "" {
+		mods, key, err := binds.ParseCombo(combo)
+		if err != nil {
+			return "", err
+		}
+		existing := inventory.Find(mods, key, submap)
+		if len(existing) == 0 {
+			return fmt.Sprintf("%s is not bound yet.", binds.FormatCombo(mods, key)), nil
+		}
+
+		lines := []string{fmt.Sprintf("%s is already bound:", binds.FormatCombo(mods, key))}
+		for _, bind := range existing {
+			lines = append(lines, fmt.Sprintf("- %s (%s)", bind.Action(), bind.Location()))
+		}
+		lines = append(lines, "Hyprland runs every bind on the same keys, so change or unbind the existing one instead of adding another.")
+		return strings.Join(lines, "\n"), nil
+	}
+
+	output := inventory.Format(hyprlandDir)
+	if len(inventory.Issues) > 0 {
+		output += "\n\nProblems:\n" + binds.FormatIssues(inventory.Issues, hyprlandDir)
+	}
+	return output, nil
+}
+
+var BindsTool = &genai.Tool{
+	FunctionDeclarations: []*genai.FunctionDeclaration{
+		{
+			Name:        "listBinds",
+			Description: "Lists the keybindings of the user's Hyprland config across all sourced files, with $variables resolved, and reports duplicated or conflicting binds and unknown dispatchers. Pass a combo to check whether a key combination is free before adding a bind with writeFile.",
+			Parameters: &genai.Schema{
+				Type: genai.TypeObject,
+				Properties: map[string]*genai.Schema{
+					"combo": {
+						Type:        genai.TypeString,
+						Description: "Optional key combination to check, e.g. 'SUPER+T' or 'SUPER SHIFT, Q'.",
+					},
+					"submap": {
+						Type:        genai.TypeString,
+						Description: "Optional submap the combo belongs to. Defaults to the global binds.",
+					},
+				},
+			},
+		},
+	},
+}
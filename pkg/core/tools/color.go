@@ -0,0 +1,147 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/saat-sy/hyprlander/pkg/color"
+	"google.golang.org/genai"
+)
+
+// colorOperations are the adjustments adjustColor supports. Amounts are
+// fractions (0.2 is 20%), except rotate which takes degrees.
+var colorOperations = map[string]func(c color.Color, amount float64) color.Color{
+	"lighten":    color.Color.Lighten,
+	"darken":     color.Color.Darken,
+	"saturate":   color.Color.Saturate,
+	"desaturate": color.Color.Desaturate,
+	"rotate":     color.Color.RotateHue,
+	"alpha":      color.Color.WithAlpha,
+}
+
+// parseColors reads a single color, including CSS hex, or a gradient.
+func parseColors(value string) (color.Gradient, error) {
+	if c, err := color.ParseAny(value); err == nil {
+		return color.Gradient{Colors: []color.Color{c}}, nil
+	}
+	return color.ParseGradient(value)
+}
+
+// ConvertColor shows a color or gradient in every notation.
+func ConvertColor(value string) (string, error) {
+	gradient, err := parseColors(value)
+	if err != nil {
+		return "", err
+	}
+
+	lines := []string{"hyprland: " + gradient.String()}
+	for _, c := range gradient.Colors {
+		lines = append(lines, fmt.Sprintf("- %s  hex %s  %s  %s  alpha %.2f", c, c.Hex(), c.FormatHSL(), c.FormatOKLCH(), c.A))
+	}
+	if gradient.Angle != "" {
+		lines = append(lines, "angle: "+gradient.Angle)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// AdjustColor applies an operation to every color of a color or gradient and
+// returns the result in Hyprland syntax.
+func AdjustColor(value, operation string, amount float64) (string, error) {
+	adjust, ok := colorOperations[operation]
+	if !ok {
+		return "", fmt.Errorf("unknown operation %q, expected lighten, darken, saturate, desaturate, rotate or alpha", operation)
+	}
+	if operation != "rotate" && (amount < 0 || amount > 1) {
+		return "", fmt.Errorf("amount must be a fraction between 0 and 1, e.g. 0.2 for 20%%")
+	}
+
+	gradient, err := parseColors(value)
+	if err != nil {
+		return "", err
+	}
+	return gradient.Map(func(c color.Color) color.Color { return adjust(c, amount) }).String(), nil
+}
+
+// ContrastRatio reports the WCAG contrast between two colors.
+func ContrastRatio(foreground, background string) (string, error) {
+	fg, err := color.ParseAny(foreground)
+	if err != nil {
+		return "", err
+	}
+	bg, err := color.ParseAny(background)
+	if err != nil {
+		return "", err
+	}
+
+	ratio := color.ContrastRatio(fg, bg)
+	rating := "fails WCAG AA for text"
+	switch {
+	case ratio >= 7:
+		rating = "passes WCAG AAA"
+	case ratio >= 4.5:
+		rating = "passes WCAG AA"
+	case ratio >= 3:
+		rating = "passes WCAG AA for large text and UI elements such as borders only"
+	}
+	return fmt.Sprintf("%.2f:1, %s", ratio, rating), nil
+}
+
+var ColorTool = &genai.Tool{
+	FunctionDeclarations: []*genai.FunctionDeclaration{
+		{
+			Name:        "convertColor",
+			Description: "Parses a Hyprland color (rgba(RRGGBBAA), rgb(RRGGBB), 0xAARRGGBB, rgba(r, g, b, a)), a CSS hex color or a gradient such as 'rgba(33ccffee) rgba(00ff99ee) 45deg', and shows each color as Hyprland rgba, hex, HSL and OKLCH.",
+			Parameters: &genai.Schema{
+				Type: genai.TypeObject,
+				Properties: map[string]*genai.Schema{
+					"value": {
+						Type:        genai.TypeString,
+						Description: "The color or gradient.",
+					},
+				},
+				Required: []string{"value"},
+			},
+		},
+		{
+			Name:        "adjustColor",
+			Description: "Adjusts every color of a color or gradient and returns it in Hyprland syntax, keeping the gradient angle. Use it instead of computing hex values yourself, e.g. operation 'darken' with amount 0.2 for '20% darker'.",
+			Parameters: &genai.Schema{
+				Type: genai.TypeObject,
+				Properties: map[string]*genai.Schema{
+					"value": {
+						Type:        genai.TypeString,
+						Description: "The color or gradient to adjust.",
+					},
+					"operation": {
+						Type:        genai.TypeString,
+						Description: "One of lighten, darken, saturate, desaturate (amount 0-1), rotate (amount in degrees of hue) or alpha (sets the opacity, 0-1).",
+						Enum:        []string{"lighten", "darken", "saturate", "desaturate", "rotate", "alpha"},
+					},
+					"amount": {
+						Type:        genai.TypeNumber,
+						Description: "Fraction for most operations (0.2 is 20%), degrees for rotate.",
+					},
+				},
+				Required: []string{"value", "operation", "amount"},
+			},
+		},
+		{
+			Name:        "contrastRatio",
+			Description: "Computes the WCAG contrast ratio between a foreground and a background color, e.g. a border against the wallpaper or bar text against its background.",
+			Parameters: &genai.Schema{
+				Type: genai.TypeObject,
+				Properties: map[string]*genai.Schema{
+					"foreground": {
+						Type:        genai.TypeString,
+						Description: "The foreground color.",
+					},
+					"background": {
+						Type:        genai.TypeString,
+						Description: "The background color.",
+					},
+				},
+				Required: []string{"foreground", "background"},
+			},
+		},
+	},
+}
@@ -0,0 +1,39 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/saat-sy/hyprlander/pkg/daemon"
+	"github.com/saat-sy/hyprlander/pkg/schema"
+	"google.golang.org/genai"
+)
+
+// RestartDaemon makes a hypr program pick up its changed config.
+func RestartDaemon(ctx context.Context, controller *daemon.Controller, program string) (string, error) {
+	parsed, ok := schema.ParseProgram(program)
+	if !ok {
+		return "", fmt.Errorf("unknown program %q", program)
+	}
+	return controller.Restart(ctx, parsed)
+}
+
+var DaemonTool = &genai.Tool{
+	FunctionDeclarations: []*genai.FunctionDeclaration{
+		{
+			Name:        "restartDaemon",
+			Description: "Makes a hypr program apply its changed config: reloads Hyprland with hyprctl reload, and restarts hyprpaper, hypridle or hyprsunset through their systemd user service or through Hyprland. hyprlock needs no restart because it reads its config each time it starts. Use it after writing the program's config, not for Hyprland itself, which reloads on its own when its config is saved.",
+			Parameters: &genai.Schema{
+				Type: genai.TypeObject,
+				Properties: map[string]*genai.Schema{
+					"program": {
+						Type:        genai.TypeString,
+						Description: "The program to restart.",
+						Enum:        []string{"hyprland", "hyprpaper", "hyprlock", "hypridle", "hyprsunset"},
+					},
+				},
+				Required: []string{"program"},
+			},
+		},
+	},
+}
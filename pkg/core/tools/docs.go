@@ -0,0 +1,77 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/saat-sy/hyprlander/pkg/docs"
+	"google.golang.org/genai"
+)
+
+const (
+	docSearchResults = 5
+	docSnippetLength = 1500
+)
+
+func SearchDocs(query string) (string, error) {
+	results := docs.Search(query, docSearchResults)
+	if len(results) == 0 {
+		return fmt.Sprintf("No documentation found for %q. Try other words, or getDocPage with one of the pages.", query), nil
+	}
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "Hyprland wiki %s, best matches for %q:\n", docs.Version(), query)
+	for _, result := range results {
+		fmt.Fprintf(&builder, "\n## %s (page: %s)\n%s\n", result.Section.Title(), result.Section.Page, result.Snippet(query, docSnippetLength))
+	}
+	return builder.String(), nil
+}
+
+func GetDocPage(name string) (string, error) {
+	page, err := docs.GetPage(name)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Hyprland wiki %s, page %s:\n\n%s", docs.Version(), page.Name, page.Content), nil
+}
+
+func docPageNames() string {
+	var names []string
+	for _, page := range docs.Pages() {
+		names = append(names, page.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+var DocsTool = &genai.Tool{
+	FunctionDeclarations: []*genai.FunctionDeclaration{
+		{
+			Name:        "searchDocs",
+			Description: "Searches an offline snapshot of the Hyprland wiki (variables, dispatchers, window rules, keywords, binds, monitors, animations) and returns the best matching sections. Use it to check option names, types and defaults before writing them.",
+			Parameters: &genai.Schema{
+				Type: genai.TypeObject,
+				Properties: map[string]*genai.Schema{
+					"query": {
+						Type:        genai.TypeString,
+						Description: "Words or option names to look for (e.g. 'blur passes' or 'col.active_border').",
+					},
+				},
+				Required: []string{"query"},
+			},
+		},
+		{
+			Name:        "getDocPage",
+			Description: "Returns a full page of the offline Hyprland wiki snapshot. Available pages: " + docPageNames() + ".",
+			Parameters: &genai.Schema{
+				Type: genai.TypeObject,
+				Properties: map[string]*genai.Schema{
+					"name": {
+						Type:        genai.TypeString,
+						Description: "The page name (e.g. 'Window-Rules').",
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+	},
+}
@@ -0,0 +1,56 @@
+package tools
+
+import (
+	"path/filepath"
+
+	"github.com/saat-sy/hyprlander/pkg/explain"
+	"github.com/saat-sy/hyprlander/pkg/hyprlang"
+	"google.golang.org/genai"
+)
+
+// ExplainOption reports every assignment of an option in the config in
+// hyprlandDir and which one takes effect. program selects the hypr program
+// whose config is read; it is detected from the option when empty.
+func ExplainOption(hyprlandDir, option, program string) (string, error) {
+	p, err := explain.Program(option, program)
+	if err != nil {
+		return "", err
+	}
+	cfg, err := hyprlang.LoadConfig(filepath.Join(hyprlandDir, p.ConfigFileName()))
+	if err != nil {
+		return "", err
+	}
+
+	explanation, err := explain.ExplainIn(cfg, p, option)
+	if err != nil {
+		return "", err
+	}
+	return explanation.Format(hyprlandDir), nil
+}
+
+var ExplainTool = &genai.Tool{
+	FunctionDeclarations: []*genai.FunctionDeclaration{
+		{
+			Name:        "explainOption",
+			Description: "Shows where a config option is set in the user's Hyprland config across all sourced files, with $variables expanded, which assignment takes effect (the last one Hyprland reads), and the default. Use it before changing an option so you edit the assignment that is actually in effect instead of one that is overridden later. Also works for hyprpaper, hyprlock, hypridle and hyprsunset options, where repeatable blocks such as listener keep their own values.",
+			Parameters: &genai.Schema{
+				Type: genai.TypeObject,
+				Properties: map[string]*genai.Schema{
+					"option": {
+						Type:        genai.TypeString,
+						Description: "The option, e.g. 'general:border_size' or just 'border_size'.",
+					},
+					"program": programParameter,
+				},
+				Required: []string{"option"},
+			},
+		},
+	},
+}
+
+// programParameter selects the hypr program an option belongs to.
+var programParameter = &genai.Schema{
+	Type:        genai.TypeString,
+	Description: "The program whose config the option is in. Leave it out to detect it from the option name.",
+	Enum:        []string{"hyprland", "hyprpaper", "hyprlock", "hypridle", "hyprsunset"},
+}
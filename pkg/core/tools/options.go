@@ -0,0 +1,70 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/saat-sy/hyprlander/pkg/schema"
+	"google.golang.org/genai"
+)
+
+// GetOptionInfo describes the options matching name in the schema of
+// program, or when program is empty in Hyprland's schema and then in those of
+// the other hypr programs.
+func GetOptionInfo(name, program string) (string, error) {
+	target := schema.Hyprland
+	if program != "" {
+		parsed, ok := schema.ParseProgram(program)
+		if !ok {
+			return "", fmt.Errorf("unknown program %q", program)
+		}
+		target = parsed
+	}
+
+	found := schema.FindIn(target, name)
+	if len(found) == 0 && program == "" {
+		for _, p := range schema.Ecosystem() {
+			found = append(found, schema.FindIn(p, name)...)
+		}
+	}
+	if len(found) == 0 {
+		message := fmt.Sprintf("%s is not a known %s option", name, displayName(target))
+		if suggestions := schema.SuggestIn(target, name, 5); len(suggestions) > 0 {
+			message += ". Did you mean: " + strings.Join(suggestions, ", ")
+		}
+		return message, nil
+	}
+
+	descriptions := make([]string, len(found))
+	for i, option := range found {
+		descriptions[i] = schema.Describe(option)
+	}
+	return strings.Join(descriptions, "\n\n"), nil
+}
+
+var OptionInfoTool = &genai.Tool{
+	FunctionDeclarations: []*genai.FunctionDeclaration{
+		{
+			Name:        "getOptionInfo",
+			Description: "Returns the type, default value, allowed range or values and deprecation status of a Hyprland config option, or of a hyprpaper, hyprlock, hypridle or hyprsunset option. Values written with writeFile are checked against the same information.",
+			Parameters: &genai.Schema{
+				Type: genai.TypeObject,
+				Properties: map[string]*genai.Schema{
+					"name": {
+						Type:        genai.TypeString,
+						Description: "The full option path (e.g. 'decoration:blur:size') or just its name (e.g. 'border_size').",
+					},
+					"program": programParameter,
+				},
+				Required: []string{"name"},
+			},
+		},
+	},
+}
+
+func displayName(program schema.Program) string {
+	if program == schema.Hyprland {
+		return "Hyprland"
+	}
+	return string(program)
+}
@@ -0,0 +1,228 @@
+package tools
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/saat-sy/hyprlander/pkg/color"
+	"github.com/saat-sy/hyprlander/pkg/config"
+	"github.com/saat-sy/hyprlander/pkg/hyprlang"
+	"github.com/saat-sy/hyprlander/pkg/snapshot"
+	"github.com/saat-sy/hyprlander/pkg/theme"
+	"google.golang.org/genai"
+)
+
+// WallpaperThemeName derives the palette from the wallpaper instead of a
+// bundled theme.
+const WallpaperThemeName = "wallpaper"
+
+// LoadPalette returns a bundled theme, or the palette of the wallpaper for
+// WallpaperThemeName. image overrides the wallpaper set in hyprpaper.conf.
+func LoadPalette(hyprlandDir, name, image string) (theme.Palette, error) {
+	if name != WallpaperThemeName {
+		return theme.Get(name)
+	}
+	palette, _, err := WallpaperPalette(hyprlandDir, image, "")
+	return palette, err
+}
+
+// WallpaperPalette extracts a palette from image, or from the wallpaper in
+// hyprpaper.conf when image is empty.
+func WallpaperPalette(hyprlandDir, image, variant string) (theme.Palette, []theme.Swatch, error) {
+	if image == "" {
+		path, err := theme.WallpaperPath(hyprlandDir, "")
+		if err != nil {
+			return theme.Palette{}, nil, err
+		}
+		image = path
+	}
+	return theme.FromImage(image, variant)
+}
+
+// ThemeTargets are the configs a theme is written to.
+type ThemeTargets struct {
+	HyprlandDir string
+	// Apps names the apps to theme, including theme.HyprlandApp for the
+	// Hyprland config, or theme.AllApps. Empty means only Hyprland.
+	Apps []string
+	// AppDirs replaces the default config directory of an app.
+	AppDirs map[string]string
+}
+
+// PlanTheme computes the changes that apply a palette to the targets without
+// writing them.
+func PlanTheme(targets ThemeTargets, palette theme.Palette) ([]hyprlang.FileChange, error) {
+	names := targets.Apps
+	if len(names) == 0 {
+		names = []string{theme.HyprlandApp}
+	}
+	if slices.Contains(names, theme.AllApps) {
+		names = []string{theme.HyprlandApp}
+		for _, app := range theme.Apps() {
+			if app.Installed(targets.AppDirs) {
+				names = append(names, app.Name)
+			}
+		}
+	}
+
+	var changes []hyprlang.FileChange
+	seen := make(map[string]bool)
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		if name == theme.HyprlandApp {
+			cfg, err := hyprlang.LoadConfig(filepath.Join(targets.HyprlandDir, config.MainConfigFileName))
+			if err != nil {
+				return nil, err
+			}
+			changes = append(changes, theme.Plan(cfg, palette)...)
+			continue
+		}
+
+		app, err := theme.FindApp(name)
+		if err != nil {
+			return nil, err
+		}
+		change, err := app.Plan(palette, targets.AppDirs)
+		if err != nil {
+			return nil, err
+		}
+		if change != nil {
+			changes = append(changes, *change)
+		}
+	}
+	return changes, nil
+}
+
+// WriteChanges snapshots the files and writes the changes, returning the
+// snapshot that undoes them.
+func WriteChanges(reason string, changes []hyprlang.FileChange) (*snapshot.Snapshot, error) {
+	dir, err := config.GetSnapshotsDirectory()
+	if err != nil {
+		return nil, fmt.Errorf("could not determine snapshot directory: %w", err)
+	}
+	paths := make([]string, len(changes))
+	for i, change := range changes {
+		paths[i] = change.Path
+	}
+	snap, err := snapshot.Create(dir, reason, paths)
+	if err != nil {
+		return nil, fmt.Errorf("could not snapshot files: %w", err)
+	}
+
+	for _, change := range changes {
+		if err := WriteFile(change.Path, change.Content); err != nil {
+			return snap, err
+		}
+	}
+	return snap, nil
+}
+
+// ApplyTheme writes a bundled theme or the wallpaper palette to the targets.
+func ApplyTheme(targets ThemeTargets, name, image string) (string, error) {
+	palette, err := LoadPalette(targets.HyprlandDir, name, image)
+	if err != nil {
+		return "", err
+	}
+	changes, err := PlanTheme(targets, palette)
+	if err != nil {
+		return "", err
+	}
+	if len(changes) == 0 {
+		return fmt.Sprintf("The config already uses the %s colors.", name), nil
+	}
+
+	snap, err := WriteChanges("theme apply "+name, changes)
+	if err != nil {
+		return "", err
+	}
+
+	var paths []string
+	for _, change := range changes {
+		paths = append(paths, change.Path)
+	}
+	return fmt.Sprintf("Applied theme %s to %s. The previous files are in snapshot %s.", name, strings.Join(paths, ", "), snap.ID), nil
+}
+
+// DescribeWallpaperPalette lists the colors extracted from the wallpaper and
+// the roles they were given.
+func DescribeWallpaperPalette(hyprlandDir, image string) (string, error) {
+	palette, swatches, err := WallpaperPalette(hyprlandDir, image, "")
+	if err != nil {
+		return "", err
+	}
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "%s, %s variant\n\nDominant colors:\n", palette.Title, palette.Variant)
+	for _, swatch := range swatches {
+		fmt.Fprintf(&builder, "  %s %3.0f%%\n", swatch.Color.String(), swatch.Population*100)
+	}
+	builder.WriteString("\nRoles (contrast against the background):\n")
+	for _, role := range palette.Roles() {
+		fmt.Fprintf(&builder, "  %-11s %s %4.1f:1\n", role.Name, role.Color.String(), color.ContrastRatio(role.Color, palette.Background))
+	}
+	return builder.String(), nil
+}
+
+func appNames() string {
+	names := []string{theme.HyprlandApp}
+	for _, app := range theme.Apps() {
+		names = append(names, app.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+func themeNames() string {
+	palettes, _ := theme.List()
+	var names []string
+	for _, palette := range palettes {
+		names = append(names, palette.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+var ThemeTool = &genai.Tool{
+	FunctionDeclarations: []*genai.FunctionDeclaration{
+		{
+			Name:        "applyTheme",
+			Description: "Applies a bundled color theme or the colors of the wallpaper to the user's Hyprland config, setting the active and inactive borders, group borders and groupbars, shadow and background colors, and optionally to the bar, terminal, notification and launcher configs. Each option is changed where it takes effect. The files are snapshotted first. Available themes: " + themeNames() + ", or '" + WallpaperThemeName + "'.",
+			Parameters: &genai.Schema{
+				Type: genai.TypeObject,
+				Properties: map[string]*genai.Schema{
+					"name": {
+						Type:        genai.TypeString,
+						Description: "The theme name, e.g. 'catppuccin-mocha', or '" + WallpaperThemeName + "' to use the wallpaper's colors.",
+					},
+					"image": {
+						Type:        genai.TypeString,
+						Description: "Optional image to take the colors from with the '" + WallpaperThemeName + "' theme. Defaults to the wallpaper in hyprpaper.conf.",
+					},
+					"apps": {
+						Type:        genai.TypeArray,
+						Items:       &genai.Schema{Type: genai.TypeString},
+						Description: "Optional configs to write the theme to: " + appNames() + ", or '" + theme.AllApps + "' for Hyprland and every app that has a config. Defaults to only Hyprland.",
+					},
+				},
+				Required: []string{"name"},
+			},
+		},
+		{
+			Name:        "getWallpaperPalette",
+			Description: "Extracts the dominant colors of the wallpaper set in hyprpaper.conf, or of another image, and the background, foreground, accent and border colors derived from them with their contrast. Use it whenever colors should match the wallpaper instead of guessing them.",
+			Parameters: &genai.Schema{
+				Type: genai.TypeObject,
+				Properties: map[string]*genai.Schema{
+					"image": {
+						Type:        genai.TypeString,
+						Description: "Optional path of the image. Defaults to the wallpaper in hyprpaper.conf.",
+					},
+				},
+			},
+		},
+	},
+}
@@ -14,6 +14,14 @@ func NewConfigForTools() *Tools {
 			FileReaderTool,
 			FileWriterTool,
 			ShellExecutorTool,
+			DocsTool,
+			OptionInfoTool,
+			BindsTool,
+			ExplainTool,
+			ColorTool,
+			ThemeTool,
+			DaemonTool,
+			WaybarTool,
 		},
 	}
 
@@ -0,0 +1,200 @@
+package tools
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/saat-sy/hyprlander/pkg/hyprlang"
+	"github.com/saat-sy/hyprlander/pkg/theme"
+	"github.com/saat-sy/hyprlander/pkg/waybar"
+	"google.golang.org/genai"
+)
+
+// WaybarConfigPath finds waybar's config in the directory app_dirs.waybar
+// names, or under $XDG_CONFIG_HOME.
+func WaybarConfigPath(appDirs map[string]string) (string, error) {
+	app, err := theme.FindApp("waybar")
+	if err != nil {
+		return "", err
+	}
+	style, err := app.Path(appDirs)
+	if err != nil {
+		return "", err
+	}
+	return waybar.FindConfig(filepath.Dir(style))
+}
+
+// PlanWaybarEdit applies edit to waybar's config and checks that waybar can
+// still read the result. It returns nil if nothing changes.
+func PlanWaybarEdit(appDirs map[string]string, edit func(*waybar.Config) error) (*hyprlang.FileChange, error) {
+	path, err := WaybarConfigPath(appDirs)
+	if err != nil {
+		return nil, err
+	}
+	original, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := waybar.Parse(string(original))
+	if err != nil {
+		return nil, fmt.Errorf("%s cannot be read, fix it first: %w", path, err)
+	}
+
+	if err := edit(cfg); err != nil {
+		return nil, err
+	}
+	content := cfg.String()
+	if err := waybar.Check(content); err != nil {
+		return nil, fmt.Errorf("the change would leave %s invalid: %w", path, err)
+	}
+	if content == string(original) {
+		return nil, nil
+	}
+	return &hyprlang.FileChange{Path: path, Original: string(original), Content: content}, nil
+}
+
+// WriteWaybarChange writes a planned change to a bar in waybar's config.
+func WriteWaybarChange(reason string, change *hyprlang.FileChange, bar int) (string, error) {
+	if change == nil {
+		return "waybar's config already has that, nothing was changed.", nil
+	}
+	snap, err := WriteChanges(reason, []hyprlang.FileChange{*change})
+	if err != nil {
+		return "", err
+	}
+
+	result := fmt.Sprintf("Updated %s. The previous file is in snapshot %s. Waybar only reads its config at start; reload it with pkill -SIGUSR2 waybar.", change.Path, snap.ID)
+	if cfg, err := waybar.Parse(change.Content); err == nil {
+		if missing := cfg.Unconfigured(bar); len(missing) > 0 {
+			verb := "have"
+			if len(missing) == 1 {
+				verb = "has"
+			}
+			result += fmt.Sprintf(" %s %s no configuration yet and will not show until one is added with setWaybarOption.", strings.Join(missing, ", "), verb)
+		}
+	}
+	return result, nil
+}
+
+// DescribeWaybar lists where waybar's config is and the modules of each bar.
+func DescribeWaybar(appDirs map[string]string) (string, error) {
+	path, err := WaybarConfigPath(appDirs)
+	if err != nil {
+		return "", err
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	cfg, err := waybar.Parse(string(content))
+	if err != nil {
+		return "", fmt.Errorf("%s cannot be read: %w", path, err)
+	}
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "Config: %s\n", path)
+	for bar := 0; ; bar++ {
+		if _, ok := cfg.Get(bar, nil); !ok {
+			break
+		}
+		fmt.Fprintf(&builder, "\nBar %d:\n", bar)
+		for _, section := range waybar.Sections {
+			modules, err := cfg.Modules(bar, section)
+			if err != nil {
+				return "", err
+			}
+			fmt.Fprintf(&builder, "  %s: %s\n", section, strings.Join(modules, ", "))
+		}
+		if missing := cfg.Unconfigured(bar); len(missing) > 0 {
+			fmt.Fprintf(&builder, "  without configuration: %s\n", strings.Join(missing, ", "))
+		}
+	}
+	if err := cfg.Validate(); err != nil {
+		fmt.Fprintf(&builder, "\nProblem: %v\n", err)
+	}
+	return strings.TrimRight(builder.String(), "\n"), nil
+}
+
+// barParameter selects a bar in configs that define several.
+var barParameter = &genai.Schema{
+	Type:        genai.TypeInteger,
+	Description: "The bar to edit when the config is an array of bars, counting from 0. Defaults to 0.",
+}
+
+var WaybarTool = &genai.Tool{
+	FunctionDeclarations: []*genai.FunctionDeclaration{
+		{
+			Name:        "getWaybarConfig",
+			Description: "Shows where waybar's config is and the modules in modules-left, modules-center and modules-right of each bar. Read the file with readFile for the module settings.",
+		},
+		{
+			Name:        "setWaybarOption",
+			Description: "Sets or removes an option in waybar's config.jsonc without touching its comments or layout, such as the bar's height or a module's format. Missing objects on the path are created. The result is checked before the user is asked to approve it. Use this instead of writeFile for waybar's config.",
+			Parameters: &genai.Schema{
+				Type: genai.TypeObject,
+				Properties: map[string]*genai.Schema{
+					"path": {
+						Type:        genai.TypeArray,
+						Items:       &genai.Schema{Type: genai.TypeString},
+						Description: "Keys from the bar down to the option, e.g. ['height'] or ['clock', 'format']. Array elements are addressed by their index.",
+					},
+					"value": {
+						Type:        genai.TypeString,
+						Description: "The new value as JSON, e.g. 30, true, [\"a\", \"b\"] or {\"format\": \"{}\"}. Text that is not valid JSON is stored as a string, so formats can be given as they are.",
+					},
+					"remove": {
+						Type:        genai.TypeBoolean,
+						Description: "Remove the option instead of setting it.",
+					},
+					"bar": barParameter,
+				},
+				Required: []string{"path"},
+			},
+		},
+		{
+			Name:        "addWaybarModule",
+			Description: "Adds a module such as clock, pulseaudio or custom/power to modules-left, modules-center or modules-right of waybar's config, keeping comments and layout. Custom modules also need their settings, set with setWaybarOption.",
+			Parameters: &genai.Schema{
+				Type: genai.TypeObject,
+				Properties: map[string]*genai.Schema{
+					"module": {
+						Type:        genai.TypeString,
+						Description: "The module name, e.g. 'battery' or 'hyprland/workspaces'.",
+					},
+					"section": {
+						Type: genai.TypeString,
+						Enum: waybar.Sections,
+					},
+					"position": {
+						Type:        genai.TypeInteger,
+						Description: "Index to insert the module at, counting from 0. Leave it out to add the module at the end.",
+					},
+					"bar": barParameter,
+				},
+				Required: []string{"module", "section"},
+			},
+		},
+		{
+			Name:        "removeWaybarModule",
+			Description: "Removes a module from modules-left, modules-center or modules-right of waybar's config, keeping comments and layout. Its settings stay in the config.",
+			Parameters: &genai.Schema{
+				Type: genai.TypeObject,
+				Properties: map[string]*genai.Schema{
+					"module": {
+						Type:        genai.TypeString,
+						Description: "The module name.",
+					},
+					"section": {
+						Type:        genai.TypeString,
+						Description: "The section to remove it from. Leave it out to remove it from every section.",
+						Enum:        waybar.Sections,
+					},
+					"bar": barParameter,
+				},
+				Required: []string{"module"},
+			},
+		},
+	},
+}
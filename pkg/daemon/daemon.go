@@ -0,0 +1,109 @@
+// Package daemon makes Hyprland and the other hypr programs pick up config
+// changes: Hyprland is reloaded, hyprpaper, hypridle and hyprsunset are
+// restarted. Commands go through a Runner so they can be replaced in tests.
+package daemon
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/saat-sy/hyprlander/pkg/schema"
+)
+
+// Runner runs external commands.
+type Runner interface {
+	// Run runs name with args and returns its combined output. A non-zero
+	// exit status is an error.
+	Run(ctx context.Context, name string, args ...string) (string, error)
+}
+
+// ExecRunner runs commands with os/exec.
+type ExecRunner struct{}
+
+func (ExecRunner) Run(ctx context.Context, name string, args ...string) (string, error) {
+	output, err := exec.CommandContext(ctx, name, args...).CombinedOutput()
+	if err != nil {
+		return string(output), fmt.Errorf("%s %s: %w: %s", name, strings.Join(args, " "), err, strings.TrimSpace(string(output)))
+	}
+	return string(output), nil
+}
+
+// Controller restarts the hypr programs.
+type Controller struct {
+	runner Runner
+	// pollInterval and pollAttempts bound the wait for a stopped program to
+	// exit before it is started again.
+	pollInterval time.Duration
+	pollAttempts int
+}
+
+func New(runner Runner) *Controller {
+	return &Controller{runner: runner, pollInterval: 100 * time.Millisecond, pollAttempts: 20}
+}
+
+// Restart makes program read its config again and describes what was done.
+func (c *Controller) Restart(ctx context.Context, program schema.Program) (string, error) {
+	switch program {
+	case schema.Hyprland:
+		if _, err := c.runner.Run(ctx, "hyprctl", "reload"); err != nil {
+			return "", fmt.Errorf("could not reload Hyprland: %w", err)
+		}
+		return "Reloaded Hyprland with hyprctl reload.", nil
+	case schema.Hyprlock:
+		return "hyprlock reads hyprlock.conf each time it starts, so the changes apply the next time the screen is locked. Nothing was restarted.", nil
+	case schema.Hyprpaper, schema.Hypridle, schema.Hyprsunset:
+		return c.restart(ctx, string(program))
+	}
+	return "", fmt.Errorf("cannot restart %q", program)
+}
+
+// restart uses the program's systemd user service when it is running as
+// one, and otherwise stops the program and starts it again through Hyprland
+// so it does not end with hyprlander.
+func (c *Controller) restart(ctx context.Context, name string) (string, error) {
+	unit := name + ".service"
+	if _, err := c.runner.Run(ctx, "systemctl", "--user", "is-active", "--quiet", unit); err == nil {
+		if _, err := c.runner.Run(ctx, "systemctl", "--user", "restart", unit); err != nil {
+			return "", fmt.Errorf("could not restart %s: %w", unit, err)
+		}
+		return fmt.Sprintf("Restarted %s with systemctl --user restart %s.", name, unit), nil
+	}
+
+	action := "Started"
+	if c.running(ctx, name) {
+		action = "Restarted"
+		if _, err := c.runner.Run(ctx, "pkill", "-x", name); err != nil {
+			return "", fmt.Errorf("could not stop %s: %w", name, err)
+		}
+		if err := c.waitForExit(ctx, name); err != nil {
+			return "", err
+		}
+	}
+
+	if _, err := c.runner.Run(ctx, "hyprctl", "dispatch", "exec", name); err != nil {
+		return "", fmt.Errorf("could not start %s: %w", name, err)
+	}
+	return fmt.Sprintf("%s %s with hyprctl dispatch exec %s.", action, name, name), nil
+}
+
+func (c *Controller) running(ctx context.Context, name string) bool {
+	_, err := c.runner.Run(ctx, "pidof", name)
+	return err == nil
+}
+
+func (c *Controller) waitForExit(ctx context.Context, name string) error {
+	for range c.pollAttempts {
+		if !c.running(ctx, name) {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(c.pollInterval):
+		}
+	}
+	return fmt.Errorf("%s did not exit after pkill", name)
+}
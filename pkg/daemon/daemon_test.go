@@ -0,0 +1,173 @@
+package daemon
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/saat-sy/hyprlander/pkg/schema"
+)
+
+// fakeRunner records the commands it is given and answers them with fail,
+// which returns whether a command exits with an error.
+type fakeRunner struct {
+	commands []string
+	fail     func(command string) bool
+}
+
+func (r *fakeRunner) Run(ctx context.Context, name string, args ...string) (string, error) {
+	command := strings.Join(append([]string{name}, args...), " ")
+	r.commands = append(r.commands, command)
+	if r.fail != nil && r.fail(command) {
+		return "", errors.New("exit status 1")
+	}
+	return "", nil
+}
+
+// testController polls without waiting so timeouts are quick.
+func testController(runner Runner) *Controller {
+	c := New(runner)
+	c.pollInterval = time.Millisecond
+	c.pollAttempts = 3
+	return c
+}
+
+func TestRestartHyprland(t *testing.T) {
+	runner := &fakeRunner{}
+	if _, err := testController(runner).Restart(context.Background(), schema.Hyprland); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"hyprctl reload"}; !slices.Equal(runner.commands, want) {
+		t.Errorf("ran %q, want %q", runner.commands, want)
+	}
+}
+
+func TestRestartHyprlockDoesNothing(t *testing.T) {
+	runner := &fakeRunner{}
+	message, err := testController(runner).Restart(context.Background(), schema.Hyprlock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(runner.commands) != 0 {
+		t.Errorf("ran %q for hyprlock", runner.commands)
+	}
+	if !strings.Contains(message, "Nothing was restarted") {
+		t.Errorf("message = %q", message)
+	}
+}
+
+func TestRestartSystemdService(t *testing.T) {
+	runner := &fakeRunner{}
+	message, err := testController(runner).Restart(context.Background(), schema.Hyprpaper)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"systemctl --user is-active --quiet hyprpaper.service",
+		"systemctl --user restart hyprpaper.service",
+	}
+	if !slices.Equal(runner.commands, want) {
+		t.Errorf("ran %q, want %q", runner.commands, want)
+	}
+	if !strings.Contains(message, "systemctl --user restart") {
+		t.Errorf("message = %q", message)
+	}
+}
+
+func TestRestartWithoutSystemd(t *testing.T) {
+	// hypridle is running outside systemd and exits after the second pidof.
+	pidof := 0
+	runner := &fakeRunner{fail: func(command string) bool {
+		switch {
+		case strings.HasPrefix(command, "systemctl"):
+			return true
+		case command == "pidof hypridle":
+			pidof++
+			return pidof > 2
+		}
+		return false
+	}}
+
+	message, err := testController(runner).Restart(context.Background(), schema.Hypridle)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []string{
+		"systemctl --user is-active --quiet hypridle.service",
+		"pidof hypridle",
+		"pkill -x hypridle",
+		"pidof hypridle",
+		"pidof hypridle",
+		"hyprctl dispatch exec hypridle",
+	}
+	if !slices.Equal(runner.commands, want) {
+		t.Errorf("ran %q, want %q", runner.commands, want)
+	}
+	if !strings.HasPrefix(message, "Restarted hypridle") {
+		t.Errorf("message = %q", message)
+	}
+}
+
+func TestRestartStartsStoppedProgram(t *testing.T) {
+	runner := &fakeRunner{fail: func(command string) bool {
+		return strings.HasPrefix(command, "systemctl") || strings.HasPrefix(command, "pidof")
+	}}
+
+	message, err := testController(runner).Restart(context.Background(), schema.Hyprsunset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if slices.Contains(runner.commands, "pkill -x hyprsunset") {
+		t.Errorf("stopped a program that was not running: %q", runner.commands)
+	}
+	if last := runner.commands[len(runner.commands)-1]; last != "hyprctl dispatch exec hyprsunset" {
+		t.Errorf("last command = %q", last)
+	}
+	if !strings.HasPrefix(message, "Started hyprsunset") {
+		t.Errorf("message = %q", message)
+	}
+}
+
+func TestRestartWaitTimesOut(t *testing.T) {
+	// hyprpaper ignores pkill.
+	runner := &fakeRunner{fail: func(command string) bool {
+		return strings.HasPrefix(command, "systemctl")
+	}}
+
+	_, err := testController(runner).Restart(context.Background(), schema.Hyprpaper)
+	if err == nil || !strings.Contains(err.Error(), "did not exit") {
+		t.Fatalf("Restart = %v, want a timeout", err)
+	}
+	if slices.Contains(runner.commands, "hyprctl dispatch exec hyprpaper") {
+		t.Error("started a second hyprpaper while the first was still running")
+	}
+}
+
+func TestRestartWaitCancelled(t *testing.T) {
+	runner := &fakeRunner{fail: func(command string) bool {
+		return strings.HasPrefix(command, "systemctl")
+	}}
+	c := New(runner)
+	c.pollInterval = time.Hour
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := c.Restart(ctx, schema.Hyprpaper); !errors.Is(err, context.Canceled) {
+		t.Errorf("Restart = %v, want context.Canceled", err)
+	}
+}
+
+func TestRestartErrors(t *testing.T) {
+	runner := &fakeRunner{fail: func(command string) bool {
+		return command == "hyprctl reload"
+	}}
+	if _, err := testController(runner).Restart(context.Background(), schema.Hyprland); err == nil {
+		t.Error("a failed reload was reported as done")
+	}
+	if _, err := testController(&fakeRunner{}).Restart(context.Background(), schema.Program("waybar")); err == nil {
+		t.Error("Restart of an unknown program succeeded")
+	}
+}
@@ -0,0 +1,218 @@
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+type OpKind int
+
+const (
+	Equal OpKind = iota
+	Delete
+	Insert
+)
+
+type Op struct {
+	Kind OpKind
+	Line string
+}
+
+// Hunk is a run of changes with surrounding context, numbered from 1 like a
+// unified diff.
+type Hunk struct {
+	OldStart int
+	OldLines int
+	NewStart int
+	NewLines int
+	Ops      []Op
+}
+
+const DefaultContext = 3
+
+// Lines computes a line diff of two texts from their longest common subsequence.
+func Lines(oldText, newText string) []Op {
+	a := splitLines(oldText)
+	b := splitLines(newText)
+
+	// lcs[i][j] is the LCS length of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var ops []Op
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			ops = append(ops, Op{Equal, a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			ops = append(ops, Op{Delete, a[i]})
+			i++
+		default:
+			ops = append(ops, Op{Insert, b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		ops = append(ops, Op{Delete, a[i]})
+	}
+	for ; j < len(b); j++ {
+		ops = append(ops, Op{Insert, b[j]})
+	}
+
+	return ops
+}
+
+// Hunks groups changed lines together with up to context unchanged lines on
+// either side. Changes whose context windows touch share a hunk.
+func Hunks(ops []Op, context int) []Hunk {
+	type span struct{ start, end int }
+	var spans []span
+	for i, op := range ops {
+		if op.Kind == Equal {
+			continue
+		}
+		start, end := max(i-context, 0), min(i+context+1, len(ops))
+		if len(spans) > 0 && start <= spans[len(spans)-1].end {
+			spans[len(spans)-1].end = end
+			continue
+		}
+		spans = append(spans, span{start, end})
+	}
+
+	var hunks []Hunk
+	oldLine, newLine, next := 1, 1, 0
+	for _, sp := range spans {
+		for ; next < sp.start; next++ {
+			oldLine, newLine = advance(ops[next], oldLine, newLine)
+		}
+
+		hunk := Hunk{OldStart: oldLine, NewStart: newLine, Ops: ops[sp.start:sp.end]}
+		for ; next < sp.end; next++ {
+			if ops[next].Kind != Insert {
+				hunk.OldLines++
+			}
+			if ops[next].Kind != Delete {
+				hunk.NewLines++
+			}
+			oldLine, newLine = advance(ops[next], oldLine, newLine)
+		}
+		hunks = append(hunks, hunk)
+	}
+
+	return hunks
+}
+
+func advance(op Op, oldLine, newLine int) (int, int) {
+	switch op.Kind {
+	case Delete:
+		return oldLine + 1, newLine
+	case Insert:
+		return oldLine, newLine + 1
+	default:
+		return oldLine + 1, newLine + 1
+	}
+}
+
+// Header returns the @@ line of a hunk.
+func (h Hunk) Header() string {
+	return fmt.Sprintf("@@ -%d,%d +%d,%d @@", h.OldStart, h.OldLines, h.NewStart, h.NewLines)
+}
+
+func (h Hunk) String() string {
+	var out strings.Builder
+	out.WriteString(h.Header() + "\n")
+	for _, op := range h.Ops {
+		switch op.Kind {
+		case Equal:
+			out.WriteString(" " + op.Line + "\n")
+		case Delete:
+			out.WriteString("-" + op.Line + "\n")
+		case Insert:
+			out.WriteString("+" + op.Line + "\n")
+		}
+	}
+	return out.String()
+}
+
+// Unified renders a unified diff between two versions of path. It returns an
+// empty string when the texts are identical.
+func Unified(path, oldText, newText string) string {
+	hunks := Hunks(Lines(oldText, newText), DefaultContext)
+	if len(hunks) == 0 {
+		return ""
+	}
+
+	var out strings.Builder
+	out.WriteString("--- " + path + "\n")
+	out.WriteString("+++ " + path + "\n")
+	for _, hunk := range hunks {
+		out.WriteString(hunk.String())
+	}
+	return out.String()
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// OldText returns the lines the hunk covers in the original text.
+func (h Hunk) OldText() []string {
+	var lines []string
+	for _, op := range h.Ops {
+		if op.Kind != Insert {
+			lines = append(lines, op.Line)
+		}
+	}
+	return lines
+}
+
+// NewText returns the lines the hunk covers in the new text.
+func (h Hunk) NewText() []string {
+	var lines []string
+	for _, op := range h.Ops {
+		if op.Kind != Delete {
+			lines = append(lines, op.Line)
+		}
+	}
+	return lines
+}
+
+// Apply rebuilds a text from the original by replacing the region covered by
+// each hunk with the matching entry of replacements. Passing a hunk's NewText
+// accepts it and its OldText rejects it. Hunks must be in order and must not
+// overlap, as returned by Hunks.
+func Apply(oldText string, hunks []Hunk, replacements [][]string) string {
+	original := splitLines(oldText)
+	var out []string
+	next := 0
+
+	for i, hunk := range hunks {
+		start := hunk.OldStart - 1
+		out = append(out, original[next:start]...)
+		out = append(out, replacements[i]...)
+		next = start + hunk.OldLines
+	}
+	out = append(out, original[next:]...)
+
+	if len(out) == 0 {
+		return ""
+	}
+	return strings.Join(out, "\n") + "\n"
+}
@@ -0,0 +1,174 @@
+// Package docs embeds a snapshot of the Hyprland wiki so the agent can look
+// up option names, dispatchers and rules without network access.
+package docs
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+)
+
+//go:embed wiki/*.md wiki/VERSION
+var wikiFS embed.FS
+
+type Page struct {
+	Name    string
+	Title   string
+	Content string
+}
+
+// Section is the part of a page under one heading. Heading lists the page
+// title and every heading above the section, outermost first.
+type Section struct {
+	Page    string
+	Heading []string
+	Text    string
+}
+
+func (s Section) Title() string {
+	return strings.Join(s.Heading, " > ")
+}
+
+var (
+	loadOnce sync.Once
+	pages    []Page
+	sections []Section
+	version  string
+	index    *Index
+)
+
+func load() {
+	if data, err := wikiFS.ReadFile("wiki/VERSION"); err == nil {
+		version = strings.TrimSpace(string(data))
+	}
+
+	entries, _ := wikiFS.ReadDir("wiki")
+	for _, entry := range entries {
+		if path.Ext(entry.Name()) != ".md" {
+			continue
+		}
+		data, err := wikiFS.ReadFile("wiki/" + entry.Name())
+		if err != nil {
+			continue
+		}
+
+		page := Page{
+			Name:    strings.TrimSuffix(entry.Name(), ".md"),
+			Content: string(data),
+		}
+		page.Title = page.Name
+		if first, _, _ := strings.Cut(page.Content, "\n"); strings.HasPrefix(first, "# ") {
+			page.Title = strings.TrimPrefix(first, "# ")
+		}
+
+		pages = append(pages, page)
+		sections = append(sections, splitSections(page)...)
+	}
+	sort.Slice(pages, func(i, j int) bool { return pages[i].Name < pages[j].Name })
+
+	index = NewIndex(sections)
+}
+
+// Version is the Hyprland release the snapshot was taken from.
+func Version() string {
+	loadOnce.Do(load)
+	return version
+}
+
+func Pages() []Page {
+	loadOnce.Do(load)
+	return pages
+}
+
+// Sections returns every section of every page, in page order.
+func Sections() []Section {
+	loadOnce.Do(load)
+	return sections
+}
+
+// GetPage finds a page by name or title, ignoring case, spaces, dashes and
+// underscores, so "window rules" finds Window-Rules.
+func GetPage(name string) (Page, error) {
+	loadOnce.Do(load)
+
+	key := normalizeName(name)
+	for _, page := range pages {
+		if normalizeName(page.Name) == key || normalizeName(page.Title) == key {
+			return page, nil
+		}
+	}
+
+	names := make([]string, len(pages))
+	for i, page := range pages {
+		names[i] = page.Name
+	}
+	return Page{}, fmt.Errorf("no documentation page named %q, available pages: %s", name, strings.Join(names, ", "))
+}
+
+// Search returns the sections that best match query.
+func Search(query string, limit int) []Result {
+	loadOnce.Do(load)
+	return index.Search(query, limit)
+}
+
+func normalizeName(name string) string {
+	name = strings.ToLower(strings.TrimSuffix(name, ".md"))
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' || r == '_' {
+			return -1
+		}
+		return r
+	}, name)
+}
+
+// splitSections cuts a page at its "##" and "###" headings. Headings inside
+// fenced code blocks are left alone.
+func splitSections(page Page) []Section {
+	var sections []Section
+	headings := []string{page.Title}
+	var text strings.Builder
+	inFence := false
+
+	flush := func() {
+		if body := strings.TrimSpace(text.String()); body != "" {
+			sections = append(sections, Section{
+				Page:    page.Name,
+				Heading: append([]string(nil), headings...),
+				Text:    body,
+			})
+		}
+		text.Reset()
+	}
+
+	for _, line := range strings.Split(page.Content, "\n") {
+		if strings.HasPrefix(line, "```") {
+			inFence = !inFence
+		}
+
+		level := 0
+		if !inFence {
+			switch {
+			case strings.HasPrefix(line, "### "):
+				level = 3
+			case strings.HasPrefix(line, "## "):
+				level = 2
+			case strings.HasPrefix(line, "# "):
+				continue
+			}
+		}
+		if level == 0 {
+			text.WriteString(line)
+			text.WriteByte('\n')
+			continue
+		}
+
+		flush()
+		headings = append(headings[:min(level-1, len(headings))], strings.TrimSpace(line[level+1:]))
+	}
+	flush()
+
+	return sections
+}
@@ -0,0 +1,190 @@
+package docs
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// BM25 parameters, using the usual defaults.
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// Headings count more than body text: a query for "blur" should rank the
+// blur section above every table that mentions blur in passing.
+const headingWeight = 3
+
+type Result struct {
+	Section Section
+	Score   float64
+}
+
+// Index is an in-memory BM25 index over documentation sections.
+type Index struct {
+	sections  []Section
+	terms     []map[string]int
+	lengths   []int
+	avgLength float64
+	docFreq   map[string]int
+}
+
+func NewIndex(sections []Section) *Index {
+	index := &Index{
+		sections: sections,
+		terms:    make([]map[string]int, len(sections)),
+		lengths:  make([]int, len(sections)),
+		docFreq:  make(map[string]int),
+	}
+
+	total := 0
+	for i, section := range sections {
+		counts := make(map[string]int)
+		length := 0
+		for _, token := range tokenize(strings.Join(section.Heading, " ")) {
+			counts[token] += headingWeight
+			length += headingWeight
+		}
+		for _, token := range tokenize(section.Text) {
+			counts[token]++
+			length++
+		}
+
+		index.terms[i] = counts
+		index.lengths[i] = length
+		total += length
+		for term := range counts {
+			index.docFreq[term]++
+		}
+	}
+	if len(sections) > 0 {
+		index.avgLength = float64(total) / float64(len(sections))
+	}
+
+	return index
+}
+
+func (idx *Index) Search(query string, limit int) []Result {
+	queryTerms := tokenize(query)
+	if len(queryTerms) == 0 {
+		return nil
+	}
+
+	n := float64(len(idx.sections))
+	var results []Result
+	for i, section := range idx.sections {
+		score := 0.0
+		for _, term := range queryTerms {
+			tf := float64(idx.terms[i][term])
+			if tf == 0 {
+				continue
+			}
+			df := float64(idx.docFreq[term])
+			idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+			norm := 1 - bm25B + bm25B*float64(idx.lengths[i])/idx.avgLength
+			score += idf * tf * (bm25K1 + 1) / (tf + bm25K1*norm)
+		}
+		if score > 0 {
+			results = append(results, Result{Section: section, Score: score})
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool { return results[i].Score > results[j].Score })
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// tokenize lowercases text and splits it into words. Option names such as
+// "col.active_border" are kept whole and also split into their parts, so
+// both "active_border" and "active border" find them.
+func tokenize(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '_' && r != '.' && r != ':' && r != '-'
+	})
+
+	var tokens []string
+	for _, field := range fields {
+		field = strings.Trim(field, "._:-")
+		if field == "" || stopWords[field] {
+			continue
+		}
+
+		parts := strings.FieldsFunc(field, func(r rune) bool {
+			return r == '_' || r == '.' || r == ':' || r == '-'
+		})
+		if len(parts) == 1 {
+			tokens = append(tokens, stem(field))
+			continue
+		}
+
+		tokens = append(tokens, field)
+		for _, part := range parts {
+			if !stopWords[part] {
+				tokens = append(tokens, stem(part))
+			}
+		}
+	}
+	return tokens
+}
+
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "by": true, "can": true, "do": true, "for": true, "how": true,
+	"i": true, "if": true, "in": true, "is": true, "it": true, "my": true,
+	"of": true, "on": true, "or": true, "that": true, "the": true, "this": true,
+	"to": true, "what": true, "when": true, "will": true, "with": true,
+}
+
+// stem strips common English suffixes so "passes" finds "pass" and
+// "silently" finds "silent". It only has to be consistent, not correct.
+func stem(word string) string {
+	for _, suffix := range []string{"ing", "ly", "ed", "es", "s"} {
+		if strings.HasSuffix(word, suffix) && len(word)-len(suffix) >= 3 {
+			return strings.TrimSuffix(word, suffix)
+		}
+	}
+	return word
+}
+
+// Snippet shortens a long section to the lines that mention the query,
+// keeping table headers so matched rows still make sense.
+func (r Result) Snippet(query string, maxLength int) string {
+	text := r.Section.Text
+	if len(text) <= maxLength {
+		return text
+	}
+
+	terms := make(map[string]bool)
+	for _, term := range tokenize(query) {
+		terms[term] = true
+	}
+
+	var kept []string
+	length := 0
+	for _, line := range strings.Split(text, "\n") {
+		keep := strings.HasPrefix(line, "| name") || strings.HasPrefix(line, "|---")
+		if !keep {
+			for _, token := range tokenize(line) {
+				if terms[token] {
+					keep = true
+					break
+				}
+			}
+		}
+		if !keep {
+			continue
+		}
+		if length+len(line) > maxLength {
+			kept = append(kept, "...")
+			break
+		}
+		kept = append(kept, line)
+		length += len(line) + 1
+	}
+
+	return strings.Join(kept, "\n")
+}
@@ -0,0 +1,243 @@
+// Package explain reports where the effective value of a config option comes
+// from: every assignment across the sourced files, which one wins, and the
+// default it replaces. Options of hyprlock, hypridle and the other hypr
+// programs are explained against their own config.
+package explain
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/saat-sy/hyprlander/pkg/hyprlang"
+	"github.com/saat-sy/hyprlander/pkg/schema"
+)
+
+type Assignment struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+	// Raw is the value as written, Value the value with $variables expanded.
+	Raw   string `json:"raw"`
+	Value string `json:"value"`
+	// Effective marks the assignment the program ends up using.
+	Effective bool `json:"effective"`
+	// Block numbers the blocks of a repeatable category such as hypridle's
+	// listener, counting from 1.
+	Block int `json:"block,omitempty"`
+}
+
+type Explanation struct {
+	Option  string `json:"option"`
+	Program string `json:"program"`
+	// Known is false for options that are not in the schema.
+	Known       bool         `json:"known"`
+	Type        string       `json:"type,omitempty"`
+	Default     string       `json:"default,omitempty"`
+	Deprecated  string       `json:"deprecated,omitempty"`
+	Assignments []Assignment `json:"assignments"`
+	// Value is the effective value: the last assignment, or the default.
+	Value string `json:"value"`
+	// PerBlock is set for options of repeatable categories, where every
+	// block keeps its own value and Value is the one of the last block.
+	PerBlock bool `json:"per_block,omitempty"`
+}
+
+// Explain finds the assignments of name. name is a full option path, or the
+// last part of one when that names a single option, e.g. border_size.
+func Explain(cfg *hyprlang.Config, name string) (*Explanation, error) {
+	return ExplainIn(cfg, schema.Hyprland, name)
+}
+
+// ExplainIn is Explain for the config of program.
+func ExplainIn(cfg *hyprlang.Config, program schema.Program, name string) (*Explanation, error) {
+	path, err := resolve(cfg, program, strings.TrimSpace(name))
+	if err != nil {
+		return nil, err
+	}
+
+	category, _, _ := strings.Cut(path, ":")
+	explanation := &Explanation{
+		Option:      path,
+		Program:     string(program),
+		Assignments: []Assignment{},
+		PerBlock:    strings.Contains(path, ":") && schema.IsRepeatable(program, category),
+	}
+	if option, ok := schema.LookupIn(program, path); ok {
+		explanation.Known = true
+		explanation.Type = string(option.Type)
+		explanation.Default = option.Default
+		if option.Deprecated != nil {
+			explanation.Deprecated = schema.DeprecationMessage(option)
+		}
+	}
+
+	// Variables are expanded as they stand at each line: a later definition
+	// of the same variable does not change what an earlier line meant.
+	vars := make(map[string]string)
+	block := 0
+	for _, line := range cfg.Lines {
+		if line.Kind == hyprlang.Variable {
+			vars[line.Key[1:]] = hyprlang.ExpandValue(line.Value, vars)
+		}
+		if line.Kind == hyprlang.BlockStart && len(line.Category) == 0 && line.Key == category {
+			block++
+		}
+		if line.Kind == hyprlang.Assignment && line.FullKey() == path {
+			assignment := Assignment{
+				File:  line.File,
+				Line:  line.Number,
+				Raw:   line.Value,
+				Value: hyprlang.ExpandValue(line.Value, vars),
+			}
+			if explanation.PerBlock {
+				assignment.Block = block
+			}
+			explanation.Assignments = append(explanation.Assignments, assignment)
+		}
+	}
+
+	explanation.Value = explanation.Default
+	for i, assignment := range explanation.Assignments {
+		last := i == len(explanation.Assignments)-1
+		if last || assignment.Block != explanation.Assignments[i+1].Block {
+			explanation.Assignments[i].Effective = true
+		}
+		if last {
+			explanation.Value = assignment.Value
+		}
+	}
+	return explanation, nil
+}
+
+// Program returns the program named by program, or when it is empty the one
+// whose schema knows name. Hyprland wins over the other programs.
+func Program(name, program string) (schema.Program, error) {
+	if program == "" {
+		found := schema.ProgramsFor(strings.TrimSpace(name))
+		switch {
+		case len(found) == 0:
+			return schema.Hyprland, nil
+		case len(found) == 1 || found[0] == schema.Hyprland:
+			return found[0], nil
+		}
+		names := make([]string, len(found))
+		for i, p := range found {
+			names[i] = string(p)
+		}
+		return "", fmt.Errorf("%q is an option of %s, choose the program", name, strings.Join(names, " and "))
+	}
+	parsed, ok := schema.ParseProgram(program)
+	if !ok {
+		return "", fmt.Errorf("unknown program %q, use hyprland, hyprpaper, hyprlock, hypridle or hyprsunset", program)
+	}
+	return parsed, nil
+}
+
+// resolve turns a short option name into its full path when the schema or
+// the config makes that unambiguous.
+func resolve(cfg *hyprlang.Config, program schema.Program, name string) (string, error) {
+	if name == "" {
+		return "", fmt.Errorf("no option given")
+	}
+	if _, ok := schema.LookupIn(program, name); ok || strings.Contains(name, ":") {
+		return name, nil
+	}
+
+	candidates := make(map[string]bool)
+	for _, option := range schema.FindIn(program, name) {
+		candidates[option.Path] = true
+	}
+	for _, line := range cfg.Lines {
+		if line.Kind == hyprlang.Assignment && !schema.IsKeywordIn(program, line.Line) && line.Key == name {
+			candidates[line.FullKey()] = true
+		}
+	}
+
+	switch len(candidates) {
+	case 0:
+		return name, nil
+	case 1:
+		for path := range candidates {
+			return path, nil
+		}
+	}
+	var paths []string
+	for path := range candidates {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	return "", fmt.Errorf("%q is ambiguous, use one of %s", name, strings.Join(paths, ", "))
+}
+
+// Format describes the explanation with paths relative to root.
+func (e *Explanation) Format(root string) string {
+	var builder strings.Builder
+	builder.WriteString(e.Option)
+	if e.Program != "" && e.Program != string(schema.Hyprland) {
+		builder.WriteString(" (" + schema.Program(e.Program).ConfigFileName() + ")")
+	}
+	builder.WriteString("\n")
+	if !e.Known {
+		builder.WriteString("  not a known option")
+		if suggestions := schema.SuggestIn(schema.Program(e.Program), e.Option, 3); len(suggestions) > 0 {
+			builder.WriteString(", did you mean " + strings.Join(suggestions, " or ") + "?")
+		}
+		builder.WriteString("\n")
+	}
+	if e.Deprecated != "" {
+		builder.WriteString("  " + e.Deprecated + "\n")
+	}
+	if e.Type != "" {
+		builder.WriteString("  type: " + e.Type + "\n")
+	}
+	if e.Known && e.Deprecated == "" {
+		builder.WriteString("  default: " + orEmpty(e.Default) + "\n")
+	}
+
+	if len(e.Assignments) == 0 && !e.Known {
+		builder.WriteString("  not set in the config\n")
+		return strings.TrimRight(builder.String(), "\n")
+	}
+	if len(e.Assignments) == 0 {
+		builder.WriteString("  not set in the config, the default applies\n")
+		return strings.TrimRight(builder.String(), "\n")
+	}
+
+	if e.PerBlock {
+		builder.WriteString("  set per block, each block keeps its own value\n")
+	} else {
+		builder.WriteString("  effective value: " + orEmpty(e.Value) + "\n")
+	}
+	builder.WriteString("  set at:\n")
+	for _, assignment := range e.Assignments {
+		line := fmt.Sprintf("    %s:%d  %s", relative(root, assignment.File), assignment.Line, assignment.Raw)
+		if assignment.Value != assignment.Raw {
+			line += "  (= " + assignment.Value + ")"
+		}
+		switch {
+		case assignment.Effective && e.PerBlock:
+			line += fmt.Sprintf("  <- effective in block %d", assignment.Block)
+		case assignment.Effective:
+			line += "  <- effective"
+		default:
+			line += "  (overridden)"
+		}
+		builder.WriteString(line + "\n")
+	}
+	return strings.TrimRight(builder.String(), "\n")
+}
+
+func orEmpty(value string) string {
+	if value == "" {
+		return "(empty)"
+	}
+	return value
+}
+
+func relative(root, path string) string {
+	if rel, err := filepath.Rel(root, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
@@ -0,0 +1,82 @@
+package hyprlang
+
+import (
+	"errors"
+	"fmt"
+	"os"
+)
+
+// Config is a main config file together with every file it sources.
+type Config struct {
+	// Files are the parsed files in the order Hyprland reads them.
+	Files []*Document
+	// Lines are the lines of all files with each source line followed by the
+	// lines of the files it includes, as if they were written in its place.
+	Lines []ConfigLine
+	// Vars are the $variables defined anywhere in the config.
+	Vars map[string]string
+	// Missing lists source lines whose target does not exist.
+	Missing []MissingSource
+}
+
+type ConfigLine struct {
+	File string
+	Line
+}
+
+type MissingSource struct {
+	ConfigLine
+	Target string
+}
+
+// LoadConfig parses mainPath and follows its source lines recursively.
+// Sourcing the same file twice only reads it once, which also breaks cycles.
+func LoadConfig(mainPath string) (*Config, error) {
+	cfg := &Config{Vars: make(map[string]string)}
+	visited := make(map[string]bool)
+
+	if err := cfg.load(mainPath, visited); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (c *Config) load(path string, visited map[string]bool) error {
+	if visited[path] {
+		return nil
+	}
+	visited[path] = true
+
+	doc, err := ParseFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to load %s: %w", path, err)
+	}
+	c.Files = append(c.Files, doc)
+
+	for _, line := range doc.Lines {
+		configLine := ConfigLine{File: path, Line: line}
+		c.Lines = append(c.Lines, configLine)
+
+		switch line.Kind {
+		case Variable:
+			c.Vars[line.Key[1:]] = line.Value
+		case Source:
+			for _, target := range ResolveSource(line.Value, path, c.Vars) {
+				if _, err := os.Stat(target); errors.Is(err, os.ErrNotExist) {
+					c.Missing = append(c.Missing, MissingSource{ConfigLine: configLine, Target: target})
+					continue
+				}
+				if err := c.load(target, visited); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// Expand substitutes the config's variables in value.
+func (c *Config) Expand(value string) string {
+	return ExpandValue(value, c.Vars)
+}
@@ -0,0 +1,115 @@
+package hyprlang
+
+import (
+	"slices"
+	"strings"
+)
+
+// FileChange is the new content of one file of a Config.
+type FileChange struct {
+	Path     string
+	Original string
+	Content  string
+}
+
+// Editor sets options across the files of a Config. Each option is changed
+// where its effective assignment is, so later assignments do not override
+// the edit; options that are not set anywhere are appended to the main file.
+type Editor struct {
+	cfg      *Config
+	lines    map[string][]string
+	order    []string
+	appended []string
+}
+
+func NewEditor(cfg *Config) *Editor {
+	return &Editor{cfg: cfg, lines: make(map[string][]string)}
+}
+
+// Set changes the effective assignment of path to value.
+func (e *Editor) Set(path, value string) {
+	var effective *ConfigLine
+	for i := range e.cfg.Lines {
+		line := &e.cfg.Lines[i]
+		if line.Kind == Assignment && line.FullKey() == path {
+			effective = line
+		}
+	}
+
+	if effective == nil {
+		e.appended = append(e.appended, path+" = "+escapeValue(value))
+		e.touch(e.mainPath())
+		return
+	}
+
+	lines := e.touch(effective.File)
+	raw := lines[effective.Number-1]
+	lines[effective.Number-1] = replaceValue(raw, value)
+}
+
+func (e *Editor) mainPath() string {
+	return e.cfg.Files[0].Path
+}
+
+// touch returns the editable lines of a file, copying them on first use.
+func (e *Editor) touch(path string) []string {
+	if lines, ok := e.lines[path]; ok {
+		return lines
+	}
+	for _, doc := range e.cfg.Files {
+		if doc.Path == path {
+			lines := make([]string, len(doc.Lines))
+			for i, line := range doc.Lines {
+				lines[i] = line.Raw
+			}
+			e.lines[path] = lines
+			e.order = append(e.order, path)
+			return lines
+		}
+	}
+	return nil
+}
+
+// replaceValue swaps the value of an assignment, keeping the key, the
+// spacing around "=" and a trailing comment.
+func replaceValue(raw, value string) string {
+	equals := strings.Index(raw, "=")
+	prefix := raw[:equals+1]
+	rest := raw[equals+1:]
+	prefix += rest[:len(rest)-len(strings.TrimLeft(rest, " \t"))]
+
+	line := prefix + escapeValue(value)
+	if comment := rawComment(raw); comment != "" {
+		line += " " + comment
+	}
+	return line
+}
+
+// Changes returns the files whose content changed, in the order they were
+// first edited.
+func (e *Editor) Changes() []FileChange {
+	var changes []FileChange
+	for _, path := range e.order {
+		lines := e.lines[path]
+		if path == e.mainPath() && len(e.appended) > 0 {
+			lines = slices.Clone(lines)
+			for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+				lines = lines[:len(lines)-1]
+			}
+			lines = append(lines, "")
+			lines = append(lines, e.appended...)
+			lines = append(lines, "")
+		}
+
+		var original string
+		for _, doc := range e.cfg.Files {
+			if doc.Path == path {
+				original = doc.String()
+			}
+		}
+		if content := strings.Join(lines, "\n"); content != original {
+			changes = append(changes, FileChange{Path: path, Original: original, Content: content})
+		}
+	}
+	return changes
+}
@@ -0,0 +1,156 @@
+package hyprlang
+
+import (
+	"strings"
+)
+
+const indentUnit = "    "
+
+// Format normalizes a config: lines are indented four spaces per block,
+// assignments are written as "key = value", comments are indented with the
+// block they are in, runs of blank lines are collapsed, and the columns of
+// consecutive bind lines are aligned. Comments and values are kept as written.
+func Format(content string) string {
+	doc := Parse("", content)
+
+	var lines []string
+	var binds []Line
+	depth := 0
+	flushBinds := func() {
+		lines = append(lines, alignBinds(binds, depth)...)
+		binds = nil
+	}
+
+	for _, line := range doc.Lines {
+		if line.Kind == Assignment && isBindKey(line.Key) {
+			// bindd has an extra description column, so it is aligned separately.
+			if len(binds) > 0 && bindFields(binds[0]) != bindFields(line) {
+				flushBinds()
+			}
+			binds = append(binds, line)
+			continue
+		}
+		flushBinds()
+
+		switch line.Kind {
+		case Blank:
+			if len(lines) == 0 || lines[len(lines)-1] == "" || strings.HasSuffix(lines[len(lines)-1], "{") {
+				continue
+			}
+			lines = append(lines, "")
+		case Comment:
+			lines = append(lines, indent(depth)+rawComment(line.Raw))
+		case BlockStart:
+			lines = append(lines, withComment(indent(depth)+line.Key+" {", line.Raw))
+			depth++
+		case BlockEnd:
+			depth--
+			for len(lines) > 0 && lines[len(lines)-1] == "" {
+				lines = lines[:len(lines)-1]
+			}
+			lines = append(lines, withComment(indent(depth)+"}", line.Raw))
+		case Assignment, Variable, Source:
+			if strings.ContainsAny(line.Key, " \t{}") {
+				lines = append(lines, strings.TrimRight(line.Raw, " \t"))
+				continue
+			}
+			lines = append(lines, withComment(indent(depth)+line.Key+" = "+escapeValue(line.Value), line.Raw))
+		default:
+			// Banners such as "####" are read as escaped text, not comments.
+			if trimmed := strings.TrimSpace(line.Raw); strings.HasPrefix(trimmed, "#") {
+				lines = append(lines, indent(depth)+trimmed)
+				continue
+			}
+			lines = append(lines, strings.TrimRight(line.Raw, " \t"))
+		}
+	}
+	flushBinds()
+
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func isBindKey(key string) bool {
+	return strings.HasPrefix(key, "bind")
+}
+
+func bindFields(line Line) int {
+	if strings.Contains(strings.TrimPrefix(line.Key, "bind"), "d") {
+		return 5
+	}
+	return 4
+}
+
+// alignBinds pads the key and every field but the last of a run of bind
+// lines so their columns line up.
+func alignBinds(binds []Line, depth int) []string {
+	keyWidth := 0
+	var fields [][]string
+	var widths []int
+	for _, bind := range binds {
+		keyWidth = max(keyWidth, len(bind.Key))
+		parts := strings.SplitN(escapeValue(bind.Value), ",", bindFields(bind))
+		for i := range parts {
+			parts[i] = strings.TrimSpace(parts[i])
+			if i < len(parts)-1 {
+				parts[i] += ","
+				if i >= len(widths) {
+					widths = append(widths, 0)
+				}
+				widths[i] = max(widths[i], len(parts[i]))
+			}
+		}
+		fields = append(fields, parts)
+	}
+
+	lines := make([]string, len(binds))
+	for i, bind := range binds {
+		var builder strings.Builder
+		builder.WriteString(indent(depth) + bind.Key + strings.Repeat(" ", keyWidth-len(bind.Key)) + " =")
+		for j, field := range fields[i] {
+			builder.WriteString(" " + field)
+			if j < len(fields[i])-1 {
+				builder.WriteString(strings.Repeat(" ", widths[j]-len(field)))
+			}
+		}
+		lines[i] = withComment(strings.TrimRight(builder.String(), " "), bind.Raw)
+	}
+	return lines
+}
+
+func indent(depth int) string {
+	return strings.Repeat(indentUnit, max(depth, 0))
+}
+
+// withComment appends the trailing comment of raw, if any, to line.
+func withComment(line, raw string) string {
+	if comment := rawComment(raw); comment != "" {
+		return line + " " + comment
+	}
+	return line
+}
+
+// rawComment returns the comment of a line as written, including the "#".
+func rawComment(raw string) string {
+	for i := 0; i < len(raw); i++ {
+		if raw[i] != '#' {
+			continue
+		}
+		if i+1 < len(raw) && raw[i+1] == '#' {
+			i++
+			continue
+		}
+		return strings.TrimRight(raw[i:], " \t")
+	}
+	return ""
+}
+
+// escapeValue doubles the "#" that splitComment unescaped.
+func escapeValue(value string) string {
+	return strings.ReplaceAll(value, "#", "##")
+}
@@ -0,0 +1,167 @@
+package hyprlang
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+type LineKind int
+
+const (
+	Blank LineKind = iota
+	Comment
+	Assignment
+	Variable
+	Source
+	BlockStart
+	BlockEnd
+	Invalid
+)
+
+func (k LineKind) String() string {
+	switch k {
+	case Blank:
+		return "blank"
+	case Comment:
+		return "comment"
+	case Assignment:
+		return "assignment"
+	case Variable:
+		return "variable"
+	case Source:
+		return "source"
+	case BlockStart:
+		return "block_start"
+	case BlockEnd:
+		return "block_end"
+	default:
+		return "invalid"
+	}
+}
+
+type Line struct {
+	Number   int
+	Raw      string
+	Kind     LineKind
+	Key      string
+	Value    string
+	Comment  string
+	Category []string
+}
+
+// FullKey returns the key prefixed with its enclosing categories, e.g. decoration:blur:size.
+func (l Line) FullKey() string {
+	if len(l.Category) == 0 {
+		return l.Key
+	}
+	return strings.Join(l.Category, ":") + ":" + l.Key
+}
+
+type Document struct {
+	Path  string
+	Lines []Line
+}
+
+func ParseFile(path string) (*Document, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("could not read config file: %w", err)
+	}
+	return Parse(path, string(content)), nil
+}
+
+func Parse(path string, content string) *Document {
+	doc := &Document{Path: path}
+	var stack []string
+
+	for i, raw := range strings.Split(content, "\n") {
+		line := Line{
+			Number:   i + 1,
+			Raw:      raw,
+			Category: append([]string(nil), stack...),
+		}
+
+		body, comment := splitComment(raw)
+		line.Comment = comment
+		trimmed := strings.TrimSpace(body)
+
+		switch {
+		case strings.TrimSpace(raw) == "":
+			line.Kind = Blank
+		case trimmed == "":
+			line.Kind = Comment
+		case trimmed == "}":
+			line.Kind = BlockEnd
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			} else {
+				line.Kind = Invalid
+			}
+			line.Category = append([]string(nil), stack...)
+		case strings.HasSuffix(trimmed, "{") && !strings.Contains(trimmed, "="):
+			name := strings.TrimSpace(strings.TrimSuffix(trimmed, "{"))
+			line.Kind = BlockStart
+			line.Key = name
+			stack = append(stack, strings.Split(name, ":")...)
+		default:
+			key, value, ok := strings.Cut(trimmed, "=")
+			if !ok {
+				line.Kind = Invalid
+				break
+			}
+			line.Key = strings.TrimSpace(key)
+			line.Value = strings.TrimSpace(value)
+			switch {
+			case strings.HasPrefix(line.Key, "$"):
+				line.Kind = Variable
+			case line.Key == "source" && len(stack) == 0:
+				line.Kind = Source
+			default:
+				line.Kind = Assignment
+			}
+		}
+
+		doc.Lines = append(doc.Lines, line)
+	}
+
+	return doc
+}
+
+// String reassembles the document exactly as it was parsed.
+func (d *Document) String() string {
+	raws := make([]string, len(d.Lines))
+	for i, line := range d.Lines {
+		raws[i] = line.Raw
+	}
+	return strings.Join(raws, "\n")
+}
+
+func (d *Document) Variables() map[string]string {
+	vars := make(map[string]string)
+	for _, line := range d.Lines {
+		if line.Kind == Variable {
+			vars[strings.TrimPrefix(line.Key, "$")] = line.Value
+		}
+	}
+	return vars
+}
+
+// splitComment separates a trailing comment from a line. A doubled "##" is an
+// escaped literal "#" and does not start a comment.
+func splitComment(raw string) (string, string) {
+	var body strings.Builder
+	for i := 0; i < len(raw); i++ {
+		if raw[i] != '#' {
+			body.WriteByte(raw[i])
+			continue
+		}
+		if i+1 < len(raw) && raw[i+1] == '#' {
+			body.WriteByte('#')
+			i++
+			continue
+		}
+		return body.String(), strings.TrimSpace(raw[i+1:])
+	}
+	return body.String(), ""
+}
@@ -0,0 +1,57 @@
+package hyprlang
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// ExpandValue substitutes $variables in a value the same way Hyprland does
+// before using it. Unknown variables are left untouched.
+func ExpandValue(value string, vars map[string]string) string {
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	// Longest names first so $mainModShift is not clobbered by $mainMod.
+	sort.Slice(names, func(i, j int) bool { return len(names[i]) > len(names[j]) })
+
+	for _, name := range names {
+		value = strings.ReplaceAll(value, "$"+name, vars[name])
+	}
+
+	return value
+}
+
+// ResolveSource returns the files matched by a source directive value. Relative
+// paths are resolved against the directory of the including file.
+func ResolveSource(value string, includingFile string, vars map[string]string) []string {
+	target := os.ExpandEnv(ExpandValue(value, vars))
+	if strings.HasPrefix(target, "~") {
+		if home, err := os.UserHomeDir(); err == nil {
+			target = home + strings.TrimPrefix(target, "~")
+		}
+	}
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(filepath.Dir(includingFile), target)
+	}
+
+	matches, err := filepath.Glob(target)
+	if err != nil || len(matches) == 0 {
+		return []string{filepath.Clean(target)}
+	}
+	return matches
+}
+
+// SourceTargets lists every file a document sources, in order of appearance.
+func (d *Document) SourceTargets() []string {
+	vars := d.Variables()
+	var targets []string
+	for _, line := range d.Lines {
+		if line.Kind == Source {
+			targets = append(targets, ResolveSource(line.Value, d.Path, vars)...)
+		}
+	}
+	return targets
+}
@@ -0,0 +1,256 @@
+package ini
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+type entryKind int
+
+const (
+	blankEntry entryKind = iota
+	commentEntry
+	valueEntry
+)
+
+type entry struct {
+	kind    entryKind
+	raw     string
+	key     string
+	value   string
+	comment string
+}
+
+type Section struct {
+	Name    string
+	header  string
+	entries []*entry
+}
+
+// File is a parsed INI document. Lines that are never modified are written
+// back exactly as they were read, so comments and layout survive a round trip.
+type File struct {
+	sections []*Section
+}
+
+func New() *File {
+	return &File{sections: []*Section{{}}}
+}
+
+func Load(path string) (*File, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(string(content))
+}
+
+func Parse(content string) (*File, error) {
+	file := New()
+	current := file.sections[0]
+
+	content = strings.TrimSuffix(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	if content == "" {
+		return file, nil
+	}
+
+	for i, line := range strings.Split(content, "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			current.entries = append(current.entries, &entry{kind: blankEntry, raw: line})
+		case strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, ";"):
+			current.entries = append(current.entries, &entry{kind: commentEntry, raw: line})
+		case strings.HasPrefix(trimmed, "["):
+			if !strings.HasSuffix(trimmed, "]") {
+				return nil, fmt.Errorf("line %d: unterminated section header", i+1)
+			}
+			name := strings.TrimSpace(trimmed[1 : len(trimmed)-1])
+			if name == "" {
+				return nil, fmt.Errorf("line %d: empty section name", i+1)
+			}
+			current = &Section{Name: name, header: line}
+			file.sections = append(file.sections, current)
+		default:
+			key, rest, ok := strings.Cut(line, "=")
+			if !ok {
+				return nil, fmt.Errorf("line %d: expected key=value, got %q", i+1, trimmed)
+			}
+			key = strings.TrimSpace(key)
+			if key == "" {
+				return nil, fmt.Errorf("line %d: missing key", i+1)
+			}
+			value, comment, err := parseValue(rest)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", i+1, err)
+			}
+			current.entries = append(current.entries, &entry{
+				kind:    valueEntry,
+				raw:     line,
+				key:     key,
+				value:   value,
+				comment: comment,
+			})
+		}
+	}
+
+	return file, nil
+}
+
+func (f *File) Save(path string, perm os.FileMode) error {
+	return os.WriteFile(path, []byte(f.String()), perm)
+}
+
+func (f *File) String() string {
+	var out strings.Builder
+	for _, section := range f.sections {
+		if section.Name != "" {
+			header := section.header
+			if header == "" {
+				if out.Len() > 0 && !strings.HasSuffix(out.String(), "\n\n") {
+					out.WriteString("\n")
+				}
+				header = "[" + section.Name + "]"
+			}
+			out.WriteString(header + "\n")
+		}
+		for _, e := range section.entries {
+			out.WriteString(e.raw + "\n")
+		}
+	}
+	return out.String()
+}
+
+// Global returns the unnamed section holding keys that appear before any header.
+func (f *File) Global() *Section {
+	return f.sections[0]
+}
+
+// Section returns the named section, or nil if it does not exist.
+func (f *File) Section(name string) *Section {
+	if name == "" {
+		return f.Global()
+	}
+	for _, section := range f.sections {
+		if section.Name == name {
+			return section
+		}
+	}
+	return nil
+}
+
+// EnsureSection returns the named section, appending it if it does not exist.
+func (f *File) EnsureSection(name string) *Section {
+	if section := f.Section(name); section != nil {
+		return section
+	}
+	section := &Section{Name: name}
+	f.sections = append(f.sections, section)
+	return section
+}
+
+func (f *File) DeleteSection(name string) {
+	for i, section := range f.sections {
+		if section.Name == name && name != "" {
+			f.sections = append(f.sections[:i], f.sections[i+1:]...)
+			return
+		}
+	}
+}
+
+// SectionNames lists the named sections in file order.
+func (f *File) SectionNames() []string {
+	var names []string
+	for _, section := range f.sections[1:] {
+		names = append(names, section.Name)
+	}
+	return names
+}
+
+func (s *Section) Get(key string) (string, bool) {
+	if e := s.find(key); e != nil {
+		return e.value, true
+	}
+	return "", false
+}
+
+// Set updates a key in place, keeping any trailing comment, or adds it after
+// the last value in the section.
+func (s *Section) Set(key, value string) {
+	if e := s.find(key); e != nil {
+		e.value = value
+		e.raw = formatEntry(key, value, e.comment)
+		return
+	}
+
+	e := &entry{kind: valueEntry, raw: formatEntry(key, value, ""), key: key, value: value}
+
+	// Comments after the last value usually describe the next section, so
+	// insert directly after the last value rather than at the very end.
+	insertAt := len(s.entries)
+	for insertAt > 0 && s.entries[insertAt-1].kind == blankEntry {
+		insertAt--
+	}
+	for i := len(s.entries) - 1; i >= 0; i-- {
+		if s.entries[i].kind == valueEntry {
+			insertAt = i + 1
+			break
+		}
+	}
+	s.entries = append(s.entries[:insertAt], append([]*entry{e}, s.entries[insertAt:]...)...)
+}
+
+func (s *Section) Delete(key string) {
+	for i, e := range s.entries {
+		if e.kind == valueEntry && e.key == key {
+			s.entries = append(s.entries[:i], s.entries[i+1:]...)
+			return
+		}
+	}
+}
+
+// Keys returns the keys of the section in file order.
+func (s *Section) Keys() []string {
+	var keys []string
+	for _, e := range s.entries {
+		if e.kind == valueEntry {
+			keys = append(keys, e.key)
+		}
+	}
+	return keys
+}
+
+func (s *Section) Map() map[string]string {
+	values := make(map[string]string)
+	for _, e := range s.entries {
+		if e.kind == valueEntry {
+			values[e.key] = e.value
+		}
+	}
+	return values
+}
+
+// Require reports every listed key that is missing or empty.
+func (s *Section) Require(keys ...string) error {
+	var missing []string
+	for _, key := range keys {
+		if value, ok := s.Get(key); !ok || strings.TrimSpace(value) == "" {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing required keys: %s", strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+func (s *Section) find(key string) *entry {
+	for _, e := range s.entries {
+		if e.kind == valueEntry && e.key == key {
+			return e
+		}
+	}
+	return nil
+}
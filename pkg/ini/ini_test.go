@@ -0,0 +1,193 @@
+package ini
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+const sample = `# hyprlander settings
+; written by hand
+
+api_key_name = gemini
+format_on_write=true  # tidy files
+
+[default]
+model = gemini-2.5-flash ; fast
+prompt = "say \"hi\"\tthen\\leave\nnow"
+
+# work machine
+[profile work]
+  model=gemini-2.5-pro
+dir = /home/me/work hypr
+
+; end of file
+`
+
+func TestRoundTrip(t *testing.T) {
+	file, err := Parse(sample)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := file.String(); got != sample {
+		t.Errorf("round trip changed the file:\n%s\nwant:\n%s", got, sample)
+	}
+}
+
+func TestParseValues(t *testing.T) {
+	file, err := Parse(sample)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		section, key, want string
+	}{
+		{"", "api_key_name", "gemini"},
+		{"", "format_on_write", "true"},
+		{"default", "model", "gemini-2.5-flash"},
+		{"default", "prompt", "say \"hi\"\tthen\\leave\nnow"},
+		{"profile work", "model", "gemini-2.5-pro"},
+		{"profile work", "dir", "/home/me/work hypr"},
+	}
+	for _, test := range tests {
+		section := file.Section(test.section)
+		if section == nil {
+			t.Fatalf("section %q is missing", test.section)
+		}
+		if got, ok := section.Get(test.key); !ok || got != test.want {
+			t.Errorf("[%s] %s = %q, %v; want %q", test.section, test.key, got, ok, test.want)
+		}
+	}
+
+	if names := file.SectionNames(); !slices.Equal(names, []string{"default", "profile work"}) {
+		t.Errorf("SectionNames = %q", names)
+	}
+	if keys := file.Section("default").Keys(); !slices.Equal(keys, []string{"model", "prompt"}) {
+		t.Errorf("Keys = %q, want file order", keys)
+	}
+}
+
+func TestSetExistingKey(t *testing.T) {
+	file, err := Parse(sample)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.Section("default").Set("model", "gemini-2.5-pro")
+
+	// Only the edited line changes, and its comment is kept.
+	want := strings.Replace(sample, "model = gemini-2.5-flash ; fast", "model=gemini-2.5-pro # fast", 1)
+	if got := file.String(); got != want {
+		t.Errorf("Set changed more than the key:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestSetNewKey(t *testing.T) {
+	file, err := Parse(sample)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.Section("profile work").Set("format_on_write", "false")
+
+	// The key goes after the last value, before the comment that closes
+	// the section.
+	want := strings.Replace(sample, "dir = /home/me/work hypr\n", "dir = /home/me/work hypr\nformat_on_write=false\n", 1)
+	if got := file.String(); got != want {
+		t.Errorf("Set of a new key:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestSetNewSection(t *testing.T) {
+	file, err := Parse(sample)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.EnsureSection("profile laptop").Set("model", "gemini-2.5-flash")
+
+	want := sample + "\n[profile laptop]\nmodel=gemini-2.5-flash\n"
+	if got := file.String(); got != want {
+		t.Errorf("EnsureSection:\n%s\nwant:\n%s", got, want)
+	}
+
+	reparsed, err := Parse(file.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := reparsed.Section("profile laptop").Get("model"); got != "gemini-2.5-flash" {
+		t.Errorf("new section reads back %q", got)
+	}
+}
+
+func TestSetEscaping(t *testing.T) {
+	values := []string{
+		"",
+		"plain",
+		" padded ",
+		`"starts with a quote`,
+		`back\slash`,
+		"two\nlines",
+		"tab\there",
+		"looks # like a comment",
+		"looks ; like a comment",
+		"#not-a-comment",
+		`say "hi"`,
+	}
+
+	for _, value := range values {
+		file := New()
+		file.EnsureSection("default").Set("key", value)
+
+		reparsed, err := Parse(file.String())
+		if err != nil {
+			t.Errorf("Set(%q) wrote an unreadable line %q: %v", value, file.String(), err)
+			continue
+		}
+		if got, _ := reparsed.Section("default").Get("key"); got != value {
+			t.Errorf("Set(%q) reads back as %q from %q", value, got, file.String())
+		}
+	}
+}
+
+func TestDelete(t *testing.T) {
+	file, err := Parse(sample)
+	if err != nil {
+		t.Fatal(err)
+	}
+	file.Section("default").Delete("prompt")
+	file.DeleteSection("profile work")
+
+	got := file.String()
+	if strings.Contains(got, "prompt") || strings.Contains(got, "[profile work]") {
+		t.Errorf("Delete left the key or section behind:\n%s", got)
+	}
+	if !strings.HasPrefix(got, "# hyprlander settings\n; written by hand\n") {
+		t.Errorf("Delete lost the leading comments:\n%s", got)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []string{
+		"[default",
+		"[ ]",
+		"no equals sign",
+		"= value",
+		`key = "unterminated`,
+		`key = "quoted" trailing`,
+		`key = "dangling\`,
+	}
+	for _, content := range tests {
+		if _, err := Parse(content); err == nil {
+			t.Errorf("Parse(%q) succeeded", content)
+		}
+	}
+}
+
+func TestParseCRLF(t *testing.T) {
+	file, err := Parse("[default]\r\nmodel = pro\r\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := file.Section("default").Get("model"); got != "pro" {
+		t.Errorf("model = %q, want pro", got)
+	}
+}
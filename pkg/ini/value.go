@@ -0,0 +1,81 @@
+package ini
+
+import (
+	"fmt"
+	"strings"
+)
+
+// parseValue reads the right-hand side of a key=value line. Quoted values
+// support \", \\, \n and \t escapes; an unquoted value ends at a " #" or " ;"
+// inline comment.
+func parseValue(rest string) (string, string, error) {
+	rest = strings.TrimSpace(rest)
+	if !strings.HasPrefix(rest, `"`) {
+		value, comment := splitInlineComment(rest)
+		return value, comment, nil
+	}
+
+	var value strings.Builder
+	for i := 1; i < len(rest); i++ {
+		c := rest[i]
+		switch c {
+		case '\\':
+			if i+1 >= len(rest) {
+				return "", "", fmt.Errorf("dangling escape in quoted value")
+			}
+			i++
+			switch rest[i] {
+			case 'n':
+				value.WriteByte('\n')
+			case 't':
+				value.WriteByte('\t')
+			default:
+				value.WriteByte(rest[i])
+			}
+		case '"':
+			trailing := strings.TrimSpace(rest[i+1:])
+			if trailing == "" {
+				return value.String(), "", nil
+			}
+			if trailing[0] != '#' && trailing[0] != ';' {
+				return "", "", fmt.Errorf("unexpected text after quoted value")
+			}
+			return value.String(), strings.TrimSpace(trailing[1:]), nil
+		default:
+			value.WriteByte(c)
+		}
+	}
+
+	return "", "", fmt.Errorf("unterminated quoted value")
+}
+
+func splitInlineComment(value string) (string, string) {
+	for i := 1; i < len(value); i++ {
+		if (value[i] == '#' || value[i] == ';') && (value[i-1] == ' ' || value[i-1] == '\t') {
+			return strings.TrimSpace(value[:i]), strings.TrimSpace(value[i+1:])
+		}
+	}
+	return value, ""
+}
+
+func formatEntry(key, value, comment string) string {
+	line := key + "=" + quoteIfNeeded(value)
+	if comment != "" {
+		line += " # " + comment
+	}
+	return line
+}
+
+func quoteIfNeeded(value string) string {
+	needsQuotes := value != strings.TrimSpace(value) ||
+		strings.HasPrefix(value, `"`) ||
+		strings.ContainsAny(value, "\n\t\\") ||
+		strings.Contains(value, " #") ||
+		strings.Contains(value, " ;")
+	if !needsQuotes {
+		return value
+	}
+
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\t", `\t`)
+	return `"` + replacer.Replace(value) + `"`
+}
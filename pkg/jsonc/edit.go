@@ -0,0 +1,411 @@
+package jsonc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Get decodes the value at path. Paths address values by object keys and
+// array indexes, e.g. ["clock", "format"] or ["modules-left", "0"].
+func (d *Document) Get(path []string) (any, bool) {
+	n, err := d.find(path)
+	if err != nil {
+		return nil, false
+	}
+	var value any
+	sub := &Document{src: d.src, root: n}
+	if err := json.Unmarshal([]byte(sub.Standard()), &value); err != nil {
+		return nil, false
+	}
+	return value, true
+}
+
+// Set replaces the value at path. Missing object members are added, with
+// objects created for the keys in between, and an index one past the end of
+// an array appends to it.
+func (d *Document) Set(path []string, value any) error {
+	n := d.root
+	for i, segment := range path {
+		switch n.kind {
+		case objectNode:
+			e := lookup(n, segment)
+			if e == nil {
+				return d.insert(n, len(n.entries), segment, nest(path[i+1:], value))
+			}
+			n = e.value
+		case arrayNode:
+			index, err := d.index(n, path[:i+1])
+			if err != nil {
+				return err
+			}
+			if index == len(n.entries) {
+				if i < len(path)-1 {
+					return fmt.Errorf("%s does not exist", formatPath(path[:i+1]))
+				}
+				return d.insert(n, index, "", value)
+			}
+			n = n.entries[index].value
+		default:
+			return fmt.Errorf("%s is not an object or array", formatPath(path[:i]))
+		}
+	}
+
+	_, object := value.(map[string]any)
+	text, err := d.format(value, lineIndent(d.src, n.start), object || multiline(d.src, n))
+	if err != nil {
+		return err
+	}
+	return d.splice(edit{n.start, n.end, text})
+}
+
+// Delete removes the member or element at path.
+func (d *Document) Delete(path []string) error {
+	if len(path) == 0 {
+		return fmt.Errorf("cannot delete the whole document")
+	}
+	parent, err := d.find(path[:len(path)-1])
+	if err != nil {
+		return err
+	}
+	last := path[len(path)-1]
+	switch parent.kind {
+	case objectNode:
+		for i := len(parent.entries) - 1; i >= 0; i-- {
+			if parent.entries[i].key == last {
+				return d.remove(parent, i)
+			}
+		}
+		return fmt.Errorf("%s does not exist", formatPath(path))
+	case arrayNode:
+		index, err := d.index(parent, path)
+		if err != nil {
+			return err
+		}
+		if index == len(parent.entries) {
+			return fmt.Errorf("%s does not exist", formatPath(path))
+		}
+		return d.remove(parent, index)
+	}
+	return fmt.Errorf("%s is not an object or array", formatPath(path[:len(path)-1]))
+}
+
+// Insert adds value to the array at path before index. An index of -1 or
+// the array's length appends.
+func (d *Document) Insert(path []string, index int, value any) error {
+	n, err := d.find(path)
+	if err != nil {
+		return err
+	}
+	if n.kind != arrayNode {
+		return fmt.Errorf("%s is not an array", formatPath(path))
+	}
+	if index < 0 {
+		index = len(n.entries)
+	}
+	if index > len(n.entries) {
+		return fmt.Errorf("index %d is past the end of %s, which has %d elements", index, formatPath(path), len(n.entries))
+	}
+	return d.insert(n, index, "", value)
+}
+
+func (d *Document) find(path []string) (*node, error) {
+	n := d.root
+	for i, segment := range path {
+		switch n.kind {
+		case objectNode:
+			e := lookup(n, segment)
+			if e == nil {
+				return nil, fmt.Errorf("%s does not exist", formatPath(path[:i+1]))
+			}
+			n = e.value
+		case arrayNode:
+			index, err := d.index(n, path[:i+1])
+			if err != nil {
+				return nil, err
+			}
+			if index == len(n.entries) {
+				return nil, fmt.Errorf("%s does not exist", formatPath(path[:i+1]))
+			}
+			n = n.entries[index].value
+		default:
+			return nil, fmt.Errorf("%s is not an object or array", formatPath(path[:i]))
+		}
+	}
+	return n, nil
+}
+
+// index parses the last segment of path as an index into the array n. It may
+// be one past the end.
+func (d *Document) index(n *node, path []string) (int, error) {
+	index, err := strconv.Atoi(path[len(path)-1])
+	if err != nil || index < 0 || index > len(n.entries) {
+		return 0, fmt.Errorf("%s: expected an index into %s, which has %d elements", formatPath(path), formatPath(path[:len(path)-1]), len(n.entries))
+	}
+	return index, nil
+}
+
+// lookup returns the member of an object with key. Like JSON decoders, the
+// last one wins when a key is repeated.
+func lookup(n *node, key string) *entry {
+	for i := len(n.entries) - 1; i >= 0; i-- {
+		if n.entries[i].key == key {
+			return n.entries[i]
+		}
+	}
+	return nil
+}
+
+// nest wraps value in objects for the remaining keys of a path.
+func nest(keys []string, value any) any {
+	for i := len(keys) - 1; i >= 0; i-- {
+		value = map[string]any{keys[i]: value}
+	}
+	return value
+}
+
+func formatPath(path []string) string {
+	if len(path) == 0 {
+		return "the document"
+	}
+	return strings.Join(path, ".")
+}
+
+type edit struct {
+	start, end int
+	text       string
+}
+
+// splice applies edits to the text and parses it again.
+func (d *Document) splice(edits ...edit) error {
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start > edits[j].start })
+	src := d.src
+	for _, e := range edits {
+		src = src[:e.start] + e.text + src[e.end:]
+	}
+	parsed, err := Parse(src)
+	if err != nil {
+		return fmt.Errorf("the edit would break the document: %w", err)
+	}
+	*d = *parsed
+	return nil
+}
+
+// insert adds an entry to the container n before index, in the layout of the
+// entries around it. key is ignored for arrays.
+func (d *Document) insert(n *node, index int, key string, value any) error {
+	// Empty objects are opened up over several lines; arrays such as module
+	// lists stay on one line unless they already span several.
+	wrap := multiline(d.src, n) || (n.kind == objectNode && len(n.entries) == 0)
+	indent := d.childIndent(n)
+
+	text, err := d.format(value, indent, wrap)
+	if err != nil {
+		return err
+	}
+	if n.kind == objectNode {
+		quoted, _ := json.Marshal(key)
+		text = string(quoted) + ": " + text
+	}
+
+	entries := n.entries
+	switch {
+	case len(entries) == 0 && !wrap:
+		return d.splice(edit{n.start + 1, n.close, text})
+	case len(entries) == 0:
+		closeIndent := lineIndent(d.src, n.start)
+		if strings.TrimSpace(d.src[n.start+1:n.close]) == "" {
+			return d.splice(edit{n.start + 1, n.close, "\n" + indent + text + "\n" + closeIndent})
+		}
+		// Keep the comments inside the container above the new entry.
+		at := n.close
+		for at > n.start+1 && (d.src[at-1] == ' ' || d.src[at-1] == '\t') {
+			at--
+		}
+		if d.src[at-1] == '\n' {
+			return d.splice(edit{at, at, indent + text + "\n"})
+		}
+		return d.splice(edit{n.close, n.close, "\n" + indent + text + "\n" + closeIndent})
+	case index < len(entries) && wrap:
+		return d.splice(edit{entries[index].start, entries[index].start, text + ",\n" + indent})
+	case index < len(entries):
+		return d.splice(edit{entries[index].start, entries[index].start, text + ", "})
+	}
+
+	last := entries[len(entries)-1]
+	trailing := last.comma >= 0
+	if !wrap {
+		if trailing {
+			return d.splice(edit{last.comma + 1, last.comma + 1, " " + text + ","})
+		}
+		return d.splice(edit{last.value.end, last.value.end, ", " + text})
+	}
+
+	// Append on a new line after the last entry and its trailing comment,
+	// matching whether the entries end with a comma.
+	anchor := last.value.end
+	if trailing {
+		anchor = last.comma + 1
+	}
+	at := restOfLine(d.src, anchor, n.close)
+	text = "\n" + indent + text
+	if trailing {
+		text += ","
+	}
+	edits := []edit{{at, at, text}}
+	if !trailing {
+		edits = append(edits, edit{last.value.end, last.value.end, ","})
+	}
+	return d.splice(edits...)
+}
+
+// remove deletes entry index of the container n with its comma, and the whole
+// line when the entry has one to itself.
+func (d *Document) remove(n *node, index int) error {
+	entries := n.entries
+	e := entries[index]
+	end := e.value.end
+	if e.comma >= 0 {
+		end = e.comma + 1
+	}
+
+	if !multiline(d.src, n) {
+		switch {
+		case index < len(entries)-1:
+			return d.splice(edit{e.start, entries[index+1].start, ""})
+		case index > 0 && e.comma >= 0:
+			// Keep the trailing comma on the entry that is now last.
+			return d.splice(edit{entries[index-1].comma + 1, end, ""})
+		case index > 0:
+			return d.splice(edit{entries[index-1].value.end, end, ""})
+		}
+		return d.splice(edit{e.start, end, ""})
+	}
+
+	start := e.start
+	lineStart := strings.LastIndexByte(d.src[:start], '\n') + 1
+	lineEnd := restOfLine(d.src, end, n.close)
+	if strings.TrimSpace(d.src[lineStart:start]) == "" && d.src[lineEnd] == '\n' {
+		start, end = lineStart, lineEnd+1
+	}
+	edits := []edit{{start, end, ""}}
+
+	// Without a trailing comma on the last entry, the one before it loses its
+	// comma too.
+	if index == len(entries)-1 && e.comma < 0 && index > 0 {
+		if previous := entries[index-1]; previous.comma >= 0 {
+			edits = append(edits, edit{previous.comma, previous.comma + 1, ""})
+		}
+	}
+	return d.splice(edits...)
+}
+
+// restOfLine returns the end of the line at pos when only whitespace or a
+// comment follows before it, and pos otherwise. It never goes past limit.
+func restOfLine(src string, pos, limit int) int {
+	end := strings.IndexByte(src[pos:limit], '\n')
+	if end < 0 {
+		return pos
+	}
+	rest := strings.TrimSpace(src[pos : pos+end])
+	if rest == "" || strings.HasPrefix(rest, "//") || (strings.HasPrefix(rest, "/*") && strings.HasSuffix(rest, "*/")) {
+		return pos + end
+	}
+	return pos
+}
+
+// multiline reports whether a container puts its entries on their own lines.
+func multiline(src string, n *node) bool {
+	if n.kind != objectNode && n.kind != arrayNode {
+		return false
+	}
+	if len(n.entries) > 0 {
+		return strings.Contains(src[n.start:n.entries[0].start], "\n")
+	}
+	return strings.Contains(src[n.start:n.end], "\n")
+}
+
+// childIndent is the indentation of the entries of n.
+func (d *Document) childIndent(n *node) string {
+	if len(n.entries) > 0 && multiline(d.src, n) {
+		return lineIndent(d.src, n.entries[0].start)
+	}
+	return lineIndent(d.src, n.start) + d.indentUnit()
+}
+
+// lineIndent is the leading whitespace of the line containing pos.
+func lineIndent(src string, pos int) string {
+	start := strings.LastIndexByte(src[:pos], '\n') + 1
+	end := start
+	for end < len(src) && (src[end] == ' ' || src[end] == '\t') {
+		end++
+	}
+	return src[start:end]
+}
+
+// indentUnit is the indentation the document uses per level, taken from the
+// first indented line.
+func (d *Document) indentUnit() string {
+	for _, line := range strings.Split(d.src, "\n") {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed == "" || trimmed == line {
+			continue
+		}
+		if line[0] == '\t' {
+			return "\t"
+		}
+		return line[:len(line)-len(trimmed)]
+	}
+	return "    "
+}
+
+// format encodes value as JSON. Objects and arrays are spread over several
+// lines at indent when wrap is set, and kept on one line otherwise.
+func (d *Document) format(value any, indent string, wrap bool) (string, error) {
+	var buffer bytes.Buffer
+	encoder := json.NewEncoder(&buffer)
+	// Waybar formats are full of <span> markup.
+	encoder.SetEscapeHTML(false)
+	if wrap {
+		encoder.SetIndent(indent, d.indentUnit())
+	}
+	if err := encoder.Encode(value); err != nil {
+		return "", fmt.Errorf("cannot encode %v: %w", value, err)
+	}
+	text := strings.TrimSuffix(buffer.String(), "\n")
+	if !wrap {
+		// The compact encoding has no space after colons and commas.
+		var compact any
+		if err := json.Unmarshal(buffer.Bytes(), &compact); err == nil {
+			switch compact.(type) {
+			case map[string]any, []any:
+				text = spaceOut(text)
+			}
+		}
+	}
+	return text, nil
+}
+
+// spaceOut adds a space after the colons and commas between the tokens of
+// compact JSON.
+func spaceOut(text string) string {
+	var builder strings.Builder
+	inString := false
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		builder.WriteByte(c)
+		switch {
+		case inString && c == '\\':
+			i++
+			builder.WriteByte(text[i])
+		case c == '"':
+			inString = !inString
+		case !inString && (c == ',' || c == ':'):
+			builder.WriteByte(' ')
+		}
+	}
+	return builder.String()
+}
@@ -0,0 +1,273 @@
+// Package jsonc reads and edits JSON with comments and trailing commas, the
+// format of waybar's config.jsonc. Edits are spliced into the original text,
+// so comments, layout and everything that is not changed survive.
+package jsonc
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+type kind int
+
+const (
+	objectNode kind = iota
+	arrayNode
+	stringNode
+	literalNode
+)
+
+// node is a value and where it is in the source.
+type node struct {
+	kind       kind
+	start, end int
+	entries    []*entry
+	// close is the offset of the closing brace or bracket.
+	close int
+}
+
+// entry is a member of an object or an element of an array.
+type entry struct {
+	key string
+	// start is where the entry begins: its key, or the value in arrays.
+	start int
+	value *node
+	// comma is the offset of the comma after the value, or -1.
+	comma int
+}
+
+// SyntaxError reports where a document stops being valid JSONC.
+type SyntaxError struct {
+	Line, Column int
+	Message      string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %s", e.Line, e.Column, e.Message)
+}
+
+// Document is a parsed JSONC file.
+type Document struct {
+	src  string
+	root *node
+}
+
+func Parse(content string) (*Document, error) {
+	p := &parser{src: content}
+	if err := p.skip(); err != nil {
+		return nil, err
+	}
+	root, err := p.value()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.skip(); err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.src) {
+		return nil, p.errorf("unexpected %q after the end of the document", p.src[p.pos])
+	}
+	return &Document{src: content, root: root}, nil
+}
+
+// String returns the document's text.
+func (d *Document) String() string {
+	return d.src
+}
+
+// Decode stores the document's value in v like json.Unmarshal.
+func (d *Document) Decode(v any) error {
+	return json.Unmarshal([]byte(d.Standard()), v)
+}
+
+// Standard returns the document as plain JSON, without comments and trailing
+// commas.
+func (d *Document) Standard() string {
+	var builder strings.Builder
+	var write func(n *node)
+	write = func(n *node) {
+		switch n.kind {
+		case objectNode, arrayNode:
+			open, closing := "{", "}"
+			if n.kind == arrayNode {
+				open, closing = "[", "]"
+			}
+			builder.WriteString(open)
+			for i, e := range n.entries {
+				if i > 0 {
+					builder.WriteString(",")
+				}
+				if n.kind == objectNode {
+					key, _ := json.Marshal(e.key)
+					builder.Write(key)
+					builder.WriteString(":")
+				}
+				write(e.value)
+			}
+			builder.WriteString(closing)
+		default:
+			builder.WriteString(d.src[n.start:n.end])
+		}
+	}
+	write(d.root)
+	return builder.String()
+}
+
+type parser struct {
+	src string
+	pos int
+}
+
+func (p *parser) errorf(format string, args ...any) error {
+	line := strings.Count(p.src[:p.pos], "\n") + 1
+	column := p.pos - strings.LastIndex(p.src[:p.pos], "\n")
+	return &SyntaxError{Line: line, Column: column, Message: fmt.Sprintf(format, args...)}
+}
+
+// skip moves past whitespace and comments.
+func (p *parser) skip() error {
+	for p.pos < len(p.src) {
+		switch {
+		case strings.ContainsRune(" \t\r\n", rune(p.src[p.pos])):
+			p.pos++
+		case strings.HasPrefix(p.src[p.pos:], "//"):
+			if end := strings.IndexByte(p.src[p.pos:], '\n'); end >= 0 {
+				p.pos += end
+			} else {
+				p.pos = len(p.src)
+			}
+		case strings.HasPrefix(p.src[p.pos:], "/*"):
+			end := strings.Index(p.src[p.pos+2:], "*/")
+			if end < 0 {
+				return p.errorf("unterminated comment")
+			}
+			p.pos += end + 4
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+func (p *parser) value() (*node, error) {
+	if p.pos >= len(p.src) {
+		return nil, p.errorf("unexpected end of the document")
+	}
+	switch c := p.src[p.pos]; {
+	case c == '{':
+		return p.container(objectNode, '}')
+	case c == '[':
+		return p.container(arrayNode, ']')
+	case c == '"':
+		start := p.pos
+		if _, err := p.string(); err != nil {
+			return nil, err
+		}
+		return &node{kind: stringNode, start: start, end: p.pos}, nil
+	default:
+		start := p.pos
+		for p.pos < len(p.src) && !strings.ContainsRune(" \t\r\n,:]}/", rune(p.src[p.pos])) {
+			p.pos++
+		}
+		literal := p.src[start:p.pos]
+		var v any
+		if literal == "" || json.Unmarshal([]byte(literal), &v) != nil {
+			p.pos = start
+			return nil, p.errorf("unexpected %q", literalOrChar(literal, c))
+		}
+		return &node{kind: literalNode, start: start, end: p.pos}, nil
+	}
+}
+
+func literalOrChar(literal string, c byte) string {
+	if literal != "" {
+		return literal
+	}
+	return string(c)
+}
+
+// string reads a quoted string and returns its value.
+func (p *parser) string() (string, error) {
+	start := p.pos
+	p.pos++
+	for p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case '\\':
+			p.pos += 2
+		case '"':
+			p.pos++
+			var value string
+			if err := json.Unmarshal([]byte(p.src[start:p.pos]), &value); err != nil {
+				p.pos = start
+				return "", p.errorf("invalid string: %v", err)
+			}
+			return value, nil
+		case '\n':
+			p.pos = start
+			return "", p.errorf("unterminated string")
+		default:
+			p.pos++
+		}
+	}
+	p.pos = start
+	return "", p.errorf("unterminated string")
+}
+
+func (p *parser) container(k kind, closing byte) (*node, error) {
+	n := &node{kind: k, start: p.pos}
+	p.pos++
+	for {
+		if err := p.skip(); err != nil {
+			return nil, err
+		}
+		if p.pos >= len(p.src) {
+			return nil, p.errorf("missing %q", closing)
+		}
+		if p.src[p.pos] == closing {
+			n.close = p.pos
+			p.pos++
+			n.end = p.pos
+			return n, nil
+		}
+		if len(n.entries) > 0 && n.entries[len(n.entries)-1].comma < 0 {
+			return nil, p.errorf("expected ',' or %q", closing)
+		}
+
+		e := &entry{start: p.pos, comma: -1}
+		if k == objectNode {
+			if p.src[p.pos] != '"' {
+				return nil, p.errorf("expected a quoted key")
+			}
+			key, err := p.string()
+			if err != nil {
+				return nil, err
+			}
+			e.key = key
+			if err := p.skip(); err != nil {
+				return nil, err
+			}
+			if p.pos >= len(p.src) || p.src[p.pos] != ':' {
+				return nil, p.errorf("expected ':' after key %q", key)
+			}
+			p.pos++
+			if err := p.skip(); err != nil {
+				return nil, err
+			}
+		}
+
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		e.value = value
+		if err := p.skip(); err != nil {
+			return nil, err
+		}
+		if p.pos < len(p.src) && p.src[p.pos] == ',' {
+			e.comma = p.pos
+			p.pos++
+		}
+		n.entries = append(n.entries, e)
+	}
+}
@@ -0,0 +1,265 @@
+package jsonc
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const sample = `// waybar
+{
+    "layer": "top", // above windows
+    /* modules */
+    "modules-left": ["hyprland/workspaces", "clock",],
+    "clock": {
+        "format": "{:%H:%M}",
+    },
+    "tray": {},
+    "modules-right": [],
+}
+`
+
+func TestRoundTrip(t *testing.T) {
+	doc, err := Parse(sample)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := doc.String(); got != sample {
+		t.Errorf("round trip changed the document:\n%s\nwant:\n%s", got, sample)
+	}
+}
+
+func TestStandard(t *testing.T) {
+	doc, err := Parse(sample)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{"layer":"top","modules-left":["hyprland/workspaces","clock"],"clock":{"format":"{:%H:%M}"},"tray":{},"modules-right":[]}`
+	if got := doc.Standard(); got != want {
+		t.Errorf("Standard = %s\nwant %s", got, want)
+	}
+}
+
+func TestGet(t *testing.T) {
+	doc, err := Parse(sample)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		path []string
+		want any
+	}{
+		{[]string{"layer"}, "top"},
+		{[]string{"modules-left", "1"}, "clock"},
+		{[]string{"clock", "format"}, "{:%H:%M}"},
+		{[]string{"tray"}, map[string]any{}},
+		{[]string{"modules-right"}, []any{}},
+	}
+	for _, test := range tests {
+		if got, ok := doc.Get(test.path); !ok || !reflect.DeepEqual(got, test.want) {
+			t.Errorf("Get(%q) = %#v, %v; want %#v", test.path, got, ok, test.want)
+		}
+	}
+
+	for _, path := range [][]string{{"missing"}, {"modules-left", "2"}, {"layer", "x"}} {
+		if got, ok := doc.Get(path); ok {
+			t.Errorf("Get(%q) = %#v, want nothing", path, got)
+		}
+	}
+}
+
+func TestSet(t *testing.T) {
+	tests := []struct {
+		name  string
+		path  []string
+		value any
+		old   string
+		new   string
+	}{
+		{
+			"replace keeps the comment",
+			[]string{"layer"}, "bottom",
+			`"layer": "top", // above windows`,
+			`"layer": "bottom", // above windows`,
+		},
+		{
+			"nested member",
+			[]string{"clock", "format"}, "{:%A}",
+			`"format": "{:%H:%M}",`,
+			`"format": "{:%A}",`,
+		},
+		{
+			"into an empty object",
+			[]string{"tray", "spacing"}, 4,
+			`"tray": {},`,
+			"\"tray\": {\n        \"spacing\": 4\n    },",
+		},
+		{
+			"append to an array",
+			[]string{"modules-left", "2"}, "cpu",
+			`["hyprland/workspaces", "clock",]`,
+			`["hyprland/workspaces", "clock", "cpu",]`,
+		},
+		{
+			"new member after a trailing comma",
+			[]string{"height"}, 30,
+			"    \"modules-right\": [],\n",
+			"    \"modules-right\": [],\n    \"height\": 30,\n",
+		},
+	}
+
+	for _, test := range tests {
+		doc, err := Parse(sample)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := doc.Set(test.path, test.value); err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		want := strings.Replace(sample, test.old, test.new, 1)
+		if got := doc.String(); got != want {
+			t.Errorf("%s:\n%s\nwant:\n%s", test.name, got, want)
+		}
+	}
+}
+
+func TestInsertIntoEmptyArray(t *testing.T) {
+	doc, err := Parse(sample)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.Insert([]string{"modules-right"}, -1, "battery"); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Replace(sample, `"modules-right": [],`, `"modules-right": ["battery"],`, 1)
+	if got := doc.String(); got != want {
+		t.Errorf("Insert:\n%s\nwant:\n%s", got, want)
+	}
+
+	if err := doc.Insert([]string{"modules-right"}, 3, "cpu"); err == nil {
+		t.Error("Insert past the end of the array succeeded")
+	}
+	if err := doc.Insert([]string{"clock"}, 0, "cpu"); err == nil {
+		t.Error("Insert into an object succeeded")
+	}
+}
+
+func TestDelete(t *testing.T) {
+	tests := []struct {
+		name string
+		path []string
+		old  string
+		new  string
+	}{
+		{
+			"member on its own line",
+			[]string{"tray"},
+			"    \"tray\": {},\n", "",
+		},
+		{
+			"first element of a one-line array",
+			[]string{"modules-left", "0"},
+			`["hyprland/workspaces", "clock",]`, `["clock",]`,
+		},
+		{
+			"last element of a one-line array",
+			[]string{"modules-left", "1"},
+			`["hyprland/workspaces", "clock",]`, `["hyprland/workspaces",]`,
+		},
+	}
+
+	for _, test := range tests {
+		doc, err := Parse(sample)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := doc.Delete(test.path); err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		want := strings.Replace(sample, test.old, test.new, 1)
+		if got := doc.String(); got != want {
+			t.Errorf("%s:\n%s\nwant:\n%s", test.name, got, want)
+		}
+	}
+
+	doc, err := Parse(sample)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.Delete([]string{"missing"}); err == nil {
+		t.Error("Delete of a missing member succeeded")
+	}
+	if err := doc.Delete(nil); err == nil {
+		t.Error("Delete of the whole document succeeded")
+	}
+}
+
+func TestDeleteLastWithoutTrailingComma(t *testing.T) {
+	doc, err := Parse("{\n  \"a\": 1,\n  \"b\": 2\n}\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := doc.Delete([]string{"b"}); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := doc.String(), "{\n  \"a\": 1\n}\n"; got != want {
+		t.Errorf("Delete:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestArrayRoot(t *testing.T) {
+	content := `[
+  {"position": "top"},
+  {"position": "bottom"}, // second screen
+]
+`
+	doc, err := Parse(content)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := doc.Get([]string{"1", "position"}); got != "bottom" {
+		t.Errorf("Get = %#v, want bottom", got)
+	}
+	if err := doc.Set([]string{"1", "position"}, "left"); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Replace(content, `"bottom"`, `"left"`, 1)
+	if got := doc.String(); got != want {
+		t.Errorf("Set:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		content      string
+		line, column int
+		message      string
+	}{
+		{"", 1, 1, "unexpected end of the document"},
+		{"{", 1, 2, `missing '}'`},
+		{`{"a": 1 "b": 2}`, 1, 9, `expected ',' or '}'`},
+		{`{a: 1}`, 1, 2, "expected a quoted key"},
+		{`{"a" 1}`, 1, 6, `expected ':' after key "a"`},
+		{`{"a": tru}`, 1, 7, `unexpected "tru"`},
+		{"{\"a\": \"open\n}", 1, 7, "unterminated string"},
+		{"{\n  /* never closed\n  \"a\": 1\n}", 2, 3, "unterminated comment"},
+		{"{} /* after the end", 1, 4, "unterminated comment"},
+		{`{} {}`, 1, 4, `unexpected '{' after the end of the document`},
+	}
+
+	for _, test := range tests {
+		_, err := Parse(test.content)
+		var syntax *SyntaxError
+		if !errors.As(err, &syntax) {
+			t.Errorf("Parse(%q) = %v, want a SyntaxError", test.content, err)
+			continue
+		}
+		if syntax.Line != test.line || syntax.Column != test.column || syntax.Message != test.message {
+			t.Errorf("Parse(%q) = %v, want line %d, column %d: %s", test.content, err, test.line, test.column, test.message)
+		}
+	}
+}
@@ -0,0 +1,182 @@
+// Package lint checks a Hyprland config tree, including the configs of
+// hyprpaper, hyprlock, hypridle and hyprsunset kept next to it. Checks are
+// Rules; the built-in ones are registered at init and others can be added
+// with Register.
+package lint
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/saat-sy/hyprlander/pkg/config"
+	"github.com/saat-sy/hyprlander/pkg/hyprlang"
+	"github.com/saat-sy/hyprlander/pkg/schema"
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+type Finding struct {
+	Rule     string   `json:"rule"`
+	Severity Severity `json:"severity"`
+	File     string   `json:"file"`
+	Line     int      `json:"line,omitempty"`
+	Message  string   `json:"message"`
+}
+
+type Rule interface {
+	ID() string
+	Description() string
+	Check(target *Target) []Finding
+}
+
+// Target is the config tree a lint run looks at.
+type Target struct {
+	Root   string
+	Config *hyprlang.Config
+	// Ecosystem holds the configs of the other hypr programs found in root.
+	Ecosystem map[schema.Program]*hyprlang.Config
+	Tree      []config.TreeEntry
+}
+
+// ProgramConfig is a loaded config and the program that reads it.
+type ProgramConfig struct {
+	Program schema.Program
+	Config  *hyprlang.Config
+}
+
+// Configs returns the Hyprland config followed by the ecosystem configs.
+func (t *Target) Configs() []ProgramConfig {
+	configs := []ProgramConfig{{schema.Hyprland, t.Config}}
+	for _, program := range schema.Ecosystem() {
+		if cfg := t.Ecosystem[program]; cfg != nil {
+			configs = append(configs, ProgramConfig{program, cfg})
+		}
+	}
+	return configs
+}
+
+// Load parses the main config in root with everything it sources, and the
+// configs of the other hypr programs that exist in root.
+func Load(root string) (*Target, error) {
+	root, err := filepath.Abs(root)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := hyprlang.LoadConfig(filepath.Join(root, config.MainConfigFileName))
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := config.GetTreeFromDir(root)
+	if err != nil {
+		return nil, fmt.Errorf("error building directory tree: %w", err)
+	}
+
+	ecosystem := make(map[schema.Program]*hyprlang.Config)
+	for _, program := range schema.Ecosystem() {
+		path := filepath.Join(root, program.ConfigFileName())
+		if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		programConfig, err := hyprlang.LoadConfig(path)
+		if err != nil {
+			return nil, err
+		}
+		ecosystem[program] = programConfig
+	}
+
+	return &Target{Root: root, Config: cfg, Ecosystem: ecosystem, Tree: tree}, nil
+}
+
+// Relative returns path relative to the target's root when it is inside it.
+func (t *Target) Relative(path string) string {
+	if rel, err := filepath.Rel(t.Root, path); err == nil && !strings.HasPrefix(rel, "..") {
+		return rel
+	}
+	return path
+}
+
+var registry []Rule
+
+// Register adds a rule to the set returned by Rules.
+func Register(rule Rule) {
+	registry = append(registry, rule)
+}
+
+func Rules() []Rule {
+	return registry
+}
+
+// Select returns the registered rules, limited to enable when it is not empty
+// and without the ones in disable.
+func Select(enable, disable []string) ([]Rule, error) {
+	for _, id := range slices.Concat(enable, disable) {
+		if !slices.ContainsFunc(registry, func(rule Rule) bool { return rule.ID() == id }) {
+			return nil, fmt.Errorf("unknown lint rule %q", id)
+		}
+	}
+
+	var rules []Rule
+	for _, rule := range registry {
+		if len(enable) > 0 && !slices.Contains(enable, rule.ID()) {
+			continue
+		}
+		if slices.Contains(disable, rule.ID()) {
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+// Run applies rules to the target and returns the findings ordered by file
+// and line.
+func Run(target *Target, rules []Rule) []Finding {
+	var findings []Finding
+	for _, rule := range rules {
+		findings = append(findings, rule.Check(target)...)
+	}
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		if findings[i].File != findings[j].File {
+			return findings[i].File < findings[j].File
+		}
+		return findings[i].Line < findings[j].Line
+	})
+	return findings
+}
+
+// Count returns the number of errors and warnings.
+func Count(findings []Finding) (int, int) {
+	errors, warnings := 0, 0
+	for _, finding := range findings {
+		if finding.Severity == SeverityError {
+			errors++
+		} else {
+			warnings++
+		}
+	}
+	return errors, warnings
+}
+
+// ruleFunc adapts a function to the Rule interface.
+type ruleFunc struct {
+	id          string
+	description string
+	check       func(*Target) []Finding
+}
+
+func (r ruleFunc) ID() string                     { return r.id }
+func (r ruleFunc) Description() string            { return r.description }
+func (r ruleFunc) Check(target *Target) []Finding { return r.check(target) }
@@ -0,0 +1,214 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/saat-sy/hyprlander/pkg/config"
+	"github.com/saat-sy/hyprlander/pkg/hyprlang"
+	"github.com/saat-sy/hyprlander/pkg/schema"
+)
+
+func init() {
+	Register(ruleFunc{"unknown-option", "options that are not in the option schema of their program", checkUnknownOptions})
+	Register(ruleFunc{"deprecated-option", "options that were removed or renamed", checkDeprecatedOptions})
+	Register(ruleFunc{"deprecated-syntax", "keywords with a newer replacement", checkDeprecatedSyntax})
+	Register(ruleFunc{"invalid-value", "values of the wrong type or out of range", checkInvalidValues})
+	Register(ruleFunc{"undefined-variable", "$variables that are used but never defined", checkUndefinedVariables})
+	Register(ruleFunc{"unused-variable", "$variables that are defined but never used", checkUnusedVariables})
+	Register(ruleFunc{"missing-source", "source lines whose target does not exist", checkMissingSources})
+	Register(ruleFunc{"unsourced-file", ".conf files in the config directory that are never sourced", checkUnsourcedFiles})
+}
+
+var variablePattern = regexp.MustCompile(`\$([A-Za-z_][A-Za-z0-9_]*)`)
+
+// optionLines calls fn for every line of every config that sets a schema
+// option, with its value expanded. Values that still reference an undefined
+// variable are skipped; undefined-variable reports those.
+func optionLines(target *Target, fn func(program schema.Program, line hyprlang.ConfigLine, value string)) {
+	for _, pc := range target.Configs() {
+		for _, line := range pc.Config.Lines {
+			if line.Kind != hyprlang.Assignment || schema.IsKeywordIn(pc.Program, line.Line) {
+				continue
+			}
+			value := pc.Config.Expand(line.Value)
+			if variablePattern.MatchString(value) {
+				continue
+			}
+			fn(pc.Program, line, value)
+		}
+	}
+}
+
+func finding(rule string, severity Severity, line hyprlang.ConfigLine, message string) Finding {
+	return Finding{Rule: rule, Severity: severity, File: line.File, Line: line.Number, Message: message}
+}
+
+func checkUnknownOptions(target *Target) []Finding {
+	var findings []Finding
+	optionLines(target, func(program schema.Program, line hyprlang.ConfigLine, value string) {
+		if _, ok := schema.LookupIn(program, line.FullKey()); ok {
+			return
+		}
+		problem := schema.ValidateIn(program, line.FullKey(), value)
+		findings = append(findings, finding("unknown-option", SeverityWarning, line, fmt.Sprintf("%s: %s", line.FullKey(), problem.Message)))
+	})
+	return findings
+}
+
+func checkDeprecatedOptions(target *Target) []Finding {
+	var findings []Finding
+	optionLines(target, func(program schema.Program, line hyprlang.ConfigLine, value string) {
+		if option, ok := schema.LookupIn(program, line.FullKey()); ok && option.Deprecated != nil {
+			findings = append(findings, finding("deprecated-option", SeverityError, line, fmt.Sprintf("%s: %s", option.Path, schema.DeprecationMessage(option))))
+		}
+	})
+	return findings
+}
+
+var deprecatedKeywords = map[string]string{
+	"windowrule": "windowrule is the legacy syntax, use windowrulev2 with class: or title: matchers",
+	"blurls":     "blurls is deprecated, use layerrule = blur, NAMESPACE",
+}
+
+func checkDeprecatedSyntax(target *Target) []Finding {
+	var findings []Finding
+	for _, line := range target.Config.Lines {
+		if line.Kind != hyprlang.Assignment || len(line.Category) > 0 {
+			continue
+		}
+		if message, ok := deprecatedKeywords[line.Key]; ok {
+			findings = append(findings, finding("deprecated-syntax", SeverityWarning, line, message))
+		}
+	}
+	return findings
+}
+
+func checkInvalidValues(target *Target) []Finding {
+	var findings []Finding
+	optionLines(target, func(program schema.Program, line hyprlang.ConfigLine, value string) {
+		option, ok := schema.LookupIn(program, line.FullKey())
+		if !ok || option.Deprecated != nil {
+			return
+		}
+		if err := schema.ValidateValue(option, value); err != nil {
+			findings = append(findings, finding("invalid-value", SeverityError, line, fmt.Sprintf("%s: %v", option.Path, err)))
+		}
+	})
+	return findings
+}
+
+// resolveVariable finds the defined variable a reference expands to. Like
+// Hyprland's substitution, $mainModShift uses $mainMod if only that exists.
+func resolveVariable(name string, vars map[string]string) (string, bool) {
+	for end := len(name); end > 0; end-- {
+		if _, ok := vars[name[:end]]; ok {
+			return name[:end], true
+		}
+	}
+	return "", false
+}
+
+// builtinVariables are the variables a program substitutes itself, such as
+// the $TIME in a hyprlock label.
+var builtinVariables = map[schema.Program][]string{
+	schema.Hyprlock: {
+		"USER", "DESC", "TIME", "TIME12", "LAYOUT", "ATTEMPTS", "FAIL", "PROMPT",
+		"PAMPROMPT", "PAMFAIL", "FPRINTPROMPT", "FPRINTFAIL",
+	},
+}
+
+// checkedValue is the part of a line in which $ must refer to a config
+// variable. Commands run by exec, env values and source paths may use shell
+// and environment variables, so they are left out, as are the commands
+// hypridle and hyprlock run.
+func checkedValue(program schema.Program, line hyprlang.ConfigLine) string {
+	switch {
+	case strings.HasPrefix(line.Key, "exec"), line.Key == "env", line.Key == "envd", line.Kind == hyprlang.Source:
+		return ""
+	case program != schema.Hyprland && (strings.HasSuffix(line.Key, "cmd") || strings.HasPrefix(line.Key, "on-")):
+		return ""
+	case strings.HasPrefix(line.Key, "bind"):
+		fields := 4
+		if strings.Contains(strings.TrimPrefix(line.Key, "bind"), "d") {
+			fields = 5
+		}
+		parts := strings.SplitN(line.Value, ",", fields)
+		if len(parts) == fields {
+			dispatcher := strings.TrimSpace(parts[fields-2])
+			if dispatcher == "exec" || dispatcher == "execr" {
+				return strings.Join(parts[:fields-1], ",")
+			}
+		}
+	}
+	return line.Value
+}
+
+func checkUndefinedVariables(target *Target) []Finding {
+	var findings []Finding
+	for _, pc := range target.Configs() {
+		for _, line := range pc.Config.Lines {
+			if line.Kind != hyprlang.Assignment && line.Kind != hyprlang.Variable {
+				continue
+			}
+			for _, match := range variablePattern.FindAllStringSubmatch(checkedValue(pc.Program, line), -1) {
+				if slices.Contains(builtinVariables[pc.Program], match[1]) {
+					continue
+				}
+				if _, ok := resolveVariable(match[1], pc.Config.Vars); !ok {
+					findings = append(findings, finding("undefined-variable", SeverityError, line, fmt.Sprintf("$%s is not defined", match[1])))
+				}
+			}
+		}
+	}
+	return findings
+}
+
+func checkUnusedVariables(target *Target) []Finding {
+	var findings []Finding
+	for _, pc := range target.Configs() {
+		used := make(map[string]bool)
+		for _, line := range pc.Config.Lines {
+			for _, match := range variablePattern.FindAllStringSubmatch(line.Value, -1) {
+				if name, ok := resolveVariable(match[1], pc.Config.Vars); ok {
+					used[name] = true
+				}
+			}
+		}
+
+		for _, line := range pc.Config.Lines {
+			name := strings.TrimPrefix(line.Key, "$")
+			if line.Kind == hyprlang.Variable && !used[name] {
+				findings = append(findings, finding("unused-variable", SeverityWarning, line, fmt.Sprintf("$%s is never used", name)))
+			}
+		}
+	}
+	return findings
+}
+
+func checkMissingSources(target *Target) []Finding {
+	var findings []Finding
+	for _, pc := range target.Configs() {
+		for _, missing := range pc.Config.Missing {
+			findings = append(findings, finding("missing-source", SeverityError, missing.ConfigLine, fmt.Sprintf("sourced file %s does not exist", target.Relative(missing.Target))))
+		}
+	}
+	return findings
+}
+
+func checkUnsourcedFiles(target *Target) []Finding {
+	var findings []Finding
+	for _, entry := range target.Tree {
+		if entry.Role == config.RoleNotSourced {
+			findings = append(findings, Finding{
+				Rule:     "unsourced-file",
+				Severity: SeverityWarning,
+				File:     entry.Path,
+				Message:  fmt.Sprintf("%s is never sourced by %s or another hypr config", target.Relative(entry.Path), config.MainConfigFileName),
+			})
+		}
+	}
+	return findings
+}
@@ -0,0 +1,156 @@
+// Package migrate rewrites config lines that older Hyprland releases accepted
+// into their current form. Every rewrite is mechanical: it only applies when
+// the new line means exactly what the old one did.
+package migrate
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/saat-sy/hyprlander/pkg/docs"
+	"github.com/saat-sy/hyprlander/pkg/hyprlang"
+)
+
+// Edit is what a rewrite does to one line.
+type Edit struct {
+	// Replace holds the lines that take the place of the original one.
+	Replace []string
+	// Append holds top-level lines added at the end of the file, for options
+	// that moved to a category the line's block cannot express.
+	Append      []string
+	Description string
+}
+
+type Rewrite struct {
+	// Since is the release that deprecated the old form; the rewrite is only
+	// used when migrating to that release or a later one. Rewrites without
+	// one apply to every version, so it may only be left out when no release
+	// the table covers accepts the old form.
+	Since string
+	Name  string
+	Apply func(line hyprlang.Line) (Edit, bool)
+}
+
+// Change is one rewritten line.
+type Change struct {
+	Line        int
+	Rewrite     string
+	Description string
+}
+
+// Result is the migration of one file.
+type Result struct {
+	Path     string
+	Original string
+	Migrated string
+	Changes  []Change
+}
+
+// Latest is the newest release the rewrite table knows about.
+func Latest() string {
+	return docs.Version()
+}
+
+// Rewrites returns the rewrites needed to reach version.
+func Rewrites(version string) ([]Rewrite, error) {
+	target, err := parseVersion(version)
+	if err != nil {
+		return nil, err
+	}
+	latest, _ := parseVersion(Latest())
+	if compareVersions(target, latest) > 0 {
+		return nil, fmt.Errorf("the migration table only covers releases up to %s", Latest())
+	}
+
+	var selected []Rewrite
+	for _, rewrite := range table() {
+		if rewrite.Since == "" {
+			selected = append(selected, rewrite)
+			continue
+		}
+		since, _ := parseVersion(rewrite.Since)
+		if compareVersions(since, target) <= 0 {
+			selected = append(selected, rewrite)
+		}
+	}
+	return selected, nil
+}
+
+// Plan migrates every file of the config and returns the ones that change.
+func Plan(cfg *hyprlang.Config, version string) ([]Result, error) {
+	rewrites, err := Rewrites(version)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []Result
+	for _, doc := range cfg.Files {
+		if result := migrateDocument(doc, rewrites); len(result.Changes) > 0 {
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
+
+func migrateDocument(doc *hyprlang.Document, rewrites []Rewrite) Result {
+	result := Result{Path: doc.Path, Original: doc.String()}
+
+	var lines, appended []string
+	for _, line := range doc.Lines {
+		edit, name, ok := applyFirst(line, rewrites)
+		if !ok {
+			lines = append(lines, line.Raw)
+			continue
+		}
+		lines = append(lines, edit.Replace...)
+		appended = append(appended, edit.Append...)
+		result.Changes = append(result.Changes, Change{Line: line.Number, Rewrite: name, Description: edit.Description})
+	}
+
+	if len(appended) > 0 {
+		for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+			lines = lines[:len(lines)-1]
+		}
+		lines = append(lines, "", "# Moved by hyprlander migrate")
+		lines = append(lines, appended...)
+		lines = append(lines, "")
+	}
+
+	result.Migrated = strings.Join(lines, "\n")
+	return result
+}
+
+func applyFirst(line hyprlang.Line, rewrites []Rewrite) (Edit, string, bool) {
+	for _, rewrite := range rewrites {
+		if edit, ok := rewrite.Apply(line); ok {
+			return edit, rewrite.Name, true
+		}
+	}
+	return Edit{}, "", false
+}
+
+func parseVersion(version string) ([3]int, error) {
+	var parsed [3]int
+	parts := strings.Split(strings.TrimPrefix(strings.TrimSpace(version), "v"), ".")
+	if len(parts) < 2 || len(parts) > 3 {
+		return parsed, fmt.Errorf("invalid version %q, expected e.g. v0.45.0", version)
+	}
+	for i, part := range parts {
+		number, err := strconv.Atoi(part)
+		if err != nil {
+			return parsed, fmt.Errorf("invalid version %q, expected e.g. v0.45.0", version)
+		}
+		parsed[i] = number
+	}
+	return parsed, nil
+}
+
+func compareVersions(a, b [3]int) int {
+	for i := range a {
+		if a[i] != b[i] {
+			return a[i] - b[i]
+		}
+	}
+	return 0
+}
@@ -0,0 +1,64 @@
+package migrate
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestRewrites(t *testing.T) {
+	tests := []struct {
+		version string
+		want    []string
+		without []string
+	}{
+		{
+			version: "v0.40.0",
+			want:    []string{"windowrule", "blurls"},
+			without: []string{"new_is_master", "no_direct_scanout", "decoration:drop_shadow"},
+		},
+		{
+			version: "v0.41.2",
+			want:    []string{"windowrule", "new_is_master"},
+			without: []string{"no_direct_scanout", "decoration:drop_shadow"},
+		},
+		{
+			version: "0.42",
+			want:    []string{"new_is_master", "no_direct_scanout"},
+			without: []string{"decoration:drop_shadow"},
+		},
+		{
+			version: "v0.45.0",
+			want:    []string{"new_is_master", "no_direct_scanout", "decoration:drop_shadow", "dwindle:no_gaps_when_only"},
+		},
+	}
+
+	for _, test := range tests {
+		rewrites, err := Rewrites(test.version)
+		if err != nil {
+			t.Errorf("Rewrites(%s): %v", test.version, err)
+			continue
+		}
+		var names []string
+		for _, rewrite := range rewrites {
+			names = append(names, rewrite.Name)
+		}
+		for _, name := range test.want {
+			if !slices.Contains(names, name) {
+				t.Errorf("Rewrites(%s) is missing %s", test.version, name)
+			}
+		}
+		for _, name := range test.without {
+			if slices.Contains(names, name) {
+				t.Errorf("Rewrites(%s) includes %s, which is deprecated later", test.version, name)
+			}
+		}
+	}
+}
+
+func TestRewritesInvalidVersion(t *testing.T) {
+	for _, version := range []string{"", "latest", "v1", "v0.x.0", "v0.99.0"} {
+		if _, err := Rewrites(version); err == nil {
+			t.Errorf("Rewrites(%q) succeeded", version)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package migrate
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/saat-sy/hyprlander/pkg/hyprlang"
+	"github.com/saat-sy/hyprlander/pkg/schema"
+)
+
+// table lists the rewrites, oldest first. Renamed and removed options come
+// from the schema's deprecations; the rest change a value or the syntax.
+func table() []Rewrite {
+	rewrites := []Rewrite{
+		{
+			Since: "v0.41.0",
+			Name:  "new_is_master",
+			Apply: func(line hyprlang.Line) (Edit, bool) {
+				if line.Kind != hyprlang.Assignment || line.FullKey() != "master:new_is_master" {
+					return Edit{}, false
+				}
+				enabled, ok := schema.ParseBool(line.Value)
+				if !ok {
+					return Edit{}, false
+				}
+				status := "slave"
+				if enabled {
+					status = "master"
+				}
+				return moveOption(line, "master:new_status", status), true
+			},
+		},
+		{
+			Since: "v0.42.0",
+			Name:  "no_direct_scanout",
+			Apply: func(line hyprlang.Line) (Edit, bool) {
+				if line.Kind != hyprlang.Assignment || line.FullKey() != "misc:no_direct_scanout" {
+					return Edit{}, false
+				}
+				disabled, ok := schema.ParseBool(line.Value)
+				if !ok {
+					return Edit{}, false
+				}
+				return moveOption(line, "render:direct_scanout", fmt.Sprint(!disabled)), true
+			},
+		},
+		{
+			Name:  "windowrule",
+			Apply: windowruleV2,
+		},
+		{
+			Name:  "blurls",
+			Apply: blurlsLayerrule,
+		},
+	}
+
+	for _, option := range schema.Options() {
+		deprecation := option.Deprecated
+		switch {
+		case deprecation == nil:
+		case deprecation.Replacement != "" && deprecation.Note == "":
+			rewrites = append(rewrites, renameRewrite(option.Path, deprecation))
+		case deprecation.Replacement == "":
+			rewrites = append(rewrites, removeRewrite(option))
+		}
+	}
+
+	return rewrites
+}
+
+func renameRewrite(path string, deprecation *schema.Deprecation) Rewrite {
+	return Rewrite{
+		Since: deprecation.Since,
+		Name:  path,
+		Apply: func(line hyprlang.Line) (Edit, bool) {
+			if line.Kind != hyprlang.Assignment || line.FullKey() != path {
+				return Edit{}, false
+			}
+			key, ok := relativeKey(line, deprecation.Replacement)
+			if !ok {
+				return moveOption(line, deprecation.Replacement, line.Value), true
+			}
+			return Edit{
+				Replace:     []string{strings.Replace(line.Raw, line.Key, key, 1)},
+				Description: fmt.Sprintf("renamed %s to %s", path, deprecation.Replacement),
+			}, true
+		},
+	}
+}
+
+// removeRewrite comments out options that were removed without a direct
+// replacement, keeping the line so the user can see what it did.
+func removeRewrite(option schema.Option) Rewrite {
+	return Rewrite{
+		Since: option.Deprecated.Since,
+		Name:  option.Path,
+		Apply: func(line hyprlang.Line) (Edit, bool) {
+			if line.Kind != hyprlang.Assignment || line.FullKey() != option.Path {
+				return Edit{}, false
+			}
+			message := schema.DeprecationMessage(option)
+			return Edit{
+				Replace:     []string{indentOf(line.Raw) + "# " + strings.TrimSpace(line.Raw) + " # " + message},
+				Description: fmt.Sprintf("commented out %s: %s", option.Path, message),
+			}, true
+		},
+	}
+}
+
+// windowruleV2 turns "windowrule = RULE, WINDOW" into its windowrulev2 form.
+// A plain WINDOW is a class regex and "title:" selects the title instead.
+func windowruleV2(line hyprlang.Line) (Edit, bool) {
+	if line.Kind != hyprlang.Assignment || len(line.Category) > 0 || line.Key != "windowrule" {
+		return Edit{}, false
+	}
+	rule, window, ok := strings.Cut(line.Value, ",")
+	if !ok {
+		return Edit{}, false
+	}
+	rule, window = strings.TrimSpace(rule), strings.TrimSpace(window)
+
+	matcher := "class:" + window
+	if title, ok := strings.CutPrefix(window, "title:"); ok {
+		matcher = "title:" + title
+	} else if strings.Contains(window, ":") {
+		return Edit{}, false
+	}
+
+	return Edit{
+		Replace:     []string{formatLine(line, "windowrulev2", rule+", "+matcher)},
+		Description: "converted windowrule to windowrulev2",
+	}, true
+}
+
+func blurlsLayerrule(line hyprlang.Line) (Edit, bool) {
+	if line.Kind != hyprlang.Assignment || len(line.Category) > 0 || line.Key != "blurls" {
+		return Edit{}, false
+	}
+	namespace := strings.TrimSpace(line.Value)
+	if strings.HasPrefix(namespace, "remove,") {
+		return Edit{}, false
+	}
+	return Edit{
+		Replace:     []string{formatLine(line, "layerrule", "blur, "+namespace)},
+		Description: "converted blurls to layerrule",
+	}, true
+}
+
+// moveOption sets newPath to value in place of line. When the line's block
+// cannot hold newPath the option is moved to the end of the file.
+func moveOption(line hyprlang.Line, newPath, value string) Edit {
+	description := fmt.Sprintf("replaced %s with %s = %s", line.FullKey(), newPath, value)
+	if key, ok := relativeKey(line, newPath); ok {
+		return Edit{Replace: []string{formatLine(line, key, value)}, Description: description}
+	}
+	appended := newPath + " = " + escape(value)
+	if line.Comment != "" {
+		appended += " # " + line.Comment
+	}
+	return Edit{Append: []string{appended}, Description: description + " at the end of the file"}
+}
+
+// relativeKey returns the key that sets path from inside line's block.
+func relativeKey(line hyprlang.Line, path string) (string, bool) {
+	if len(line.Category) == 0 {
+		return path, true
+	}
+	prefix := strings.Join(line.Category, ":") + ":"
+	return strings.CutPrefix(path, prefix)
+}
+
+func formatLine(line hyprlang.Line, key, value string) string {
+	formatted := indentOf(line.Raw) + key + " = " + escape(value)
+	if line.Comment != "" {
+		formatted += " # " + line.Comment
+	}
+	return formatted
+}
+
+func indentOf(raw string) string {
+	return raw[:len(raw)-len(strings.TrimLeft(raw, " \t"))]
+}
+
+// escape doubles # so it is not read as the start of a comment.
+func escape(value string) string {
+	return strings.ReplaceAll(value, "#", "##")
+}
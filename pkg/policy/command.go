@@ -0,0 +1,99 @@
+package policy
+
+import (
+	"slices"
+	"strings"
+)
+
+// shellExecute runs commands without a shell: the command is split on
+// whitespace and the first field is run with the others as its arguments.
+// Pipes, quotes and substitutions are passed to the program as literal
+// arguments, so the program alone decides what a command can do.
+
+// launchers run the program or code given in their arguments.
+var launchers = []string{
+	"sh", "bash", "zsh", "fish", "dash", "env", "xargs", "sudo", "doas", "pkexec",
+	"nohup", "setsid", "systemd-run", "uwsm", "timeout", "nice", "watch",
+	"python", "python3", "perl", "ruby", "node", "lua", "awk", "gawk",
+}
+
+// execProbes are commands that start an arbitrary program. A saved pattern
+// must not match any of them.
+var execProbes = []string{
+	"hyprctl dispatch exec x",
+	"hyprctl dispatch execr x",
+	"hyprctl keyword bind SUPER,X,exec,x",
+	"hyprctl --batch x",
+	"git -c x",
+	"find . -exec x",
+	"sh -c x",
+	"env x",
+	"xargs x",
+}
+
+// canExec reports whether command can start a program other than its own,
+// like "hyprctl dispatch exec", "git -c" or "find -exec". Such commands are
+// always confirmed, whatever the rules say.
+func canExec(command string) bool {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return false
+	}
+	program, args := fields[0], fields[1:]
+	if slices.Contains(launchers, program) {
+		return true
+	}
+
+	for _, arg := range args {
+		switch program {
+		case "hyprctl":
+			// exec and execr dispatchers, exec binds set with keyword, and
+			// batches that may contain either.
+			if strings.HasPrefix(arg, "exec") || strings.Contains(arg, ",exec") ||
+				arg == "--batch" || arg == "-b" || arg == "plugin" {
+				return true
+			}
+		case "git":
+			if arg == "-c" || arg == "config" || strings.HasPrefix(arg, "--exec-path") {
+				return true
+			}
+		case "find":
+			if arg == "-exec" || arg == "-execdir" || arg == "-ok" || arg == "-okdir" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// coversExec reports whether a command pattern allows a command that can
+// start another program.
+func coversExec(pattern string) bool {
+	if canExec(pattern) {
+		return true
+	}
+	return slices.ContainsFunc(execProbes, func(probe string) bool {
+		return Match(pattern, probe)
+	})
+}
+
+// suggestCommand proposes the program and its subcommand, such as
+// "hyprctl reload", or "hyprctl getoption *" when arguments follow. Commands
+// whose pattern would also allow starting other programs are only suggested
+// exactly.
+func suggestCommand(command string) string {
+	fields := strings.Fields(command)
+	exact := strings.Join(fields, " ")
+	if len(fields) < 2 || strings.HasPrefix(fields[1], "-") {
+		return exact
+	}
+
+	pattern := fields[0] + " " + fields[1]
+	if len(fields) > 2 {
+		pattern += " *"
+	}
+	if coversExec(pattern) {
+		return exact
+	}
+	return pattern
+}
@@ -0,0 +1,149 @@
+package policy
+
+import (
+	"fmt"
+	"maps"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Rule always allows calls of Tool whose subject matches Pattern. In patterns
+// "*" matches any run of characters, including spaces and slashes. Commands
+// are matched with their arguments separated by single spaces, the way
+// shellExecute splits them. Commands that can start another program, such as
+// "hyprctl dispatch exec", never match, so a rule cannot approve one.
+type Rule struct {
+	Tool    string `toml:"tool"`
+	Pattern string `toml:"pattern"`
+}
+
+func (r Rule) String() string {
+	return fmt.Sprintf("%s(%s)", r.Tool, r.Pattern)
+}
+
+func (r Rule) Matches(tool, subject string) bool {
+	if tool == "shellExecute" && canExec(subject) {
+		return false
+	}
+	return r.Tool == tool && Match(r.Pattern, subject)
+}
+
+// Check refuses patterns that would allow every call of a tool, or commands
+// that start other programs.
+func (r Rule) Check() error {
+	if strings.Trim(r.Pattern, "* ") == "" {
+		return fmt.Errorf("pattern %q would allow every %s call", r.Pattern, r.Tool)
+	}
+	if r.Tool == "shellExecute" && coversExec(r.Pattern) {
+		return fmt.Errorf("pattern %q would allow commands that start other programs", r.Pattern)
+	}
+	return nil
+}
+
+// ParseRule reads a rule written as tool:pattern.
+func ParseRule(text string) (Rule, error) {
+	tool, pattern, ok := strings.Cut(strings.TrimSpace(text), ":")
+	if !ok || tool == "" || pattern == "" {
+		return Rule{}, fmt.Errorf("invalid rule %q, expected tool:pattern", text)
+	}
+	return Rule{Tool: tool, Pattern: pattern}, nil
+}
+
+// Subject is the part of a call that rules are matched against: the command
+// and its arguments for shellExecute, the absolute, cleaned path for file tools, so "dir/*"
+// cannot reach outside dir through "..", and what a call changes for the
+// theme, daemon and waybar tools.
+func Subject(tool string, args map[string]interface{}) string {
+	switch tool {
+	case "shellExecute":
+		command, _ := args["command"].(string)
+		return strings.Join(strings.Fields(command), " ")
+	case "applyTheme":
+		name, _ := args["name"].(string)
+		return name
+	case "restartDaemon":
+		program, _ := args["program"].(string)
+		return program
+	case "getWallpaperPalette":
+		image, _ := args["image"].(string)
+		return cleanPath(image)
+	case "setWaybarOption":
+		// The option path, such as clock.format.
+		items, _ := args["path"].([]interface{})
+		keys := make([]string, 0, len(items))
+		for _, item := range items {
+			if key, ok := item.(string); ok {
+				keys = append(keys, key)
+			}
+		}
+		return strings.Join(keys, ".")
+	case "addWaybarModule", "removeWaybarModule":
+		module, _ := args["module"].(string)
+		if section, _ := args["section"].(string); section != "" {
+			return section + "/" + module
+		}
+		return module
+	}
+	if path, ok := args["path"].(string); ok {
+		return cleanPath(path)
+	}
+
+	// Other tools are described by their first text argument.
+	keys := slices.Sorted(maps.Keys(args))
+	for _, key := range keys {
+		if subject, ok := args[key].(string); ok {
+			return subject
+		}
+	}
+	return ""
+}
+
+func cleanPath(path string) string {
+	if path == "" {
+		return ""
+	}
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return filepath.Clean(path)
+}
+
+// Suggest proposes a pattern covering similar calls: the same program and
+// subcommand for commands and the same directory for files. Other tools are
+// only suggested for the same subject, such as the same waybar option or
+// theme.
+func Suggest(tool, subject string) string {
+	switch tool {
+	case "shellExecute":
+		return suggestCommand(subject)
+	case "readFile", "writeFile", "getWallpaperPalette":
+		return filepath.Join(filepath.Dir(subject), "*")
+	}
+	return subject
+}
+
+// Match reports whether subject matches a pattern in which "*" stands for
+// any text.
+func Match(pattern, subject string) bool {
+	parts := strings.Split(pattern, "*")
+	if len(parts) == 1 {
+		return pattern == subject
+	}
+
+	if !strings.HasPrefix(subject, parts[0]) {
+		return false
+	}
+	subject = subject[len(parts[0]):]
+
+	last := parts[len(parts)-1]
+	for _, part := range parts[1 : len(parts)-1] {
+		index := strings.Index(subject, part)
+		if index < 0 {
+			return false
+		}
+		subject = subject[index+len(part):]
+	}
+
+	return strings.HasSuffix(subject, last)
+}
@@ -0,0 +1,56 @@
+package schema
+
+import (
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/saat-sy/hyprlander/pkg/docs"
+)
+
+// mouseDispatchers are only valid in bindm binds and are not in the
+// dispatcher table.
+var mouseDispatchers = []string{"movewindow", "resizewindow"}
+
+var (
+	dispatchersOnce sync.Once
+	dispatchers     []string
+)
+
+func loadDispatchers() {
+	for _, section := range docs.Sections() {
+		if section.Page != "Dispatchers" || section.Heading[len(section.Heading)-1] != "List of Dispatchers" {
+			continue
+		}
+		for _, line := range strings.Split(section.Text, "\n") {
+			cells := strings.Split(strings.Trim(strings.TrimSpace(line), "|"), "|")
+			if len(cells) < 2 {
+				continue
+			}
+			name := strings.TrimSpace(cells[0])
+			if name == "" || name == "dispatcher" || strings.HasPrefix(name, "---") {
+				continue
+			}
+			dispatchers = append(dispatchers, name)
+		}
+	}
+	slices.Sort(dispatchers)
+}
+
+// Dispatchers returns the names of the documented dispatchers.
+func Dispatchers() []string {
+	dispatchersOnce.Do(loadDispatchers)
+	return dispatchers
+}
+
+// IsDispatcher reports whether name is a known dispatcher. Mouse-only
+// dispatchers are accepted when mouse is set.
+func IsDispatcher(name string, mouse bool) bool {
+	dispatchersOnce.Do(loadDispatchers)
+	name = strings.ToLower(strings.TrimSpace(name))
+	if mouse && slices.Contains(mouseDispatchers, name) {
+		return true
+	}
+	_, found := slices.BinarySearch(dispatchers, name)
+	return found
+}
@@ -0,0 +1,266 @@
+package schema
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Program is a hypr program that reads a hyprlang config.
+type Program string
+
+const (
+	Hyprland   Program = "hyprland"
+	Hyprpaper  Program = "hyprpaper"
+	Hyprlock   Program = "hyprlock"
+	Hypridle   Program = "hypridle"
+	Hyprsunset Program = "hyprsunset"
+)
+
+// Ecosystem lists the programs besides Hyprland whose options are known.
+func Ecosystem() []Program {
+	return []Program{Hyprpaper, Hyprlock, Hypridle, Hyprsunset}
+}
+
+// ConfigFileName is the config the program reads from the Hyprland config
+// directory.
+func (p Program) ConfigFileName() string {
+	return string(p) + ".conf"
+}
+
+// ParseProgram accepts a program name or its config file name.
+func ParseProgram(name string) (Program, bool) {
+	name = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(name)), ".conf")
+	program := Program(name)
+	if program == Hyprland || slices.Contains(Ecosystem(), program) {
+		return program, true
+	}
+	return "", false
+}
+
+// ProgramOf tells which program reads the config at path by its file name.
+// Every other .conf file is taken to be part of the Hyprland config. It
+// returns "" for files that are not hyprlang configs.
+func ProgramOf(path string) Program {
+	base := filepath.Base(path)
+	if filepath.Ext(base) != ".conf" {
+		return ""
+	}
+	for _, program := range Ecosystem() {
+		if base == program.ConfigFileName() {
+			return program
+		}
+	}
+	if slices.Contains(otherConfigs, base) {
+		return ""
+	}
+	return Hyprland
+}
+
+// otherConfigs are hypr configs hyprlander has no schema for.
+var otherConfigs = []string{"xdph.conf", "hyprtoolkit.conf"}
+
+// repeatable are the categories a config may contain several blocks of,
+// such as one listener block per idle timeout. Each block has its own
+// values, so a later block does not override an earlier one.
+var repeatable = map[Program][]string{
+	Hyprpaper:  {"wallpaper"},
+	Hyprlock:   {"background", "shape", "image", "input-field", "label"},
+	Hypridle:   {"listener"},
+	Hyprsunset: {"profile"},
+}
+
+// IsRepeatable reports whether category can appear in several blocks.
+func IsRepeatable(program Program, category string) bool {
+	return slices.Contains(repeatable[program], category)
+}
+
+// ecosystemKeywords are the keywords of each program that are not options.
+var ecosystemKeywords = map[Program][]string{
+	Hyprpaper:  {"preload", "wallpaper", "unload", "source"},
+	Hyprlock:   {"animation", "bezier", "source"},
+	Hypridle:   {"source"},
+	Hyprsunset: {"source"},
+}
+
+func option(path string, optionType Type, defaultValue, description string) Option {
+	return Option{Path: path, Type: optionType, Default: defaultValue, Description: description}
+}
+
+func withChoices(o Option, values ...string) Option {
+	choices(values...)(&o)
+	return o
+}
+
+// widget returns the options every hyprlock widget has besides its own.
+func widget(name string, own ...Option) []Option {
+	options := []Option{
+		option(name+":monitor", String, "", "output to draw on, empty for all monitors"),
+		option(name+":zindex", Int, "0", "order the widgets are drawn in"),
+	}
+	if name != "background" {
+		options = append(options,
+			option(name+":position", String, "0, 0", "offset from the alignment point, in pixels or percent"),
+			withChoices(option(name+":halign", String, "center", "horizontal alignment"), "left", "center", "right", "none"),
+			withChoices(option(name+":valign", String, "center", "vertical alignment"), "top", "center", "bottom", "none"),
+			option(name+":shadow_passes", Int, "0", "passes of the shadow, 0 disables it"),
+			option(name+":shadow_size", Int, "3", "size of the shadow"),
+			option(name+":shadow_color", Color, "rgb(0, 0, 0)", "color of the shadow"),
+			option(name+":shadow_boost", Float, "1.2", "boost to the shadow's opacity"),
+		)
+	}
+	for _, o := range own {
+		o.Path = name + ":" + o.Path
+		options = append(options, o)
+	}
+	return options
+}
+
+// ecosystemOptions are the options of the hypr programs, from the
+// documentation of their current releases.
+var ecosystemOptions = map[Program][]Option{
+	Hyprpaper: {
+		option("ipc", Bool, "true", "listen for hyprctl hyprpaper commands"),
+		option("splash", Bool, "false", "draw the Hyprland splash text over the wallpaper"),
+		option("splash_offset", Float, "2.0", "how far up the splash is drawn"),
+		option("splash_color", Color, "55ffffff", "color of the splash text"),
+		option("wallpaper:monitor", String, "", "output the wallpaper is shown on, empty for all"),
+		option("wallpaper:path", String, "", "image file to show"),
+		option("wallpaper:fit_mode", String, "cover", "how the image is fitted to the output"),
+	},
+	Hypridle: {
+		option("general:lock_cmd", String, "", "command run by loginctl lock-session"),
+		option("general:unlock_cmd", String, "", "command run by loginctl unlock-session"),
+		option("general:before_sleep_cmd", String, "", "command run before the system sleeps"),
+		option("general:after_sleep_cmd", String, "", "command run after the system wakes up"),
+		option("general:ignore_dbus_inhibit", Bool, "false", "ignore idle inhibitors from D-Bus, such as media players"),
+		option("general:ignore_systemd_inhibit", Bool, "false", "ignore systemd-inhibit --what=idle inhibitors"),
+		option("general:ignore_wayland_inhibit", Bool, "false", "ignore Wayland idle inhibitors, such as the idleinhibit window rule"),
+		option("general:inhibit_sleep", Int, "2", "0 does not delay sleep, 1 waits for before_sleep_cmd, 2 waits for the lock screen if lock_cmd starts one, 3 waits until the session is locked [0 - 3]"),
+		option("listener:timeout", Int, "", "seconds of inactivity before on-timeout runs"),
+		option("listener:on-timeout", String, "", "command run when the timeout passes"),
+		option("listener:on-resume", String, "", "command run on activity after the timeout passed"),
+		option("listener:ignore_inhibit", Bool, "false", "run this listener even while idle is inhibited"),
+	},
+	Hyprsunset: {
+		option("max-gamma", Int, "100", "maximum gamma in percent that can be set"),
+		option("profile:time", String, "", "time of day the profile starts, e.g. 21:00"),
+		option("profile:temperature", Int, "6000", "color temperature in Kelvin"),
+		option("profile:gamma", Float, "1.0", "gamma as a fraction of full brightness"),
+		option("profile:identity", Bool, "false", "turn the filter off during this profile"),
+	},
+	Hyprlock: slices.Concat(
+		[]Option{
+			option("general:hide_cursor", Bool, "false", "hide the cursor while locked"),
+			option("general:ignore_empty_input", Bool, "false", "do not submit an empty password"),
+			option("general:immediate_render", Bool, "false", "draw the lock screen before screencopy and images are ready"),
+			option("general:text_trim", Bool, "true", "trim whitespace from label text"),
+			option("general:fractional_scaling", Int, "2", "0 disables, 1 enables, 2 follows the monitor [0 - 2]"),
+			option("general:screencopy_mode", Int, "0", "0 copies the screen on the GPU, 1 on the CPU [0 - 1]"),
+			option("general:fail_timeout", Int, "2000", "milliseconds the failure text and color stay up"),
+			option("auth:pam:enabled", Bool, "true", "authenticate with PAM"),
+			option("auth:pam:module", String, "hyprlock", "PAM module to use"),
+			option("auth:fingerprint:enabled", Bool, "false", "authenticate with a fingerprint reader through fprintd"),
+			option("auth:fingerprint:ready_message", String, "(Scan fingerprint to unlock)", "message shown while waiting for a finger"),
+			option("auth:fingerprint:present_message", String, "Scanning fingerprint...", "message shown while a finger is scanned"),
+			option("auth:fingerprint:retry_delay", Int, "250", "milliseconds before a failed scan is retried"),
+			option("animations:enabled", Bool, "true", "enable animations"),
+		},
+		widget("background",
+			option("path", String, "", "image to show, or screenshot to blur the current screen"),
+			option("color", Color, "rgba(17, 17, 17, 1.0)", "color shown when there is no image"),
+			option("blur_passes", Int, "0", "blur passes, 0 disables blur"),
+			option("blur_size", Int, "7", "blur size"),
+			option("noise", Float, "0.0117", "noise added to the blur"),
+			option("contrast", Float, "0.8917", "contrast of the blur"),
+			option("brightness", Float, "0.8172", "brightness of the blur"),
+			option("vibrancy", Float, "0.1686", "vibrancy of the blur"),
+			option("vibrancy_darkness", Float, "0.05", "vibrancy darkness of the blur"),
+			option("reload_time", Int, "-1", "seconds between reloads of the image, -1 never reloads"),
+			option("reload_cmd", String, "", "command whose output is the path of the next image"),
+			option("crossfade_time", Float, "-1.0", "seconds the crossfade between images takes"),
+		),
+		widget("shape",
+			option("size", String, "100, 100", "width and height"),
+			option("color", Color, "rgba(17, 17, 17, 1.0)", "fill color"),
+			option("rounding", Int, "-1", "corner radius, -1 for a circle"),
+			option("border_size", Int, "0", "border width"),
+			option("border_color", Gradient, "rgba(0, 207, 230, 1.0)", "border color or gradient"),
+			option("rotate", Float, "0", "rotation in degrees"),
+			option("xray", Bool, "false", "make the shape a hole through every other widget"),
+		),
+		widget("image",
+			option("path", String, "", "image to show"),
+			option("size", Int, "150", "size of the shorter side"),
+			option("rounding", Int, "-1", "corner radius, -1 for a circle"),
+			option("border_size", Int, "4", "border width"),
+			option("border_color", Gradient, "rgba(221, 221, 221, 1.0)", "border color or gradient"),
+			option("rotate", Float, "0", "rotation in degrees"),
+			option("reload_time", Int, "-1", "seconds between reloads of the image, -1 never reloads"),
+			option("reload_cmd", String, "", "command whose output is the path of the next image"),
+		),
+		widget("input-field",
+			option("size", String, "400, 90", "width and height"),
+			option("outline_thickness", Int, "4", "width of the outline"),
+			option("dots_size", Float, "0.25", "size of the dots relative to the field's height [0.2 - 0.8]"),
+			option("dots_spacing", Float, "0.15", "space between the dots relative to their size [-1.0 - 1.0]"),
+			option("dots_center", Bool, "true", "center the dots"),
+			option("dots_rounding", Int, "-1", "corner radius of the dots, -1 for circles"),
+			option("outer_color", Gradient, "rgba(17, 17, 17, 1.0)", "color of the outline"),
+			option("inner_color", Color, "rgba(200, 200, 200, 1.0)", "color of the field"),
+			option("font_color", Color, "rgba(10, 10, 10, 1.0)", "color of the dots and text"),
+			option("font_family", String, "Sans", "font of the text"),
+			option("fade_on_empty", Bool, "true", "fade the field out while it is empty"),
+			option("fade_timeout", Int, "2000", "milliseconds of no input before the field fades"),
+			option("placeholder_text", String, "<i>Input Password</i>", "text shown in the empty field"),
+			option("hide_input", Bool, "false", "show a color change instead of dots"),
+			option("rounding", Int, "-1", "corner radius, -1 for fully rounded"),
+			option("check_color", Gradient, "rgba(204, 136, 34, 1.0)", "outline color while the password is checked"),
+			option("fail_color", Gradient, "rgba(204, 34, 34, 1.0)", "outline color after a failed attempt"),
+			option("fail_text", String, "<i>$FAIL</i>", "text shown after a failed attempt"),
+			option("capslock_color", Gradient, "", "outline color while caps lock is on"),
+			option("numlock_color", Gradient, "", "outline color while num lock is on"),
+			option("bothlock_color", Gradient, "", "outline color while caps lock and num lock are on"),
+			option("invert_numlock", Bool, "false", "show numlock_color while num lock is off instead"),
+			option("swap_font_color", Bool, "false", "swap font and inner colors while a lock key is on"),
+		),
+		widget("label",
+			option("text", String, "Sample Text", "text to show, may use cmd[update:ms] to run a command"),
+			option("text_align", String, "", "alignment of multi-line text"),
+			option("color", Color, "rgba(254, 254, 254, 1.0)", "text color"),
+			option("font_size", Int, "16", "font size"),
+			option("font_family", String, "Sans", "font"),
+			option("rotate", Float, "0", "rotation in degrees"),
+		),
+	),
+}
+
+// ecosystemOverrides add choices and ranges, as overrides do for Hyprland.
+var ecosystemOverrides = map[Program]map[string]func(*Option){
+	Hyprpaper: {
+		"wallpaper:fit_mode": choices("cover", "contain", "tile", "fill"),
+	},
+	Hypridle: {
+		"general:inhibit_sleep": between(0, 3),
+	},
+	Hyprsunset: {
+		"max-gamma": between(0, 200),
+	},
+	Hyprlock: {
+		"general:fractional_scaling": between(0, 2),
+		"general:screencopy_mode":    between(0, 1),
+		"input-field:dots_size":      between(0.2, 0.8),
+		"input-field:dots_spacing":   between(-1, 1),
+	},
+}
+
+// ecosystemDeprecations are options the programs removed.
+var ecosystemDeprecations = map[Program]map[string]Deprecation{
+	Hyprlock: {
+		"general:grace":                {Note: "pass --grace to hyprlock instead"},
+		"general:no_fade_in":           {Note: "configure the fadeIn animation instead"},
+		"general:no_fade_out":          {Note: "configure the fadeOut animation instead"},
+		"general:disable_loading_bar":  {Note: "removed without replacement"},
+		"input-field:fade_on_empty_ms": {Replacement: "input-field:fade_timeout"},
+	},
+}
@@ -0,0 +1,78 @@
+package schema
+
+// overrides add what the docs tables only say in prose.
+var overrides = map[string]func(*Option){
+	"general:layout":                        choices("dwindle", "master"),
+	"master:new_status":                     choices("master", "slave", "inherit"),
+	"master:new_on_active":                  choices("before", "after", "none"),
+	"master:orientation":                    choices("left", "right", "top", "bottom", "center"),
+	"input:accel_profile":                   choices("", "adaptive", "flat", "custom"),
+	"input:scroll_method":                   choices("", "2fg", "edge", "on_button_down", "no_scroll"),
+	"input:touchpad:tap_button_map":         choices("", "lrm", "lmr"),
+	"input:follow_mouse":                    between(0, 3),
+	"input:float_switch_override_focus":     between(0, 2),
+	"input:off_window_axis_events":          between(0, 3),
+	"input:emulate_discrete_scroll":         between(0, 2),
+	"general:resize_corner":                 between(0, 4),
+	"group:drag_into_group":                 between(0, 2),
+	"misc:vrr":                              between(0, 2),
+	"misc:new_window_takes_over_fullscreen": between(0, 2),
+	"misc:initial_workspace_tracking":       between(0, 2),
+	"binds:workspace_center_on":             between(0, 1),
+	"binds:focus_preferred_method":          between(0, 1),
+	"opengl:force_introspection":            between(0, 2),
+	"render:explicit_sync":                  between(0, 2),
+	"render:explicit_sync_kms":              between(0, 2),
+	"debug:damage_tracking":                 between(0, 2),
+	"debug:error_position":                  between(0, 1),
+	"dwindle:force_split":                   between(0, 2),
+	"dwindle:split_bias":                    between(0, 2),
+}
+
+func choices(values ...string) func(*Option) {
+	return func(option *Option) {
+		option.Choices = values
+	}
+}
+
+func between(minValue, maxValue float64) func(*Option) {
+	return func(option *Option) {
+		option.Min, option.Max = &minValue, &maxValue
+	}
+}
+
+// deprecations lists options that were removed or renamed. Hyprland reports
+// them as config errors, so they are kept here to point at the replacement.
+// Since is only filled in where the release is known.
+var deprecations = map[string]Deprecation{
+	"decoration:drop_shadow":          {Since: "v0.45.0", Replacement: "decoration:shadow:enabled"},
+	"decoration:shadow_range":         {Since: "v0.45.0", Replacement: "decoration:shadow:range"},
+	"decoration:shadow_render_power":  {Since: "v0.45.0", Replacement: "decoration:shadow:render_power"},
+	"decoration:shadow_ignore_window": {Since: "v0.45.0", Replacement: "decoration:shadow:ignore_window"},
+	"decoration:shadow_offset":        {Since: "v0.45.0", Replacement: "decoration:shadow:offset"},
+	"decoration:shadow_scale":         {Since: "v0.45.0", Replacement: "decoration:shadow:scale"},
+	"decoration:col.shadow":           {Since: "v0.45.0", Replacement: "decoration:shadow:color"},
+	"decoration:col.shadow_inactive":  {Since: "v0.45.0", Replacement: "decoration:shadow:color_inactive"},
+	"dwindle:no_gaps_when_only": {
+		Since: "v0.45.0",
+		Note:  "use workspace rules instead, see the Smart gaps section of Workspace-Rules",
+	},
+	"master:no_gaps_when_only": {
+		Since: "v0.45.0",
+		Note:  "use workspace rules instead, see the Smart gaps section of Workspace-Rules",
+	},
+	"master:new_is_master":              {Since: "v0.41.0", Replacement: "master:new_status", Note: "set it to master"},
+	"general:cursor_inactive_timeout":   {Replacement: "cursor:inactive_timeout"},
+	"general:no_cursor_warps":           {Replacement: "cursor:no_warps"},
+	"general:sensitivity":               {Replacement: "input:sensitivity"},
+	"general:apply_sens_to_raw":         {Note: "removed without replacement"},
+	"misc:no_direct_scanout":            {Since: "v0.42.0", Replacement: "render:direct_scanout", Note: "the meaning is inverted"},
+	"misc:hide_cursor_on_touch":         {Replacement: "cursor:hide_on_touch"},
+	"misc:hide_cursor_on_key_press":     {Replacement: "cursor:hide_on_key_press"},
+	"misc:cursor_zoom_factor":           {Replacement: "cursor:zoom_factor"},
+	"misc:cursor_zoom_rigid":            {Replacement: "cursor:zoom_rigid"},
+	"decoration:multisample_edges":      {Note: "removed without replacement"},
+	"decoration:blur_new_optimizations": {Replacement: "decoration:blur:new_optimizations"},
+	"decoration:blur_size":              {Replacement: "decoration:blur:size"},
+	"decoration:blur_passes":            {Replacement: "decoration:blur:passes"},
+}
@@ -0,0 +1,386 @@
+// Package schema describes the Hyprland config options: their full path,
+// type, default, allowed range and deprecation. Options are read from the
+// tables of the embedded wiki snapshot, so the schema always matches the docs
+// the agent searches. The options of hyprpaper, hyprlock, hypridle and
+// hyprsunset are listed by hand in ecosystem.go.
+package schema
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/saat-sy/hyprlander/pkg/docs"
+)
+
+type Type string
+
+const (
+	Int      Type = "int"
+	Float    Type = "float"
+	Bool     Type = "bool"
+	Color    Type = "color"
+	Gradient Type = "gradient"
+	Vec2     Type = "vec2"
+	String   Type = "string"
+)
+
+type Option struct {
+	// Program is the program whose config the option belongs in.
+	Program Program
+	// Path is the full option name, e.g. decoration:blur:size.
+	Path        string
+	Type        Type
+	Default     string
+	Description string
+	// Min and Max bound numeric options when the docs give a range.
+	Min, Max *float64
+	// Choices lists the accepted values of enumerated options.
+	Choices []string
+	// Sides is set for options such as gaps_out that accept one to four
+	// css-style values.
+	Sides      bool
+	Deprecated *Deprecation
+}
+
+type Deprecation struct {
+	// Since is the release that removed or renamed the option.
+	Since string
+	// Replacement is the option to use instead, if there is one.
+	Replacement string
+	Note        string
+}
+
+func (o Option) Category() string {
+	if i := strings.LastIndex(o.Path, ":"); i >= 0 {
+		return o.Path[:i]
+	}
+	return ""
+}
+
+func (o Option) Range() string {
+	if o.Min == nil || o.Max == nil {
+		return ""
+	}
+	return formatNumber(*o.Min) + " - " + formatNumber(*o.Max)
+}
+
+// layoutPages are the pages whose "Config" table lists the options of a layout.
+var layoutPages = map[string]string{
+	"Dwindle-Layout": "dwindle",
+	"Master-Layout":  "master",
+}
+
+// optionSet holds the options of one program.
+type optionSet struct {
+	options map[string]Option
+	sorted  []string
+}
+
+var (
+	loadOnce sync.Once
+	sets     map[Program]*optionSet
+
+	rangePattern  = regexp.MustCompile(`\[(-?\d+(?:\.\d+)?) - (-?\d+(?:\.\d+)?)\]`)
+	choicePattern = regexp.MustCompile(`\[(-?\d+(?:/-?\d+)+)\]`)
+)
+
+func load() {
+	sets = map[Program]*optionSet{Hyprland: newOptionSet(hyprlandOptions(), overrides, deprecations)}
+	for _, program := range Ecosystem() {
+		sets[program] = newOptionSet(ecosystemOptions[program], ecosystemOverrides[program], ecosystemDeprecations[program])
+	}
+	for program, set := range sets {
+		for path, option := range set.options {
+			option.Program = program
+			set.options[path] = option
+		}
+	}
+}
+
+// hyprlandOptions reads the options from the wiki's tables.
+func hyprlandOptions() []Option {
+	var options []Option
+
+	for _, section := range docs.Sections() {
+		var category string
+		switch {
+		case section.Page == "Variables" && len(section.Heading) > 1:
+			category = strings.Join(section.Heading[1:], ":")
+		case layoutPages[section.Page] != "" && len(section.Heading) > 1 && section.Heading[1] == "Config":
+			category = layoutPages[section.Page]
+		default:
+			continue
+		}
+
+		for _, row := range optionRows(section.Text) {
+			option := Option{
+				Path:        category + ":" + row[0],
+				Description: row[1],
+				Type:        parseType(row[2]),
+				Default:     parseDefault(row[3]),
+			}
+			if match := rangePattern.FindStringSubmatch(option.Description); match != nil {
+				minValue, _ := strconv.ParseFloat(match[1], 64)
+				maxValue, _ := strconv.ParseFloat(match[2], 64)
+				option.Min, option.Max = &minValue, &maxValue
+			}
+			if match := choicePattern.FindStringSubmatch(option.Description); match != nil {
+				option.Choices = strings.Split(match[1], "/")
+			}
+			option.Sides = strings.Contains(option.Description, "css style")
+			options = append(options, option)
+		}
+	}
+	return options
+}
+
+func newOptionSet(list []Option, overrides map[string]func(*Option), deprecations map[string]Deprecation) *optionSet {
+	options := make(map[string]Option, len(list))
+	for _, option := range list {
+		options[option.Path] = option
+	}
+
+	for path, override := range overrides {
+		if option, ok := options[path]; ok {
+			override(&option)
+			options[path] = option
+		}
+	}
+
+	for path, deprecation := range deprecations {
+		option := Option{Path: path, Type: String, Deprecated: &deprecation}
+		if replacement, ok := options[deprecation.Replacement]; ok {
+			option.Type = replacement.Type
+			option.Description = replacement.Description
+		}
+		options[path] = option
+	}
+
+	sorted := make([]string, 0, len(options))
+	for path := range options {
+		sorted = append(sorted, path)
+	}
+	sort.Strings(sorted)
+	return &optionSet{options: options, sorted: sorted}
+}
+
+func set(program Program) *optionSet {
+	loadOnce.Do(load)
+	if s, ok := sets[program]; ok {
+		return s
+	}
+	return &optionSet{}
+}
+
+// optionRows returns the name, description, type and default columns of the
+// option tables in a section.
+func optionRows(text string) [][4]string {
+	var rows [][4]string
+	inTable := false
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if !strings.HasPrefix(line, "|") {
+			inTable = false
+			continue
+		}
+		cells := strings.Split(strings.Trim(line, "|"), "|")
+		for i := range cells {
+			cells[i] = strings.TrimSpace(cells[i])
+		}
+		if len(cells) == 4 && cells[0] == "name" && cells[2] == "type" {
+			inTable = true
+			continue
+		}
+		if !inTable || len(cells) != 4 || strings.HasPrefix(cells[0], "---") {
+			continue
+		}
+		rows = append(rows, [4]string{cells[0], cells[1], cells[2], cells[3]})
+	}
+	return rows
+}
+
+func parseType(name string) Type {
+	switch name {
+	case "int", "float", "bool", "color", "gradient", "vec2":
+		return Type(name)
+	default:
+		return String
+	}
+}
+
+func parseDefault(value string) string {
+	switch value {
+	case "[[Empty]]", "[[Auto]]", "unset":
+		return ""
+	}
+	return value
+}
+
+func Lookup(path string) (Option, bool) {
+	return LookupIn(Hyprland, path)
+}
+
+// LookupIn looks an option up in the schema of program.
+func LookupIn(program Program, path string) (Option, bool) {
+	option, ok := set(program).options[strings.TrimSpace(path)]
+	return option, ok
+}
+
+// Options returns every known option sorted by path.
+func Options() []Option {
+	return OptionsIn(Hyprland)
+}
+
+// OptionsIn returns every known option of program sorted by path.
+func OptionsIn(program Program) []Option {
+	s := set(program)
+	result := make([]Option, len(s.sorted))
+	for i, path := range s.sorted {
+		result[i] = s.options[path]
+	}
+	return result
+}
+
+// Suggest returns up to limit known options whose path is closest to path,
+// for "did you mean" hints.
+func Suggest(path string, limit int) []string {
+	return SuggestIn(Hyprland, path, limit)
+}
+
+// SuggestIn is Suggest for the options of program.
+func SuggestIn(program Program, path string, limit int) []string {
+	s := set(program)
+
+	type candidate struct {
+		path     string
+		distance int
+	}
+	name := path[strings.LastIndex(path, ":")+1:]
+	var candidates []candidate
+	for _, known := range s.sorted {
+		if s.options[known].Deprecated != nil {
+			continue
+		}
+		knownName := known[strings.LastIndex(known, ":")+1:]
+		distance := min(levenshtein(path, known), levenshtein(name, knownName)+1)
+		if strings.Contains(known, name) {
+			distance = min(distance, 1)
+		}
+		if distance <= max(2, len(name)/3) {
+			candidates = append(candidates, candidate{known, distance})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].distance < candidates[j].distance })
+
+	var result []string
+	for _, c := range candidates {
+		if len(result) == limit {
+			break
+		}
+		result = append(result, c.path)
+	}
+	return result
+}
+
+func levenshtein(a, b string) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(b)]
+}
+
+func formatNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// Find looks an option up by its full path, or by its last component when
+// that is unambiguous enough to be useful (e.g. "border_size").
+func Find(name string) []Option {
+	return FindIn(Hyprland, name)
+}
+
+// FindIn is Find for the options of program.
+func FindIn(program Program, name string) []Option {
+	s := set(program)
+
+	name = strings.TrimSpace(name)
+	if option, ok := s.options[name]; ok {
+		return []Option{option}
+	}
+
+	var found []Option
+	for _, path := range s.sorted {
+		if strings.HasSuffix(path, ":"+name) {
+			found = append(found, s.options[path])
+		}
+	}
+	return found
+}
+
+// ProgramsFor lists the programs whose schema knows an option name,
+// Hyprland first.
+func ProgramsFor(name string) []Program {
+	var found []Program
+	for _, program := range append([]Program{Hyprland}, Ecosystem()...) {
+		if len(FindIn(program, name)) > 0 {
+			found = append(found, program)
+		}
+	}
+	return found
+}
+
+// Describe formats everything known about an option for people and the model.
+func Describe(option Option) string {
+	var builder strings.Builder
+	builder.WriteString(option.Path)
+	if option.Program != "" && option.Program != Hyprland {
+		builder.WriteString(" (" + option.Program.ConfigFileName() + ")")
+	}
+	builder.WriteString("\n")
+	if option.Description != "" {
+		builder.WriteString("  " + option.Description + "\n")
+	}
+	builder.WriteString("  type: " + string(option.Type) + "\n")
+	if option.Deprecated == nil {
+		defaultValue := option.Default
+		if defaultValue == "" {
+			defaultValue = "(empty)"
+		}
+		builder.WriteString("  default: " + defaultValue + "\n")
+	}
+	if r := option.Range(); r != "" {
+		builder.WriteString("  range: " + r + "\n")
+	}
+	if len(option.Choices) > 0 {
+		builder.WriteString("  choices: " + formatChoices(option.Choices) + "\n")
+	}
+	if option.Sides {
+		builder.WriteString("  accepts: one to four values (top, right, bottom, left)\n")
+	}
+	if option.Deprecated != nil {
+		builder.WriteString("  " + DeprecationMessage(option) + "\n")
+	}
+	return strings.TrimRight(builder.String(), "\n")
+}
+
+// IsHyprlandConfig reports whether path is a Hyprland config file rather than
+// the config of another hypr tool such as hyprpaper or hyprlock.
+func IsHyprlandConfig(path string) bool {
+	return ProgramOf(path) == Hyprland
+}
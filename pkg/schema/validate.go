@@ -0,0 +1,266 @@
+package schema
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/saat-sy/hyprlander/pkg/color"
+	"github.com/saat-sy/hyprlander/pkg/hyprlang"
+)
+
+type Severity string
+
+const (
+	SeverityError   Severity = "error"
+	SeverityWarning Severity = "warning"
+)
+
+// Problem is an option that its program would reject or that looks like a
+// mistake.
+type Problem struct {
+	Line     int
+	Option   string
+	Severity Severity
+	Message  string
+}
+
+func (p Problem) String() string {
+	return fmt.Sprintf("line %d: %s: %s", p.Line, p.Option, p.Message)
+}
+
+// ValidateValue checks value against the option's type, range and choices.
+func ValidateValue(option Option, value string) error {
+	value = strings.TrimSpace(value)
+
+	if len(option.Choices) > 0 && option.Type == String {
+		if !slices.Contains(option.Choices, value) {
+			return fmt.Errorf("must be one of %s", formatChoices(option.Choices))
+		}
+		return nil
+	}
+
+	switch option.Type {
+	case Int:
+		if option.Sides {
+			return validateSides(option, value)
+		}
+		number, err := parseInt(value)
+		if err != nil {
+			return fmt.Errorf("expected an integer, got %q", value)
+		}
+		return checkNumber(option, float64(number), value)
+	case Float:
+		number, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return fmt.Errorf("expected a number, got %q", value)
+		}
+		return checkNumber(option, number, value)
+	case Bool:
+		if _, ok := ParseBool(value); !ok {
+			return fmt.Errorf("expected true or false, got %q", value)
+		}
+	case Color:
+		if !IsColor(value) {
+			return fmt.Errorf("expected a color such as rgba(33ccffee) or rgb(33ccff), got %q", value)
+		}
+	case Gradient:
+		return validateGradient(value)
+	case Vec2:
+		fields := strings.Fields(strings.NewReplacer(",", " ", "[", " ", "]", " ").Replace(value))
+		if len(fields) != 2 {
+			return fmt.Errorf("expected two numbers separated by a space, got %q", value)
+		}
+		for _, field := range fields {
+			if _, err := strconv.ParseFloat(field, 64); err != nil {
+				return fmt.Errorf("expected two numbers separated by a space, got %q", value)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Validate checks a single option assignment, such as one the agent is about
+// to set.
+func Validate(path, value string) *Problem {
+	return ValidateIn(Hyprland, path, value)
+}
+
+// ValidateIn checks an assignment against the schema of program.
+func ValidateIn(program Program, path, value string) *Problem {
+	option, ok := LookupIn(program, path)
+	if !ok {
+		problem := Problem{Option: path, Severity: SeverityWarning, Message: "unknown option"}
+		if program != Hyprland {
+			problem.Message = "unknown " + string(program) + " option"
+		}
+		if suggestions := SuggestIn(program, path, 3); len(suggestions) > 0 {
+			problem.Message += ", did you mean " + strings.Join(suggestions, " or ") + "?"
+		}
+		return &problem
+	}
+
+	if option.Deprecated != nil {
+		return &Problem{Option: path, Severity: SeverityError, Message: DeprecationMessage(option)}
+	}
+
+	if err := ValidateValue(option, value); err != nil {
+		return &Problem{Option: path, Severity: SeverityError, Message: err.Error()}
+	}
+
+	return nil
+}
+
+// ValidateDocument checks every option assignment in a config against the
+// schema of the program that reads it, told by the document's file name.
+// Values are expanded with vars first; values that still reference unknown
+// variables are skipped because they may be defined in another file.
+func ValidateDocument(doc *hyprlang.Document, vars map[string]string) []Problem {
+	program := ProgramOf(doc.Path)
+	if program == "" {
+		program = Hyprland
+	}
+
+	merged := make(map[string]string, len(vars))
+	for name, value := range vars {
+		merged[name] = value
+	}
+	for name, value := range doc.Variables() {
+		merged[name] = value
+	}
+
+	var problems []Problem
+	for _, line := range doc.Lines {
+		if line.Kind != hyprlang.Assignment || IsKeywordIn(program, line) {
+			continue
+		}
+
+		value := hyprlang.ExpandValue(line.Value, merged)
+		if strings.Contains(value, "$") {
+			continue
+		}
+
+		if problem := ValidateIn(program, line.FullKey(), value); problem != nil {
+			problem.Line = line.Number
+			problems = append(problems, *problem)
+		}
+	}
+
+	return problems
+}
+
+// HasErrors reports whether any problem is an error rather than a warning.
+func HasErrors(problems []Problem) bool {
+	return slices.ContainsFunc(problems, func(p Problem) bool { return p.Severity == SeverityError })
+}
+
+var keywords = []string{
+	"exec", "exec-once", "execr", "execr-once", "exec-shutdown",
+	"env", "envd", "monitor", "workspace", "windowrule", "windowrulev2",
+	"layerrule", "unbind", "submap", "plugin", "source", "blurls",
+	"animation", "bezier",
+}
+
+// IsKeyword reports whether a line is a keyword such as bind or exec-once,
+// or an option of a per-device or plugin section, rather than a variable
+// from the schema.
+func IsKeyword(line hyprlang.Line) bool {
+	return IsKeywordIn(Hyprland, line)
+}
+
+// IsKeywordIn is IsKeyword for the config of program. Keywords of the other
+// programs, such as hyprpaper's preload, only count outside any category.
+func IsKeywordIn(program Program, line hyprlang.Line) bool {
+	if program != Hyprland {
+		return len(line.Category) == 0 && slices.Contains(ecosystemKeywords[program], line.Key)
+	}
+	if len(line.Category) > 0 && (line.Category[0] == "device" || line.Category[0] == "plugin") {
+		return true
+	}
+	if slices.Contains(keywords, line.Key) {
+		return true
+	}
+	return strings.HasPrefix(line.Key, "bind")
+}
+
+func DeprecationMessage(option Option) string {
+	deprecation := option.Deprecated
+	message := "deprecated"
+	if deprecation.Since != "" {
+		message = "removed in " + deprecation.Since
+	}
+	if deprecation.Replacement != "" {
+		message += ", use " + deprecation.Replacement + " instead"
+	}
+	if deprecation.Note != "" {
+		message += " (" + deprecation.Note + ")"
+	}
+	return message
+}
+
+// ParseBool accepts every spelling Hyprland does.
+func ParseBool(value string) (bool, bool) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "true", "yes", "on", "1":
+		return true, true
+	case "false", "no", "off", "0":
+		return false, true
+	}
+	return false, false
+}
+
+// IsColor reports whether value is a single color in one of the formats
+// Hyprland accepts.
+func IsColor(value string) bool {
+	_, err := color.Parse(value)
+	return err == nil
+}
+
+func validateGradient(value string) error {
+	_, err := color.ParseGradient(value)
+	return err
+}
+
+func validateSides(option Option, value string) error {
+	fields := strings.Fields(strings.ReplaceAll(value, ",", " "))
+	if len(fields) == 0 || len(fields) > 4 {
+		return fmt.Errorf("expected one to four integers (top, right, bottom, left), got %q", value)
+	}
+	for _, field := range fields {
+		number, err := parseInt(field)
+		if err != nil {
+			return fmt.Errorf("expected one to four integers (top, right, bottom, left), got %q", value)
+		}
+		if err := checkNumber(option, float64(number), field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parseInt(value string) (int64, error) {
+	return strconv.ParseInt(value, 0, 64)
+}
+
+func checkNumber(option Option, number float64, value string) error {
+	if option.Min != nil && option.Max != nil && (number < *option.Min || number > *option.Max) {
+		return fmt.Errorf("%s is out of range [%s]", value, option.Range())
+	}
+	if len(option.Choices) > 0 && !slices.Contains(option.Choices, value) {
+		return fmt.Errorf("must be one of %s", formatChoices(option.Choices))
+	}
+	return nil
+}
+
+func formatChoices(choices []string) string {
+	quoted := make([]string, len(choices))
+	for i, choice := range choices {
+		if choice == "" {
+			choice = `""`
+		}
+		quoted[i] = choice
+	}
+	return strings.Join(quoted, ", ")
+}
@@ -0,0 +1,177 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/saat-sy/hyprlander/pkg/config"
+)
+
+// keySize is the length of the AES-256 key.
+const keySize = 32
+
+// EncryptedFileStore is the fallback when no Secret Service is running. Values
+// are sealed with AES-GCM under a random key kept in a file only the user can
+// read, so other local users cannot decrypt them. The key lives next to the
+// secrets: anyone who can read the user's files, including processes running
+// as the same user, can decrypt them, and copying the directory copies both.
+type EncryptedFileStore struct {
+	dir string
+}
+
+func NewEncryptedFileStore() *EncryptedFileStore {
+	dir, _ := config.GetUserHomeDirectory()
+	return &EncryptedFileStore{dir: dir}
+}
+
+func (s *EncryptedFileStore) Name() string {
+	return "encrypted file"
+}
+
+func (s *EncryptedFileStore) Get(key string) (string, error) {
+	content, err := os.ReadFile(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to read encrypted secret: %w", err)
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(content)))
+	if err != nil {
+		return "", fmt.Errorf("encrypted secret is corrupt: %w", err)
+	}
+
+	aead, err := s.cipher(false)
+	if err != nil {
+		return "", err
+	}
+	if len(sealed) < aead.NonceSize() {
+		return "", fmt.Errorf("encrypted secret is corrupt")
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, []byte(key))
+	if err != nil {
+		return "", fmt.Errorf("could not decrypt secret, was %s replaced? %w", s.keyPath(), err)
+	}
+
+	return string(plaintext), nil
+}
+
+func (s *EncryptedFileStore) Set(key, value string) error {
+	if s.dir == "" {
+		return fmt.Errorf("could not determine hyprlander directory")
+	}
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return fmt.Errorf("failed to create directory %s: %w", s.dir, err)
+	}
+
+	aead, err := s.cipher(true)
+	if err != nil {
+		return err
+	}
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(value), []byte(key))
+	encoded := base64.StdEncoding.EncodeToString(sealed) + "\n"
+
+	if err := os.WriteFile(s.path(key), []byte(encoded), 0600); err != nil {
+		return fmt.Errorf("failed to write encrypted secret: %w", err)
+	}
+
+	return nil
+}
+
+func (s *EncryptedFileStore) Delete(key string) error {
+	err := os.Remove(s.path(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (s *EncryptedFileStore) path(key string) string {
+	return filepath.Join(s.dir, strings.ToLower(key)+".enc")
+}
+
+// keyPath is the file holding the encryption key.
+func (s *EncryptedFileStore) keyPath() string {
+	return filepath.Join(s.dir, "encryption.key")
+}
+
+// cipher returns the AEAD for the store's key, creating the key when create
+// is set and there is none yet.
+func (s *EncryptedFileStore) cipher(create bool) (cipher.AEAD, error) {
+	key, err := s.readKey()
+	switch {
+	case errors.Is(err, os.ErrNotExist) && create:
+		key, err = s.createKey()
+	case errors.Is(err, os.ErrNotExist):
+		return nil, fmt.Errorf("encryption key %s is missing", s.keyPath())
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+func (s *EncryptedFileStore) readKey() ([]byte, error) {
+	info, err := os.Stat(s.keyPath())
+	if err != nil {
+		return nil, err
+	}
+	if info.Mode().Perm()&0077 != 0 {
+		return nil, fmt.Errorf("%s can be read by other users, run chmod 600 on it", s.keyPath())
+	}
+
+	content, err := os.ReadFile(s.keyPath())
+	if err != nil {
+		return nil, fmt.Errorf("failed to read encryption key: %w", err)
+	}
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(content)))
+	if err != nil || len(key) != keySize {
+		return nil, fmt.Errorf("encryption key %s is corrupt", s.keyPath())
+	}
+	return key, nil
+}
+
+// createKey writes a new random key. If another process created one first,
+// that key is used instead.
+func (s *EncryptedFileStore) createKey() ([]byte, error) {
+	key := make([]byte, keySize)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate encryption key: %w", err)
+	}
+
+	file, err := os.OpenFile(s.keyPath(), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if errors.Is(err, os.ErrExist) {
+		return s.readKey()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to create encryption key: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(base64.StdEncoding.EncodeToString(key) + "\n"); err != nil {
+		return nil, fmt.Errorf("failed to write encryption key: %w", err)
+	}
+	return key, nil
+}
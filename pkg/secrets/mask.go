@@ -0,0 +1,39 @@
+package secrets
+
+import (
+	"strings"
+	"sync"
+)
+
+var (
+	registered   []string
+	registeredMu sync.Mutex
+)
+
+// Register marks a value as secret so Redact hides it from any output.
+func Register(value string) {
+	if len(value) < 4 {
+		return
+	}
+	registeredMu.Lock()
+	defer registeredMu.Unlock()
+	registered = append(registered, value)
+}
+
+// Mask shows only the first and last few characters of a secret.
+func Mask(value string) string {
+	if len(value) <= 8 {
+		return strings.Repeat("*", len(value))
+	}
+	return value[:4] + strings.Repeat("*", 8) + value[len(value)-4:]
+}
+
+// Redact masks every registered secret that appears in text.
+func Redact(text string) string {
+	registeredMu.Lock()
+	defer registeredMu.Unlock()
+	for _, value := range registered {
+		text = strings.ReplaceAll(text, value, Mask(value))
+	}
+	return text
+}
@@ -0,0 +1,136 @@
+package secrets
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/saat-sy/hyprlander/pkg/config"
+	"github.com/zalando/go-keyring"
+)
+
+var ErrNotFound = errors.New("secret not found")
+
+type Store interface {
+	Name() string
+	Get(key string) (string, error)
+	Set(key, value string) error
+	Delete(key string) error
+}
+
+// KeyringStore keeps secrets in the freedesktop Secret Service (GNOME Keyring,
+// KWallet, KeePassXC, ...) over D-Bus.
+type KeyringStore struct{}
+
+func (KeyringStore) Name() string {
+	return "keyring"
+}
+
+func (KeyringStore) Get(key string) (string, error) {
+	value, err := keyring.Get(config.KeyringService, key)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return "", ErrNotFound
+	}
+	return value, err
+}
+
+func (KeyringStore) Set(key, value string) error {
+	return keyring.Set(config.KeyringService, key, value)
+}
+
+func (KeyringStore) Delete(key string) error {
+	err := keyring.Delete(config.KeyringService, key)
+	if errors.Is(err, keyring.ErrNotFound) {
+		return nil
+	}
+	return err
+}
+
+// Stores returns the persistent backends in order of preference.
+func Stores() []Store {
+	return []Store{KeyringStore{}, NewEncryptedFileStore()}
+}
+
+// LoadAPIKey looks the API key up in the environment first, then in each
+// persistent store. It returns the key and where it was found.
+func LoadAPIKey(name string) (string, string, error) {
+	for _, env := range []string{config.EnvAPIKey, config.EnvGeminiAPIKey} {
+		if value := os.Getenv(env); value != "" {
+			Register(value)
+			return value, env, nil
+		}
+	}
+
+	for _, store := range Stores() {
+		value, err := store.Get(name)
+		if err != nil || value == "" {
+			continue
+		}
+		Register(value)
+		return value, store.Name(), nil
+	}
+
+	return "", "", fmt.Errorf("no API key found; run 'hyprlander init' or set %s", config.EnvGeminiAPIKey)
+}
+
+// SaveAPIKey stores the key in the first backend that accepts it and returns
+// that backend's name.
+func SaveAPIKey(name, value string) (string, error) {
+	backend, err := save(name, value)
+	if err != nil {
+		return "", fmt.Errorf("could not store API key: %w", err)
+	}
+	return backend, nil
+}
+
+// LoadKey returns the random key stored under name, or ErrNotFound.
+func LoadKey(name string) ([]byte, error) {
+	for _, store := range Stores() {
+		value, err := store.Get(name)
+		if err != nil || value == "" {
+			continue
+		}
+		key, err := hex.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("%s key in the %s is corrupt: %w", name, store.Name(), err)
+		}
+		return key, nil
+	}
+	return nil, ErrNotFound
+}
+
+// LoadOrCreateKey returns the random key stored under name, generating and
+// storing a 256-bit key the first time.
+func LoadOrCreateKey(name string) ([]byte, error) {
+	key, err := LoadKey(name)
+	if !errors.Is(err, ErrNotFound) {
+		return key, err
+	}
+
+	key = make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		return nil, fmt.Errorf("failed to generate %s key: %w", name, err)
+	}
+	if _, err := save(name, hex.EncodeToString(key)); err != nil {
+		return nil, fmt.Errorf("could not store %s key: %w", name, err)
+	}
+	return key, nil
+}
+
+// save stores value in the first backend that accepts it.
+func save(name, value string) (string, error) {
+	Register(value)
+
+	var errs []error
+	for _, store := range Stores() {
+		if err := store.Set(name, value); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", store.Name(), err))
+			continue
+		}
+		return store.Name(), nil
+	}
+
+	return "", errors.Join(errs...)
+}
@@ -0,0 +1,268 @@
+package secrets
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/saat-sy/hyprlander/pkg/config"
+)
+
+func TestKeyringStore(t *testing.T) {
+	service := useFakeKeyring(t)
+	store := KeyringStore{}
+
+	if _, err := store.Get("gemini"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get of a missing key: got %v, want ErrNotFound", err)
+	}
+
+	if err := store.Set("gemini", "first-key"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+	if err := store.Set("gemini", "second-key"); err != nil {
+		t.Fatalf("Set again: %v", err)
+	}
+	if stored := service.stored(); len(stored) != 1 {
+		t.Fatalf("Set should replace the item, the service holds %v", stored)
+	}
+
+	value, err := store.Get("gemini")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if value != "second-key" {
+		t.Errorf("Get = %q, want %q", value, "second-key")
+	}
+
+	if err := store.Delete("gemini"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Get("gemini"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get after Delete: got %v, want ErrNotFound", err)
+	}
+	if err := store.Delete("gemini"); err != nil {
+		t.Errorf("Delete of a missing key: %v", err)
+	}
+}
+
+func TestEncryptedFileStore(t *testing.T) {
+	store := &EncryptedFileStore{dir: t.TempDir()}
+
+	if _, err := store.Get("gemini"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get of a missing key: got %v, want ErrNotFound", err)
+	}
+
+	if err := store.Set("gemini", "secret-api-key"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	content, err := os.ReadFile(store.path("gemini"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(content), "secret-api-key") {
+		t.Errorf("the file holds the key in clear text: %q", content)
+	}
+	for _, path := range []string{store.path("gemini"), store.keyPath()} {
+		info, err := os.Stat(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Mode().Perm() != 0600 {
+			t.Errorf("%s has mode %v, want 0600", path, info.Mode().Perm())
+		}
+	}
+
+	value, err := store.Get("gemini")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if value != "secret-api-key" {
+		t.Errorf("Get = %q, want %q", value, "secret-api-key")
+	}
+
+	// The key is bound to its name, so a file renamed to another key does
+	// not decrypt.
+	if err := os.Rename(store.path("gemini"), store.path("other")); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get("other"); err == nil {
+		t.Error("a secret moved to another key was decrypted")
+	}
+
+	if err := store.Delete("other"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if err := store.Delete("other"); err != nil {
+		t.Errorf("Delete of a missing key: %v", err)
+	}
+}
+
+func TestEncryptedFileStoreKey(t *testing.T) {
+	store := &EncryptedFileStore{dir: t.TempDir()}
+	if err := store.Set("gemini", "secret-api-key"); err != nil {
+		t.Fatal(err)
+	}
+
+	// A second store in the same directory shares the key.
+	other := &EncryptedFileStore{dir: store.dir}
+	if value, err := other.Get("gemini"); err != nil || value != "secret-api-key" {
+		t.Errorf("Get from another store = %q, %v", value, err)
+	}
+
+	if err := os.Chmod(store.keyPath(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get("gemini"); err == nil {
+		t.Error("a key readable by other users was used")
+	}
+
+	if err := os.Remove(store.keyPath()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get("gemini"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("Get without the key: got %v, want an error about the missing key", err)
+	}
+
+	// A new key does not open secrets sealed with the old one.
+	if err := store.Set("other", "value"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get("gemini"); err == nil {
+		t.Error("a secret was decrypted with a different key")
+	}
+}
+
+func TestEncryptedFileStoreCorrupt(t *testing.T) {
+	store := &EncryptedFileStore{dir: t.TempDir()}
+	if err := os.WriteFile(store.path("gemini"), []byte("not base64!\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := store.Get("gemini"); err == nil || errors.Is(err, ErrNotFound) {
+		t.Errorf("Get of a corrupt file: got %v, want a decoding error", err)
+	}
+}
+
+// withoutEnvKeys clears the API key variables and points HOME at a temporary
+// directory for the encrypted file store.
+func withoutEnvKeys(t *testing.T) string {
+	t.Helper()
+	t.Setenv(config.EnvAPIKey, "")
+	t.Setenv(config.EnvGeminiAPIKey, "")
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	return home
+}
+
+func TestLoadAPIKeyEnvironmentOverride(t *testing.T) {
+	useFakeKeyring(t)
+	withoutEnvKeys(t)
+	if err := (KeyringStore{}).Set("gemini", "keyring-key"); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv(config.EnvGeminiAPIKey, "gemini-env-key")
+	value, source, err := LoadAPIKey("gemini")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != "gemini-env-key" || source != config.EnvGeminiAPIKey {
+		t.Errorf("LoadAPIKey = %q from %s, want the %s value", value, source, config.EnvGeminiAPIKey)
+	}
+
+	// HYPRLANDER_API_KEY wins over GEMINI_API_KEY.
+	t.Setenv(config.EnvAPIKey, "hyprlander-env-key")
+	value, source, err = LoadAPIKey("gemini")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != "hyprlander-env-key" || source != config.EnvAPIKey {
+		t.Errorf("LoadAPIKey = %q from %s, want the %s value", value, source, config.EnvAPIKey)
+	}
+}
+
+func TestSaveAndLoadAPIKeyKeyring(t *testing.T) {
+	useFakeKeyring(t)
+	home := withoutEnvKeys(t)
+
+	backend, err := SaveAPIKey("gemini", "keyring-api-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if backend != "keyring" {
+		t.Errorf("SaveAPIKey used %s, want keyring", backend)
+	}
+	if _, err := os.Stat(filepath.Join(home, config.AppName)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("nothing should be written to disk when the keyring works: %v", err)
+	}
+
+	value, source, err := LoadAPIKey("gemini")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != "keyring-api-key" || source != "keyring" {
+		t.Errorf("LoadAPIKey = %q from %s, want the keyring value", value, source)
+	}
+}
+
+func TestSaveAPIKeyFallsBackToEncryptedFile(t *testing.T) {
+	service := useFakeKeyring(t)
+	home := withoutEnvKeys(t)
+	service.locked = true
+
+	backend, err := SaveAPIKey("gemini", "fallback-api-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if backend != "encrypted file" {
+		t.Errorf("SaveAPIKey used %s, want encrypted file", backend)
+	}
+	if _, err := os.Stat(filepath.Join(home, config.AppName, "gemini.enc")); err != nil {
+		t.Errorf("encrypted file was not written: %v", err)
+	}
+
+	value, source, err := LoadAPIKey("gemini")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if value != "fallback-api-key" || source != "encrypted file" {
+		t.Errorf("LoadAPIKey = %q from %s, want the encrypted file value", value, source)
+	}
+}
+
+func TestLoadAPIKeyMissing(t *testing.T) {
+	useFakeKeyring(t)
+	withoutEnvKeys(t)
+	if _, _, err := LoadAPIKey("gemini"); err == nil {
+		t.Error("LoadAPIKey found a key in empty stores")
+	}
+}
+
+func TestMask(t *testing.T) {
+	tests := []struct {
+		value, want string
+	}{
+		{"", ""},
+		{"abc", "***"},
+		{"12345678", "********"},
+		{"AIzaSyExampleKey1234", "AIza********1234"},
+	}
+	for _, test := range tests {
+		if got := Mask(test.value); got != test.want {
+			t.Errorf("Mask(%q) = %q, want %q", test.value, got, test.want)
+		}
+	}
+}
+
+func TestRedact(t *testing.T) {
+	Register("AIzaSyRedactMe5678")
+	Register("abc")
+
+	got := Redact("key=AIzaSyRedactMe5678 short=abc")
+	want := "key=AIza********5678 short=abc"
+	if got != want {
+		t.Errorf("Redact = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,263 @@
+package secrets
+
+import (
+	"bufio"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/godbus/dbus/v5"
+	"github.com/godbus/dbus/v5/prop"
+)
+
+// fakeSecretService implements the parts of the freedesktop Secret Service
+// that go-keyring uses, on a private session bus, so KeyringStore is tested
+// against the real D-Bus protocol without touching the user's keyring.
+type fakeSecretService struct {
+	conn *dbus.Conn
+
+	mu    sync.Mutex
+	items map[dbus.ObjectPath]*fakeItem
+	next  int
+	// locked makes Unlock fail, like a keyring the user refused to unlock.
+	locked bool
+}
+
+const (
+	fakeServicePath    = dbus.ObjectPath("/org/freedesktop/secrets")
+	fakeCollectionPath = dbus.ObjectPath("/org/freedesktop/secrets/collection/login")
+	fakeSessionPath    = dbus.ObjectPath("/org/freedesktop/secrets/session/1")
+)
+
+type fakeSecret struct {
+	Session     dbus.ObjectPath
+	Parameters  []byte
+	Value       []byte
+	ContentType string
+}
+
+type fakeItem struct {
+	service    *fakeSecretService
+	path       dbus.ObjectPath
+	attributes map[string]string
+	secret     []byte
+}
+
+// startSessionBus runs a private dbus-daemon and points
+// DBUS_SESSION_BUS_ADDRESS at it. The returned function stops it.
+func startSessionBus() (func(), error) {
+	daemon, err := exec.LookPath("dbus-daemon")
+	if err != nil {
+		return nil, err
+	}
+	cmd := exec.Command(daemon, "--session", "--nofork", "--print-address")
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+	address, err := bufio.NewReader(stdout).ReadString('\n')
+	if err != nil {
+		cmd.Process.Kill()
+		return nil, fmt.Errorf("dbus-daemon did not print its address: %w", err)
+	}
+	os.Setenv("DBUS_SESSION_BUS_ADDRESS", strings.TrimSpace(address))
+	return func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	}, nil
+}
+
+func newFakeSecretService() (*fakeSecretService, error) {
+	conn, err := dbus.ConnectSessionBus()
+	if err != nil {
+		return nil, err
+	}
+	s := &fakeSecretService{conn: conn, items: make(map[dbus.ObjectPath]*fakeItem)}
+
+	exports := []struct {
+		object any
+		path   dbus.ObjectPath
+		iface  string
+	}{
+		{s, fakeServicePath, "org.freedesktop.Secret.Service"},
+		{fakeCollection{s}, fakeCollectionPath, "org.freedesktop.Secret.Collection"},
+		{fakeSession{}, fakeSessionPath, "org.freedesktop.Secret.Session"},
+	}
+	for _, export := range exports {
+		if err := conn.Export(export.object, export.path, export.iface); err != nil {
+			return nil, err
+		}
+	}
+	_, err = prop.Export(conn, fakeServicePath, prop.Map{
+		"org.freedesktop.Secret.Service": {
+			"Collections": {Value: []dbus.ObjectPath{fakeCollectionPath}},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := conn.RequestName("org.freedesktop.secrets", dbus.NameFlagDoNotQueue)
+	if err != nil {
+		return nil, err
+	}
+	if reply != dbus.RequestNameReplyPrimaryOwner {
+		return nil, fmt.Errorf("org.freedesktop.secrets is already owned")
+	}
+	return s, nil
+}
+
+func (s *fakeSecretService) OpenSession(algorithm string, input dbus.Variant) (dbus.Variant, dbus.ObjectPath, *dbus.Error) {
+	if algorithm != "plain" {
+		return dbus.MakeVariant(""), "/", dbus.MakeFailedError(fmt.Errorf("unsupported algorithm %s", algorithm))
+	}
+	return dbus.MakeVariant(""), fakeSessionPath, nil
+}
+
+func (s *fakeSecretService) Unlock(objects []dbus.ObjectPath) ([]dbus.ObjectPath, dbus.ObjectPath, *dbus.Error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.locked {
+		return nil, "/", dbus.MakeFailedError(fmt.Errorf("the keyring is locked"))
+	}
+	return objects, "/", nil
+}
+
+// reset removes every item and unlocks the service.
+func (s *fakeSecretService) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for path := range s.items {
+		s.conn.Export(nil, path, "org.freedesktop.Secret.Item")
+	}
+	clear(s.items)
+	s.locked = false
+}
+
+// stored returns the secrets kept by the service, by item path.
+func (s *fakeSecretService) stored() map[dbus.ObjectPath]string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	secrets := make(map[dbus.ObjectPath]string)
+	for path, item := range s.items {
+		secrets[path] = string(item.secret)
+	}
+	return secrets
+}
+
+type fakeSession struct{}
+
+func (fakeSession) Close() *dbus.Error {
+	return nil
+}
+
+type fakeCollection struct {
+	service *fakeSecretService
+}
+
+func (c fakeCollection) CreateItem(properties map[string]dbus.Variant, secret fakeSecret, replace bool) (dbus.ObjectPath, dbus.ObjectPath, *dbus.Error) {
+	attributes, ok := properties["org.freedesktop.Secret.Item.Attributes"].Value().(map[string]string)
+	if !ok {
+		return "/", "/", dbus.MakeFailedError(fmt.Errorf("missing attributes"))
+	}
+
+	s := c.service
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if replace {
+		for _, item := range s.items {
+			if maps.Equal(item.attributes, attributes) {
+				item.secret = secret.Value
+				return item.path, "/", nil
+			}
+		}
+	}
+
+	s.next++
+	item := &fakeItem{
+		service:    s,
+		path:       dbus.ObjectPath(fmt.Sprintf("/org/freedesktop/secrets/collection/login/%d", s.next)),
+		attributes: attributes,
+		secret:     secret.Value,
+	}
+	if err := s.conn.Export(item, item.path, "org.freedesktop.Secret.Item"); err != nil {
+		return "/", "/", dbus.MakeFailedError(err)
+	}
+	s.items[item.path] = item
+	return item.path, "/", nil
+}
+
+func (c fakeCollection) SearchItems(search map[string]string) ([]dbus.ObjectPath, *dbus.Error) {
+	s := c.service
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	results := []dbus.ObjectPath{}
+	for path, item := range s.items {
+		matches := true
+		for key, value := range search {
+			if item.attributes[key] != value {
+				matches = false
+			}
+		}
+		if matches {
+			results = append(results, path)
+		}
+	}
+	return results, nil
+}
+
+func (i *fakeItem) GetSecret(session dbus.ObjectPath) (fakeSecret, *dbus.Error) {
+	if session != fakeSessionPath {
+		return fakeSecret{}, dbus.MakeFailedError(fmt.Errorf("no session %s", session))
+	}
+	i.service.mu.Lock()
+	defer i.service.mu.Unlock()
+	return fakeSecret{Session: session, Parameters: []byte{}, Value: i.secret, ContentType: "text/plain"}, nil
+}
+
+func (i *fakeItem) Delete() (dbus.ObjectPath, *dbus.Error) {
+	s := i.service
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.items, i.path)
+	s.conn.Export(nil, i.path, "org.freedesktop.Secret.Item")
+	return "/", nil
+}
+
+// secretService is shared by the tests in this package; it is nil when no
+// dbus-daemon is available.
+var secretService *fakeSecretService
+
+func TestMain(m *testing.M) {
+	stop, err := startSessionBus()
+	if err == nil {
+		secretService, err = newFakeSecretService()
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "keyring tests will be skipped: %v\n", err)
+	}
+
+	code := m.Run()
+	if stop != nil {
+		stop()
+	}
+	os.Exit(code)
+}
+
+// useFakeKeyring skips the test when the fake Secret Service is not running
+// and empties it otherwise.
+func useFakeKeyring(t *testing.T) *fakeSecretService {
+	t.Helper()
+	if secretService == nil {
+		t.Skip("dbus-daemon is not available")
+	}
+	secretService.reset()
+	return secretService
+}
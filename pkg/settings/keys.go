@@ -0,0 +1,155 @@
+package settings
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/saat-sy/hyprlander/pkg/policy"
+)
+
+type key struct {
+	get func(s *Settings) string
+	set func(s *Settings, value string) error
+}
+
+var keys = map[string]key{
+	"model": {
+		get: func(s *Settings) string { return s.Model },
+		set: func(s *Settings, value string) error { s.Model = value; return nil },
+	},
+	"max_turns": {
+		get: func(s *Settings) string { return strconv.Itoa(s.MaxTurns) },
+		set: func(s *Settings, value string) error { return setInt(&s.MaxTurns, value) },
+	},
+	"yes": {
+		get: func(s *Settings) string { return strconv.FormatBool(s.AssumeYes) },
+		set: func(s *Settings, value string) error { return setBool(&s.AssumeYes, value) },
+	},
+	"hyprland_dir": {
+		get: func(s *Settings) string { return s.HyprlandDir },
+		set: func(s *Settings, value string) error { s.HyprlandDir = value; return nil },
+	},
+	"format_on_write": {
+		get: func(s *Settings) string { return strconv.FormatBool(s.FormatOnWrite) },
+		set: func(s *Settings, value string) error { return setBool(&s.FormatOnWrite, value) },
+	},
+	"policy.auto_approve": {
+		get: func(s *Settings) string { return strings.Join(s.Policy.AutoApprove, ",") },
+		set: func(s *Settings, value string) error { s.Policy.AutoApprove = splitList(value); return nil },
+	},
+	"policy.deny": {
+		get: func(s *Settings) string { return strings.Join(s.Policy.Deny, ",") },
+		set: func(s *Settings, value string) error { s.Policy.Deny = splitList(value); return nil },
+	},
+	"policy.rules": {
+		get: func(s *Settings) string {
+			rules := make([]string, len(s.Policy.Rules))
+			for i, rule := range s.Policy.Rules {
+				rules[i] = rule.Tool + ":" + rule.Pattern
+			}
+			return strings.Join(rules, ",")
+		},
+		set: func(s *Settings, value string) error {
+			var rules []policy.Rule
+			for _, item := range splitList(value) {
+				rule, err := policy.ParseRule(item)
+				if err != nil {
+					return err
+				}
+				rules = append(rules, rule)
+			}
+			s.Policy.Rules = rules
+			return nil
+		},
+	},
+	"ui.color": {
+		get: func(s *Settings) string { return strconv.FormatBool(s.UI.Color) },
+		set: func(s *Settings, value string) error { return setBool(&s.UI.Color, value) },
+	},
+	"ui.show_diff": {
+		get: func(s *Settings) string { return strconv.FormatBool(s.UI.ShowDiff) },
+		set: func(s *Settings, value string) error { return setBool(&s.UI.ShowDiff, value) },
+	},
+}
+
+// themedApps are the apps outside Hyprland that themes are written to. Their
+// config directory can be moved with app_dirs.<app>.
+var themedApps = []string{"alacritty", "foot", "kitty", "mako", "waybar", "wofi"}
+
+func init() {
+	for _, app := range themedApps {
+		keys["app_dirs."+app] = key{
+			get: func(s *Settings) string { return s.AppDirs[app] },
+			set: func(s *Settings, value string) error {
+				if value == "" {
+					delete(s.AppDirs, app)
+					return nil
+				}
+				if s.AppDirs == nil {
+					s.AppDirs = make(map[string]string)
+				}
+				s.AppDirs[app] = value
+				return nil
+			},
+		}
+	}
+}
+
+// Keys returns every settable key in a stable order.
+func Keys() []string {
+	names := make([]string, 0, len(keys))
+	for name := range keys {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (s *Settings) Get(name string) (string, error) {
+	k, ok := keys[name]
+	if !ok {
+		return "", fmt.Errorf("unknown setting %q", name)
+	}
+	return k.get(s), nil
+}
+
+func (s *Settings) Set(name, value string) error {
+	k, ok := keys[name]
+	if !ok {
+		return fmt.Errorf("unknown setting %q", name)
+	}
+	if err := k.set(s, value); err != nil {
+		return fmt.Errorf("invalid value for %s: %w", name, err)
+	}
+	return s.Validate()
+}
+
+func setInt(target *int, value string) error {
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return err
+	}
+	*target = parsed
+	return nil
+}
+
+func setBool(target *bool, value string) error {
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return err
+	}
+	*target = parsed
+	return nil
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
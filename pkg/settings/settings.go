@@ -0,0 +1,333 @@
+package settings
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/saat-sy/hyprlander/pkg/config"
+	"github.com/saat-sy/hyprlander/pkg/policy"
+)
+
+type Settings struct {
+	Profile       string             `toml:"profile,omitempty"`
+	Model         string             `toml:"model"`
+	MaxTurns      int                `toml:"max_turns"`
+	AssumeYes     bool               `toml:"yes"`
+	HyprlandDir   string             `toml:"hyprland_dir,omitempty"`
+	FormatOnWrite bool               `toml:"format_on_write"`
+	AppDirs       map[string]string  `toml:"app_dirs,omitempty"`
+	Policy        PolicySettings     `toml:"policy"`
+	UI            UISettings         `toml:"ui"`
+	Profiles      map[string]Profile `toml:"profiles,omitempty"`
+}
+
+// Profile holds per-machine or per-config values that replace the top-level
+// settings while the profile is active. Empty fields fall back to the top level.
+type Profile struct {
+	HyprlandDir string          `toml:"hyprland_dir,omitempty"`
+	Model       string          `toml:"model,omitempty"`
+	MaxTurns    int             `toml:"max_turns,omitzero"`
+	Policy      *PolicySettings `toml:"policy,omitempty"`
+}
+
+type PolicySettings struct {
+	AutoApprove []string      `toml:"auto_approve"`
+	Deny        []string      `toml:"deny"`
+	Rules       []policy.Rule `toml:"rules,omitempty"`
+}
+
+type UISettings struct {
+	Color    bool `toml:"color"`
+	ShowDiff bool `toml:"show_diff"`
+}
+
+// Overrides carries values that take precedence over the settings file, such
+// as command-line flags. Nil fields are left untouched.
+type Overrides struct {
+	Profile     *string
+	Model       *string
+	MaxTurns    *int
+	AssumeYes   *bool
+	HyprlandDir *string
+}
+
+func Default() *Settings {
+	return &Settings{
+		Model:    config.DefaultModel,
+		MaxTurns: config.DefaultMaxTurns,
+		UI: UISettings{
+			Color:    true,
+			ShowDiff: true,
+		},
+	}
+}
+
+// Load resolves the effective settings with the precedence
+// environment > overrides > active profile > settings file > defaults.
+func Load(overrides Overrides) (*Settings, error) {
+	s, err := LoadFile()
+	if err != nil {
+		return nil, err
+	}
+
+	if overrides.Profile != nil {
+		s.Profile = *overrides.Profile
+	}
+	if profile, ok := os.LookupEnv(config.EnvProfile); ok {
+		s.Profile = profile
+	}
+	if err := s.applyProfile(); err != nil {
+		return nil, err
+	}
+
+	s.apply(overrides)
+
+	if err := s.applyEnv(); err != nil {
+		return nil, err
+	}
+
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// ErrParse is wrapped by the error LoadFile returns for a settings file that
+// is not valid TOML.
+var ErrParse = errors.New("failed to parse settings file")
+
+// LoadFile reads the settings file on top of the defaults. A missing file is
+// not an error.
+func LoadFile() (*Settings, error) {
+	s := Default()
+
+	path, err := config.GetSettingsFilePath()
+	if err != nil {
+		return nil, fmt.Errorf("could not determine settings file path: %w", err)
+	}
+
+	if _, err := toml.DecodeFile(path, s); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("%w %s: %w", ErrParse, path, err)
+	}
+
+	return s, nil
+}
+
+// SetAside renames the settings file to config.toml.bak, so a file that does
+// not parse is kept when a new one is saved, and returns the new name.
+func SetAside() (string, error) {
+	path, err := config.GetSettingsFilePath()
+	if err != nil {
+		return "", fmt.Errorf("could not determine settings file path: %w", err)
+	}
+
+	backup := path + ".bak"
+	if err := os.Rename(path, backup); err != nil {
+		return "", fmt.Errorf("failed to move settings file aside: %w", err)
+	}
+	return backup, nil
+}
+
+func (s *Settings) Save() error {
+	path, err := config.GetSettingsFilePath()
+	if err != nil {
+		return fmt.Errorf("could not determine settings file path: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create settings directory: %w", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open settings file: %w", err)
+	}
+	defer file.Close()
+
+	if err := toml.NewEncoder(file).Encode(s); err != nil {
+		return fmt.Errorf("failed to write settings file: %w", err)
+	}
+
+	return nil
+}
+
+func (s *Settings) Validate() error {
+	if s.Model == "" {
+		return fmt.Errorf("model must not be empty")
+	}
+	if s.MaxTurns < 1 {
+		return fmt.Errorf("max_turns must be at least 1, got %d", s.MaxTurns)
+	}
+	return nil
+}
+
+// ProfileName returns the active profile, or the default profile's name.
+func (s *Settings) ProfileName() string {
+	if s.Profile == "" {
+		return config.DefaultProfile
+	}
+	return s.Profile
+}
+
+// APIKeyName is the name the active profile's API key is stored under.
+func (s *Settings) APIKeyName() string {
+	return ProfileAPIKeyName(s.ProfileName())
+}
+
+func ProfileAPIKeyName(profile string) string {
+	if profile == "" || profile == config.DefaultProfile {
+		return config.APIKeyName
+	}
+	return config.APIKeyName + "@" + profile
+}
+
+func (s *Settings) applyProfile() error {
+	name := s.ProfileName()
+	if name == config.DefaultProfile {
+		return nil
+	}
+
+	profile, ok := s.Profiles[name]
+	if !ok {
+		return fmt.Errorf("unknown profile %q; create it with 'hyprlander profile create %s'", name, name)
+	}
+
+	if profile.HyprlandDir != "" {
+		s.HyprlandDir = profile.HyprlandDir
+	}
+	if profile.Model != "" {
+		s.Model = profile.Model
+	}
+	if profile.MaxTurns != 0 {
+		s.MaxTurns = profile.MaxTurns
+	}
+	if profile.Policy != nil {
+		s.Policy = *profile.Policy
+	}
+
+	return nil
+}
+
+func (s *Settings) apply(overrides Overrides) {
+	if overrides.Model != nil {
+		s.Model = *overrides.Model
+	}
+	if overrides.MaxTurns != nil {
+		s.MaxTurns = *overrides.MaxTurns
+	}
+	if overrides.AssumeYes != nil {
+		s.AssumeYes = *overrides.AssumeYes
+	}
+	if overrides.HyprlandDir != nil {
+		s.HyprlandDir = *overrides.HyprlandDir
+	}
+}
+
+func (s *Settings) applyEnv() error {
+	if model, ok := os.LookupEnv(config.EnvModel); ok {
+		s.Model = model
+	}
+
+	if maxTurns, ok := os.LookupEnv(config.EnvMaxTurns); ok {
+		value, err := strconv.Atoi(maxTurns)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", config.EnvMaxTurns, err)
+		}
+		s.MaxTurns = value
+	}
+
+	if yes, ok := os.LookupEnv(config.EnvAssumeYes); ok {
+		value, err := strconv.ParseBool(yes)
+		if err != nil {
+			return fmt.Errorf("invalid %s: %w", config.EnvAssumeYes, err)
+		}
+		s.AssumeYes = value
+	}
+
+	if dir, ok := os.LookupEnv(config.EnvHyprlandDir); ok {
+		s.HyprlandDir = dir
+	}
+
+	return nil
+}
+
+// AddProfile registers a new named profile.
+func (s *Settings) AddProfile(name string, profile Profile) error {
+	if err := validateProfileName(name); err != nil {
+		return err
+	}
+	if _, exists := s.Profiles[name]; exists {
+		return fmt.Errorf("profile %q already exists", name)
+	}
+
+	if s.Profiles == nil {
+		s.Profiles = make(map[string]Profile)
+	}
+	s.Profiles[name] = profile
+	return nil
+}
+
+// UseProfile makes name the active profile for future runs.
+func (s *Settings) UseProfile(name string) error {
+	if name == config.DefaultProfile {
+		s.Profile = ""
+		return nil
+	}
+	if _, exists := s.Profiles[name]; !exists {
+		return fmt.Errorf("unknown profile %q", name)
+	}
+	s.Profile = name
+	return nil
+}
+
+// ProfileNames lists the default profile followed by the named profiles in order.
+func (s *Settings) ProfileNames() []string {
+	names := make([]string, 0, len(s.Profiles))
+	for name := range s.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return append([]string{config.DefaultProfile}, names...)
+}
+
+func validateProfileName(name string) error {
+	if name == "" {
+		return fmt.Errorf("profile name must not be empty")
+	}
+	if name == config.DefaultProfile {
+		return fmt.Errorf("%q is reserved for the top-level settings", name)
+	}
+	if strings.ContainsAny(name, " \t@/.") {
+		return fmt.Errorf("profile name %q must not contain spaces, '@', '/' or '.'", name)
+	}
+	return nil
+}
+
+// AddPolicyRule persists an always-allow rule to the settings file. It is
+// added to the profile's policy if the profile has its own, and to the
+// top-level policy otherwise.
+func AddPolicyRule(profileName string, rule policy.Rule) error {
+	s, err := LoadFile()
+	if err != nil {
+		return err
+	}
+
+	if profile, ok := s.Profiles[profileName]; ok && profile.Policy != nil {
+		profile.Policy.Rules = append(profile.Policy.Rules, rule)
+		s.Profiles[profileName] = profile
+	} else {
+		s.Policy.Rules = append(s.Policy.Rules, rule)
+	}
+
+	return s.Save()
+}
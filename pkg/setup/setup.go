@@ -1,14 +1,18 @@
 package setup
 
 import (
+	"errors"
 	"fmt"
 	"os"
-	"strings"
+	"sort"
 
 	"github.com/saat-sy/hyprlander/pkg/config"
+	"github.com/saat-sy/hyprlander/pkg/ini"
 	"github.com/saat-sy/hyprlander/pkg/ui"
 )
 
+var requiredKeys = []string{config.HyprlandDirName}
+
 type Setup struct {
 	ui ui.UI
 }
@@ -29,17 +33,16 @@ func (s *Setup) Run(values map[string]string) error {
 		return fmt.Errorf("failed to create directory %s: %w", configDir, err)
 	}
 
-	secretFilePath, err := config.GetSecretFilePath()
-	if err != nil {
-		return fmt.Errorf("could not determine hyprlander folder: %w", err)
+	file, err := s.load()
+	if errors.Is(err, os.ErrNotExist) {
+		file = ini.New()
+	} else if err != nil {
+		return err
 	}
 
-	var iniContent strings.Builder
-	for key, value := range values {
-		iniContent.WriteString(fmt.Sprintf("%s=%s\n", key, value))
-	}
+	setSorted(file.Global(), values)
 
-	if err := os.WriteFile(secretFilePath, []byte(iniContent.String()), 0600); err != nil {
+	if err := s.save(file); err != nil {
 		return fmt.Errorf("failed to write secret file: %w", err)
 	}
 
@@ -47,36 +50,35 @@ func (s *Setup) Run(values map[string]string) error {
 }
 
 func (s *Setup) Check() error {
-	secretFilePath, err := config.GetSecretFilePath()
+	file, err := s.load()
 	if err != nil {
-		return fmt.Errorf("could not determine hyprlander folder and the secret file: %w", err)
+		return err
 	}
 
-	content, err := os.ReadFile(secretFilePath)
-	if err != nil {
-		return fmt.Errorf("failed to read secret file: %w", err)
-	}
-
-	apiKey := string(content)
-	if len(apiKey) == 0 {
-		return fmt.Errorf("secret file is empty")
+	if err := file.Global().Require(requiredKeys...); err != nil {
+		return fmt.Errorf("secret file is incomplete: %w", err)
 	}
 
 	return nil
 }
 
+// Update replaces the top-level values of the secret file. Keys that are not
+// in values are removed; comments and sections are kept.
 func (s *Setup) Update(values map[string]string) error {
-	secretFilePath, err := config.GetSecretFilePath()
+	file, err := s.load()
 	if err != nil {
-		return fmt.Errorf("could not determine hyprlander folder and the secret file: %w", err)
+		return err
 	}
 
-	var iniContent strings.Builder
-	for key, value := range values {
-		iniContent.WriteString(fmt.Sprintf("%s=%s\n", key, value))
+	global := file.Global()
+	for _, key := range global.Keys() {
+		if _, ok := values[key]; !ok {
+			global.Delete(key)
+		}
 	}
+	setSorted(global, values)
 
-	if err := os.WriteFile(secretFilePath, []byte(iniContent.String()), 0600); err != nil {
+	if err := s.save(file); err != nil {
 		return fmt.Errorf("failed to update secret file: %w", err)
 	}
 
@@ -87,28 +89,69 @@ func (s *Setup) Prompt(message string) (string, error) {
 	return s.ui.InputRequired(message)
 }
 
+func (s *Setup) PromptSecret(message string) (string, error) {
+	return s.ui.InputSecret(message)
+}
+
 func (s *Setup) FetchConfig() (map[string]string, error) {
+	file, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	return file.Global().Map(), nil
+}
+
+// FetchProfileConfig returns the top-level values overlaid with those in the
+// [profile] section of the secret file, if there is one.
+func (s *Setup) FetchProfileConfig(profile string) (map[string]string, error) {
+	file, err := s.load()
+	if err != nil {
+		return nil, err
+	}
+
+	values := file.Global().Map()
+	if section := file.Section(profile); section != nil && profile != config.DefaultProfile {
+		for key, value := range section.Map() {
+			values[key] = value
+		}
+	}
+
+	return values, nil
+}
+
+func (s *Setup) load() (*ini.File, error) {
 	secretFilePath, err := config.GetSecretFilePath()
 	if err != nil {
 		return nil, fmt.Errorf("could not determine hyprlander folder and the secret file: %w", err)
 	}
 
-	content, err := os.ReadFile(secretFilePath)
+	file, err := ini.Load(secretFilePath)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read secret file: %w", err)
 	}
 
-	lines := strings.Split(string(content), "\n")
-	dataMap := make(map[string]string)
-	for _, line := range lines {
-		if strings.TrimSpace(line) == "" {
-			continue
-		}
-		parts := strings.SplitN(line, "=", 2)
-		if len(parts) == 2 {
-			dataMap[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
-		}
+	return file, nil
+}
+
+func (s *Setup) save(file *ini.File) error {
+	secretFilePath, err := config.GetSecretFilePath()
+	if err != nil {
+		return fmt.Errorf("could not determine hyprlander folder and the secret file: %w", err)
 	}
 
-	return dataMap, nil
+	return file.Save(secretFilePath, 0600)
+}
+
+// setSorted sets values in key order so new keys are appended deterministically.
+func setSorted(section *ini.Section, values map[string]string) {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		section.Set(key, values[key])
+	}
 }
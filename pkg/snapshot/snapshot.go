@@ -0,0 +1,153 @@
+// Package snapshot saves copies of config files before hyprlander rewrites
+// them without asking the model, so a migration or theme can be undone.
+package snapshot
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"time"
+)
+
+const (
+	manifestName = "manifest.json"
+	filesDirName = "files"
+	idLayout     = "20060102-150405"
+)
+
+type Snapshot struct {
+	ID      string    `json:"id"`
+	Created time.Time `json:"created"`
+	// Reason is the command that took the snapshot, e.g. "migrate --to v0.45.0".
+	Reason string `json:"reason"`
+	Files  []File `json:"files"`
+
+	dir string
+}
+
+type File struct {
+	Path string `json:"path"`
+	// Existed is false for files the command created; restoring removes them.
+	Existed bool `json:"existed"`
+}
+
+// Create copies paths into a new snapshot under dir.
+func Create(dir, reason string, paths []string) (*Snapshot, error) {
+	created := time.Now()
+	snapshot := &Snapshot{ID: created.Format(idLayout), Created: created, Reason: reason}
+	for i := 2; ; i++ {
+		snapshot.dir = filepath.Join(dir, snapshot.ID)
+		if _, err := os.Stat(snapshot.dir); errors.Is(err, os.ErrNotExist) {
+			break
+		}
+		snapshot.ID = created.Format(idLayout) + "-" + strconv.Itoa(i)
+	}
+
+	if err := os.MkdirAll(filepath.Join(snapshot.dir, filesDirName), 0700); err != nil {
+		return nil, fmt.Errorf("could not create snapshot directory: %w", err)
+	}
+
+	for i, path := range paths {
+		path, err := filepath.Abs(path)
+		if err != nil {
+			return nil, err
+		}
+		content, err := os.ReadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			snapshot.Files = append(snapshot.Files, File{Path: path})
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("could not read %s: %w", path, err)
+		}
+		if err := os.WriteFile(snapshot.copyPath(i), content, 0600); err != nil {
+			return nil, fmt.Errorf("could not save %s: %w", path, err)
+		}
+		snapshot.Files = append(snapshot.Files, File{Path: path, Existed: true})
+	}
+
+	manifest, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(snapshot.dir, manifestName), manifest, 0600); err != nil {
+		return nil, fmt.Errorf("could not write snapshot manifest: %w", err)
+	}
+
+	return snapshot, nil
+}
+
+func (s *Snapshot) copyPath(index int) string {
+	return filepath.Join(s.dir, filesDirName, strconv.Itoa(index))
+}
+
+// Load reads the snapshot with the given id from dir.
+func Load(dir, id string) (*Snapshot, error) {
+	content, err := os.ReadFile(filepath.Join(dir, id, manifestName))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("no snapshot %q", id)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read snapshot %s: %w", id, err)
+	}
+
+	var snapshot Snapshot
+	if err := json.Unmarshal(content, &snapshot); err != nil {
+		return nil, fmt.Errorf("could not parse snapshot %s: %w", id, err)
+	}
+	snapshot.dir = filepath.Join(dir, id)
+	return &snapshot, nil
+}
+
+// List returns the snapshots in dir, newest first.
+func List(dir string) ([]*Snapshot, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read snapshots: %w", err)
+	}
+
+	var snapshots []*Snapshot
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		snapshot, err := Load(dir, entry.Name())
+		if err != nil {
+			continue
+		}
+		snapshots = append(snapshots, snapshot)
+	}
+	sort.Slice(snapshots, func(i, j int) bool { return snapshots[i].Created.After(snapshots[j].Created) })
+	return snapshots, nil
+}
+
+// Restore puts every file back as it was when the snapshot was taken.
+func (s *Snapshot) Restore() error {
+	for i, file := range s.Files {
+		if !file.Existed {
+			if err := os.Remove(file.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("could not remove %s: %w", file.Path, err)
+			}
+			continue
+		}
+
+		content, err := os.ReadFile(s.copyPath(i))
+		if err != nil {
+			return fmt.Errorf("could not read saved copy of %s: %w", file.Path, err)
+		}
+		if err := os.MkdirAll(filepath.Dir(file.Path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(file.Path, content, 0644); err != nil {
+			return fmt.Errorf("could not restore %s: %w", file.Path, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package theme
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/saat-sy/hyprlander/pkg/color"
+	"github.com/saat-sy/hyprlander/pkg/hyprlang"
+)
+
+// HyprlandApp names the Hyprland config itself in a list of apps.
+const HyprlandApp = "hyprland"
+
+// AllApps selects Hyprland and every app whose config exists.
+const AllApps = "all"
+
+// App is a program outside Hyprland whose colors a theme can set.
+type App struct {
+	Name string
+	// Dir is the app's directory under $XDG_CONFIG_HOME and File its config
+	// file in that directory.
+	Dir  string
+	File string
+	// apply returns content with the palette's colors set.
+	apply func(content string, p Palette) (string, error)
+}
+
+var apps = []App{
+	{Name: "waybar", Dir: "waybar", File: "style.css", apply: applyCSS},
+	{Name: "kitty", Dir: "kitty", File: "kitty.conf", apply: applyKitty},
+	{Name: "foot", Dir: "foot", File: "foot.ini", apply: applyFoot},
+	{Name: "alacritty", Dir: "alacritty", File: "alacritty.toml", apply: applyAlacritty},
+	{Name: "mako", Dir: "mako", File: "config", apply: applyMako},
+	{Name: "wofi", Dir: "wofi", File: "style.css", apply: applyCSS},
+}
+
+// Apps lists the apps themes can be written to.
+func Apps() []App {
+	return apps
+}
+
+// FindApp looks up an app by name.
+func FindApp(name string) (App, error) {
+	var names []string
+	for _, app := range apps {
+		if app.Name == name {
+			return app, nil
+		}
+		names = append(names, app.Name)
+	}
+	return App{}, fmt.Errorf("unknown app %q, available: %s, %s", name, HyprlandApp, strings.Join(names, ", "))
+}
+
+// Path is the app's config file. dirs maps app names to directories that
+// replace the default under $XDG_CONFIG_HOME.
+func (a App) Path(dirs map[string]string) (string, error) {
+	if dir := dirs[a.Name]; dir != "" {
+		if rest, ok := strings.CutPrefix(dir, "~/"); ok {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return "", err
+			}
+			dir = filepath.Join(home, rest)
+		}
+		return filepath.Join(dir, a.File), nil
+	}
+
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		configHome = filepath.Join(home, ".config")
+	}
+	return filepath.Join(configHome, a.Dir, a.File), nil
+}
+
+// Plan computes the change that sets the palette's colors in the app's
+// config. A missing config file is created if the app's directory exists.
+// It returns nil if nothing changes.
+func (a App) Plan(p Palette, dirs map[string]string) (*hyprlang.FileChange, error) {
+	path, err := a.Path(dirs)
+	if err != nil {
+		return nil, err
+	}
+	original, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		if _, statErr := os.Stat(filepath.Dir(path)); statErr != nil {
+			return nil, fmt.Errorf("no %s config found at %s; set app_dirs.%s if it lives elsewhere", a.Name, path, a.Name)
+		}
+	} else if err != nil {
+		return nil, err
+	}
+
+	content, err := a.apply(string(original), p)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if content == string(original) {
+		return nil, nil
+	}
+	return &hyprlang.FileChange{Path: path, Original: string(original), Content: content}, nil
+}
+
+// Installed reports whether the app's config exists.
+func (a App) Installed(dirs map[string]string) bool {
+	path, err := a.Path(dirs)
+	if err != nil {
+		return false
+	}
+	_, err = os.Stat(path)
+	return err == nil
+}
+
+// Terminal returns the 16 ANSI colors: the theme's own, or for palettes
+// without them, such as wallpaper palettes, hues at the accent's lightness
+// that stay readable on the background.
+func (p Palette) Terminal() []color.Color {
+	if len(p.ANSI) == 16 {
+		return p.ANSI
+	}
+
+	dark := p.Variant != "light"
+	lightness, chroma, _ := p.Accent.OKLCH()
+	chroma = max(chroma, 0.1)
+	hues := []float64{25, 145, 90, 255, 330, 195}
+
+	// Black and white swap places on light backgrounds, as in light themes.
+	black, white := p.Surface, p.Muted
+	brighter := 0.08
+	if !dark {
+		black, white = p.Muted, p.Surface
+		brighter = -0.08
+	}
+
+	normal := []color.Color{black}
+	for _, hue := range hues {
+		normal = append(normal, ensureContrast(color.FromOKLCH(lightness, chroma, hue, 1), p.Background, componentContrast, dark))
+	}
+	normal = append(normal, white)
+
+	var bright []color.Color
+	for _, c := range normal {
+		l, chroma, hue := c.OKLCH()
+		bright = append(bright, color.FromOKLCH(l+brighter, chroma, hue, 1))
+	}
+	if dark {
+		bright[7] = p.Foreground
+	}
+	return append(normal, bright...)
+}
+
+// hexDigits is a color as RRGGBB without the #, the form foot expects.
+func hexDigits(c color.Color) string {
+	return strings.TrimPrefix(c.Hex(), "#")
+}
@@ -0,0 +1,75 @@
+package theme
+
+import (
+	"regexp"
+	"strings"
+)
+
+// defineColor matches a GTK @define-color rule, which waybar and wofi
+// stylesheets use for their color variables.
+var defineColor = regexp.MustCompile(`^(\s*@define-color\s+)([\w-]+)(\s+)([^;]*)(;.*)$`)
+
+// cssVariables are the variables a theme defines in GTK stylesheets, so
+// rules can use @accent, @background and so on.
+func cssVariables(p Palette) [][2]string {
+	var variables [][2]string
+	for _, role := range p.Roles() {
+		variables = append(variables, [2]string{role.Name, role.Color.Hex()})
+	}
+	return variables
+}
+
+// applyCSS updates the @define-color rules for the palette's roles in place
+// and adds the missing ones after any @import rules, which must come first.
+func applyCSS(content string, p Palette) (string, error) {
+	lines := strings.Split(content, "\n")
+	defined := make(map[string]bool)
+	values := make(map[string]string)
+	for _, variable := range cssVariables(p) {
+		values[variable[0]] = variable[1]
+	}
+
+	insertAt := 0
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "@import") {
+			insertAt = i + 1
+		}
+		match := defineColor.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		if value, ok := values[match[2]]; ok {
+			lines[i] = match[1] + match[2] + match[3] + value + match[5]
+			defined[match[2]] = true
+		}
+	}
+
+	var missing []string
+	for _, variable := range cssVariables(p) {
+		if !defined[variable[0]] {
+			missing = append(missing, "@define-color "+variable[0]+" "+variable[1]+";")
+		}
+	}
+	if len(missing) == 0 {
+		return strings.Join(lines, "\n"), nil
+	}
+
+	block := append([]string{"/* Colors set by hyprlander */"}, missing...)
+	if content == "" {
+		return strings.Join(block, "\n") + "\n", nil
+	}
+	block = append(block, "")
+	if insertAt > 0 {
+		// Reuse the blank lines after the imports as the separator.
+		if insertAt < len(lines) && strings.TrimSpace(lines[insertAt]) == "" {
+			for insertAt < len(lines) && strings.TrimSpace(lines[insertAt]) == "" {
+				insertAt++
+			}
+		} else {
+			block = append([]string{""}, block...)
+		}
+	}
+	lines = append(lines[:insertAt], append(block, lines[insertAt:]...)...)
+	return strings.Join(lines, "\n"), nil
+}
@@ -0,0 +1,189 @@
+package theme
+
+import (
+	"fmt"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+	"math"
+	"os"
+	"sort"
+
+	"github.com/saat-sy/hyprlander/pkg/color"
+	_ "golang.org/x/image/webp"
+)
+
+// maxSamples bounds the pixels clustered, so large wallpapers are as fast as
+// small ones.
+const maxSamples = 16384
+
+// Swatch is a color cluster of an image and the share of pixels in it.
+type Swatch struct {
+	Color      color.Color
+	Population float64
+}
+
+// oklab is a sample in the OKLab space, where distances follow perceived
+// differences.
+type oklab [3]float64
+
+func toOKLab(c color.Color) oklab {
+	lightness, chroma, hue := c.OKLCH()
+	radians := hue * math.Pi / 180
+	return oklab{lightness, chroma * math.Cos(radians), chroma * math.Sin(radians)}
+}
+
+func (p oklab) color() color.Color {
+	return color.FromOKLCH(p[0], math.Hypot(p[1], p[2]), math.Atan2(p[2], p[1])*180/math.Pi, 1)
+}
+
+func (p oklab) distance(q oklab) float64 {
+	d0, d1, d2 := p[0]-q[0], p[1]-q[1], p[2]-q[2]
+	return d0*d0 + d1*d1 + d2*d2
+}
+
+// LoadImage decodes a PNG, JPEG, GIF or WebP file.
+func LoadImage(path string) (image.Image, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	img, _, err := image.Decode(file)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode %s (PNG, JPEG, GIF and WebP are supported): %w", path, err)
+	}
+	return img, nil
+}
+
+// Extract reduces an image to at most k swatches, sorted by population. The
+// colors are split with median cut and refined with k-means in OKLab.
+func Extract(img image.Image, k int) []Swatch {
+	samples := sample(img)
+	if len(samples) == 0 {
+		return nil
+	}
+
+	centroids := medianCut(samples, k)
+	assignments := make([]int, len(samples))
+	for range 16 {
+		changed := false
+		for i, s := range samples {
+			nearest := 0
+			for j := range centroids {
+				if s.distance(centroids[j]) < s.distance(centroids[nearest]) {
+					nearest = j
+				}
+			}
+			if assignments[i] != nearest {
+				assignments[i] = nearest
+				changed = true
+			}
+		}
+
+		sums := make([]oklab, len(centroids))
+		counts := make([]int, len(centroids))
+		for i, s := range samples {
+			cluster := assignments[i]
+			for axis := range s {
+				sums[cluster][axis] += s[axis]
+			}
+			counts[cluster]++
+		}
+		for j := range centroids {
+			if counts[j] > 0 {
+				centroids[j] = oklab{sums[j][0] / float64(counts[j]), sums[j][1] / float64(counts[j]), sums[j][2] / float64(counts[j])}
+			}
+		}
+		if !changed {
+			break
+		}
+	}
+
+	counts := make([]int, len(centroids))
+	for _, cluster := range assignments {
+		counts[cluster]++
+	}
+	var swatches []Swatch
+	for j, centroid := range centroids {
+		if counts[j] > 0 {
+			swatches = append(swatches, Swatch{centroid.color(), float64(counts[j]) / float64(len(samples))})
+		}
+	}
+	sort.SliceStable(swatches, func(i, j int) bool { return swatches[i].Population > swatches[j].Population })
+	return swatches
+}
+
+// sample reads an evenly spaced grid of opaque pixels.
+func sample(img image.Image) []oklab {
+	bounds := img.Bounds()
+	step := max(1, int(math.Sqrt(float64(bounds.Dx()*bounds.Dy())/maxSamples)))
+
+	var samples []oklab
+	for y := bounds.Min.Y; y < bounds.Max.Y; y += step {
+		for x := bounds.Min.X; x < bounds.Max.X; x += step {
+			r, g, b, a := img.At(x, y).RGBA()
+			if a < 0x8000 {
+				continue
+			}
+			// RGBA is premultiplied; undo it before converting.
+			c := color.Color{R: float64(r) / float64(a), G: float64(g) / float64(a), B: float64(b) / float64(a), A: 1}
+			samples = append(samples, toOKLab(c))
+		}
+	}
+	return samples
+}
+
+// medianCut splits the samples into up to k boxes, always cutting the box
+// with the widest spread at the median of that axis, and returns their means.
+func medianCut(samples []oklab, k int) []oklab {
+	boxes := [][]oklab{samples}
+	for len(boxes) < k {
+		widest, widestAxis, widestSpread := -1, 0, 0.0
+		for i, box := range boxes {
+			if len(box) < 2 {
+				continue
+			}
+			axis, spread := spreadOf(box)
+			if spread*float64(len(box)) > widestSpread {
+				widest, widestAxis, widestSpread = i, axis, spread*float64(len(box))
+			}
+		}
+		if widest < 0 || widestSpread == 0 {
+			break
+		}
+
+		box := boxes[widest]
+		sort.Slice(box, func(i, j int) bool { return box[i][widestAxis] < box[j][widestAxis] })
+		middle := len(box) / 2
+		boxes[widest] = box[:middle]
+		boxes = append(boxes, box[middle:])
+	}
+
+	centroids := make([]oklab, len(boxes))
+	for i, box := range boxes {
+		for _, s := range box {
+			for axis := range s {
+				centroids[i][axis] += s[axis] / float64(len(box))
+			}
+		}
+	}
+	return centroids
+}
+
+func spreadOf(box []oklab) (int, float64) {
+	widestAxis, widestSpread := 0, 0.0
+	for axis := range 3 {
+		lowest, highest := math.Inf(1), math.Inf(-1)
+		for _, s := range box {
+			lowest = min(lowest, s[axis])
+			highest = max(highest, s[axis])
+		}
+		if highest-lowest > widestSpread {
+			widestAxis, widestSpread = axis, highest-lowest
+		}
+	}
+	return widestAxis, widestSpread
+}
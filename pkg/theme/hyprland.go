@@ -0,0 +1,105 @@
+package theme
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/saat-sy/hyprlander/pkg/color"
+	"github.com/saat-sy/hyprlander/pkg/hyprlang"
+)
+
+// Setting is one option a theme sets.
+type Setting struct {
+	Path  string
+	Value string
+}
+
+// HyprlandSettings maps a palette onto the border, group, shadow and
+// background colors of Hyprland.
+func HyprlandSettings(p Palette) []Setting {
+	active := color.Gradient{Colors: []color.Color{p.Accent, p.AccentAlt}, Angle: "45deg"}.String()
+	inactive := p.Surface.String()
+
+	return []Setting{
+		{"general:col.active_border", active},
+		{"general:col.inactive_border", inactive},
+		{"group:col.border_active", active},
+		{"group:col.border_inactive", inactive},
+		{"group:col.border_locked_active", p.Urgent.String()},
+		{"group:col.border_locked_inactive", inactive},
+		{"group:groupbar:col.active", p.Accent.String()},
+		{"group:groupbar:col.inactive", inactive},
+		{"group:groupbar:col.locked_active", p.Urgent.String()},
+		{"group:groupbar:col.locked_inactive", inactive},
+		{"group:groupbar:text_color", readableOn(p, p.Accent, p.Surface).String()},
+		{"decoration:shadow:color", p.Shadow.WithAlpha(0xee / 255.0).String()},
+		{"misc:background_color", p.Background.String()},
+	}
+}
+
+// readableOn picks the foreground or background color, whichever stays more
+// legible on every one of the given backgrounds.
+func readableOn(p Palette, backgrounds ...color.Color) color.Color {
+	worst := func(c color.Color) float64 {
+		lowest := 21.0
+		for _, background := range backgrounds {
+			lowest = min(lowest, color.ContrastRatio(c, background))
+		}
+		return lowest
+	}
+	if worst(p.Background) > worst(p.Foreground) {
+		return p.Background
+	}
+	return p.Foreground
+}
+
+// Plan computes the edits that apply the palette to a config. Each option is
+// changed where it takes effect, or added to hyprland.conf.
+func Plan(cfg *hyprlang.Config, p Palette) []hyprlang.FileChange {
+	editor := hyprlang.NewEditor(cfg)
+	for _, setting := range HyprlandSettings(p) {
+		editor.Set(setting.Path, setting.Value)
+	}
+	return editor.Changes()
+}
+
+// Preview shows the palette as truecolor swatches with the options it sets.
+func Preview(p Palette) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "%s (%s)\n", p.Title, p.Variant)
+	for _, role := range p.Roles() {
+		fmt.Fprintf(&builder, "  %s  %-11s %s\n", swatch(role.Color), role.Name, role.Color.Hex())
+	}
+	if len(p.ANSI) > 0 {
+		builder.WriteString("  ")
+		for _, c := range p.ANSI {
+			builder.WriteString(swatch(c))
+		}
+		builder.WriteString("\n")
+	}
+	builder.WriteString("\n")
+	for _, setting := range HyprlandSettings(p) {
+		fmt.Fprintf(&builder, "  %s = %s\n", setting.Path, setting.Value)
+	}
+	return strings.TrimRight(builder.String(), "\n")
+}
+
+// PreviewSwatches shows the colors extracted from an image with the share of
+// the image each one covers.
+func PreviewSwatches(swatches []Swatch) string {
+	var builder strings.Builder
+	builder.WriteString("Dominant colors\n")
+	for _, s := range swatches {
+		fmt.Fprintf(&builder, "  %s  %s %3.0f%%\n", swatch(s.Color), s.Color.Hex(), s.Population*100)
+	}
+	return builder.String()
+}
+
+func swatch(c color.Color) string {
+	r, g, b := to8(c.R), to8(c.G), to8(c.B)
+	return fmt.Sprintf("\033[48;2;%d;%d;%dm    \033[0m", r, g, b)
+}
+
+func to8(component float64) int {
+	return int(component*255 + 0.5)
+}
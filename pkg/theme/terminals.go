@@ -0,0 +1,195 @@
+package theme
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	"github.com/saat-sy/hyprlander/pkg/color"
+	"github.com/saat-sy/hyprlander/pkg/ini"
+)
+
+// setting is one key an app writer sets, in the order it is added.
+type setting struct {
+	key   string
+	value string
+}
+
+func terminalSettings(p Palette, prefix string, format func(color.Color) string) []setting {
+	var settings []setting
+	for i, c := range p.Terminal() {
+		settings = append(settings, setting{fmt.Sprintf("%s%d", prefix, i), format(c)})
+	}
+	return settings
+}
+
+// kittyLine splits a kitty.conf line into indentation, key, separator and
+// value.
+var kittyLine = regexp.MustCompile(`^(\s*)(\S+)(\s+)(.*)$`)
+
+// applyKitty sets the colors in kitty.conf. Kitty uses the last value of a
+// key, so that is the one edited, unless an include comes after it: then the
+// key is appended at the end to override whatever the include sets.
+func applyKitty(content string, p Palette) (string, error) {
+	hex := func(c color.Color) string { return c.Hex() }
+	settings := []setting{
+		{"foreground", p.Foreground.Hex()},
+		{"background", p.Background.Hex()},
+		{"selection_foreground", p.Background.Hex()},
+		{"selection_background", p.Accent.Hex()},
+		{"cursor", p.Accent.Hex()},
+		{"cursor_text_color", p.Background.Hex()},
+		{"url_color", p.AccentAlt.Hex()},
+		{"active_border_color", p.Accent.Hex()},
+		{"inactive_border_color", p.Surface.Hex()},
+		{"bell_border_color", p.Urgent.Hex()},
+		{"active_tab_foreground", p.Background.Hex()},
+		{"active_tab_background", p.Accent.Hex()},
+		{"inactive_tab_foreground", p.Foreground.Hex()},
+		{"inactive_tab_background", p.Surface.Hex()},
+		{"tab_bar_background", p.Background.Hex()},
+	}
+	settings = append(settings, terminalSettings(p, "color", hex)...)
+
+	lines := strings.Split(content, "\n")
+	lastInclude := -1
+	last := make(map[string]int)
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		key := strings.Fields(trimmed)[0]
+		switch key {
+		case "include", "globinclude", "envinclude", "geninclude":
+			lastInclude = i
+		default:
+			last[key] = i
+		}
+	}
+
+	var missing []string
+	for _, s := range settings {
+		i, ok := last[s.key]
+		if !ok || i < lastInclude {
+			missing = append(missing, s.key+" "+s.value)
+			continue
+		}
+		if match := kittyLine.FindStringSubmatch(lines[i]); match != nil {
+			lines[i] = match[1] + match[2] + match[3] + s.value
+		}
+	}
+	return appendBlock(strings.Join(lines, "\n"), "#", missing), nil
+}
+
+// appendBlock adds lines at the end under a comment saying hyprlander set
+// them.
+func appendBlock(content, comment string, lines []string) string {
+	if len(lines) == 0 {
+		return content
+	}
+	block := comment + " Colors set by hyprlander\n" + strings.Join(lines, "\n") + "\n"
+	if strings.TrimSpace(content) == "" {
+		return block
+	}
+	return strings.TrimRight(content, "\n") + "\n\n" + block
+}
+
+// applyFoot sets the colors section of foot.ini: [colors-dark] or
+// [colors-light] when the config already uses them, [colors] otherwise.
+func applyFoot(content string, p Palette) (string, error) {
+	file, err := ini.Parse(content)
+	if err != nil {
+		return "", err
+	}
+
+	name := "colors"
+	if section := "colors-" + p.Variant; file.Section(section) != nil {
+		name = section
+	}
+	section := file.EnsureSection(name)
+
+	settings := []setting{
+		{"foreground", hexDigits(p.Foreground)},
+		{"background", hexDigits(p.Background)},
+		{"selection-foreground", hexDigits(p.Background)},
+		{"selection-background", hexDigits(p.Accent)},
+		{"urls", hexDigits(p.AccentAlt)},
+	}
+	terminal := terminalSettings(p, "", hexDigits)
+	for i, s := range terminal {
+		if i < 8 {
+			s.key = "regular" + s.key
+		} else {
+			s.key = fmt.Sprintf("bright%d", i-8)
+		}
+		settings = append(settings, s)
+	}
+	for _, s := range settings {
+		section.Set(s.key, s.value)
+	}
+	return keepTrailingNewline(content, file.String()), nil
+}
+
+// applyMako sets the notification colors in the global section of the mako
+// config and the border of critical notifications.
+func applyMako(content string, p Palette) (string, error) {
+	file, err := ini.Parse(content)
+	if err != nil {
+		return "", err
+	}
+
+	global := file.Global()
+	global.Set("background-color", p.Background.Hex())
+	global.Set("text-color", p.Foreground.Hex())
+	global.Set("border-color", p.Accent.Hex())
+	file.EnsureSection("urgency=high").Set("border-color", p.Urgent.Hex())
+	return keepTrailingNewline(content, file.String()), nil
+}
+
+// keepTrailingNewline drops the newline the INI writer adds when the
+// original file did not end with one, so an unchanged file stays unchanged.
+func keepTrailingNewline(original, content string) string {
+	if original != "" && !strings.HasSuffix(original, "\n") {
+		return strings.TrimSuffix(content, "\n")
+	}
+	return content
+}
+
+// applyAlacritty sets the color tables of alacritty.toml and checks that the
+// result still parses, since colors may also be set with inline tables that
+// the line editor does not touch.
+func applyAlacritty(content string, p Palette) (string, error) {
+	names := []string{"black", "red", "green", "yellow", "blue", "magenta", "cyan", "white"}
+	terminal := p.Terminal()
+
+	tables := []struct {
+		name     string
+		settings []setting
+	}{
+		{"colors.primary", []setting{{"background", p.Background.Hex()}, {"foreground", p.Foreground.Hex()}}},
+		{"colors.cursor", []setting{{"text", p.Background.Hex()}, {"cursor", p.Accent.Hex()}}},
+		{"colors.selection", []setting{{"text", p.Background.Hex()}, {"background", p.Accent.Hex()}}},
+		{"colors.normal", nil},
+		{"colors.bright", nil},
+	}
+	for i, name := range names {
+		tables[3].settings = append(tables[3].settings, setting{name, terminal[i].Hex()})
+		tables[4].settings = append(tables[4].settings, setting{name, terminal[i+8].Hex()})
+	}
+
+	doc := parseTOML(content)
+	for _, table := range tables {
+		for _, s := range table.settings {
+			doc.set(table.name, s.key, s.value)
+		}
+	}
+
+	result := doc.String()
+	var decoded map[string]interface{}
+	if _, err := toml.Decode(result, &decoded); err != nil {
+		return "", fmt.Errorf("could not set the colors without breaking the file, edit it by hand: %w", err)
+	}
+	return result, nil
+}
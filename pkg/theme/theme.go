@@ -0,0 +1,187 @@
+// Package theme holds color palettes and maps them onto Hyprland options, so
+// a theme can be applied without asking the model.
+package theme
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/BurntSushi/toml"
+	"github.com/saat-sy/hyprlander/pkg/color"
+)
+
+//go:embed themes/*.toml
+var themeFiles embed.FS
+
+// Palette is a theme reduced to the roles hyprlander maps onto config
+// options.
+type Palette struct {
+	Name    string
+	Title   string
+	Variant string
+
+	Background color.Color
+	// Surface is used for inactive borders and groupbars.
+	Surface    color.Color
+	Foreground color.Color
+	Muted      color.Color
+	Accent     color.Color
+	AccentAlt  color.Color
+	Urgent     color.Color
+	Shadow     color.Color
+	// ANSI holds the 16 terminal colors, when the theme defines them.
+	ANSI []color.Color
+}
+
+// paletteFile is the on-disk form of a palette, with colors as strings.
+type paletteFile struct {
+	Title      string   `toml:"title"`
+	Variant    string   `toml:"variant"`
+	Background string   `toml:"background"`
+	Surface    string   `toml:"surface"`
+	Foreground string   `toml:"foreground"`
+	Muted      string   `toml:"muted"`
+	Accent     string   `toml:"accent"`
+	AccentAlt  string   `toml:"accent_alt"`
+	Urgent     string   `toml:"urgent"`
+	Shadow     string   `toml:"shadow"`
+	ANSI       []string `toml:"ansi"`
+}
+
+var (
+	loadOnce sync.Once
+	palettes []Palette
+	loadErr  error
+)
+
+func load() {
+	entries, err := themeFiles.ReadDir("themes")
+	if err != nil {
+		loadErr = err
+		return
+	}
+	for _, entry := range entries {
+		content, err := themeFiles.ReadFile(path.Join("themes", entry.Name()))
+		if err != nil {
+			loadErr = err
+			return
+		}
+		palette, err := Parse(strings.TrimSuffix(entry.Name(), ".toml"), string(content))
+		if err != nil {
+			loadErr = fmt.Errorf("theme %s: %w", entry.Name(), err)
+			return
+		}
+		palettes = append(palettes, palette)
+	}
+	sort.Slice(palettes, func(i, j int) bool { return palettes[i].Name < palettes[j].Name })
+}
+
+// Parse reads a palette from TOML. Colors may be CSS hex or any Hyprland
+// color. A missing shadow is derived from the background.
+func Parse(name, content string) (Palette, error) {
+	var file paletteFile
+	if _, err := toml.Decode(content, &file); err != nil {
+		return Palette{}, err
+	}
+
+	palette := Palette{Name: name, Title: file.Title, Variant: file.Variant}
+	if palette.Title == "" {
+		palette.Title = name
+	}
+
+	fields := []struct {
+		key    string
+		value  string
+		target *color.Color
+	}{
+		{"background", file.Background, &palette.Background},
+		{"surface", file.Surface, &palette.Surface},
+		{"foreground", file.Foreground, &palette.Foreground},
+		{"muted", file.Muted, &palette.Muted},
+		{"accent", file.Accent, &palette.Accent},
+		{"accent_alt", file.AccentAlt, &palette.AccentAlt},
+		{"urgent", file.Urgent, &palette.Urgent},
+	}
+	for _, field := range fields {
+		c, err := color.ParseAny(field.value)
+		if err != nil {
+			return Palette{}, fmt.Errorf("%s: %w", field.key, err)
+		}
+		*field.target = c
+	}
+
+	palette.Shadow = palette.Background.Darken(0.6)
+	if file.Shadow != "" {
+		c, err := color.ParseAny(file.Shadow)
+		if err != nil {
+			return Palette{}, fmt.Errorf("shadow: %w", err)
+		}
+		palette.Shadow = c
+	}
+
+	if len(file.ANSI) != 0 && len(file.ANSI) != 16 {
+		return Palette{}, fmt.Errorf("ansi must list 16 colors, found %d", len(file.ANSI))
+	}
+	for i, value := range file.ANSI {
+		c, err := color.ParseAny(value)
+		if err != nil {
+			return Palette{}, fmt.Errorf("ansi %d: %w", i, err)
+		}
+		palette.ANSI = append(palette.ANSI, c)
+	}
+
+	return palette, nil
+}
+
+// List returns the bundled themes sorted by name.
+func List() ([]Palette, error) {
+	loadOnce.Do(load)
+	return palettes, loadErr
+}
+
+// Get finds a bundled theme by name, ignoring case, spaces and accents in
+// the title, so "Catppuccin Mocha" finds catppuccin-mocha.
+func Get(name string) (Palette, error) {
+	all, err := List()
+	if err != nil {
+		return Palette{}, err
+	}
+
+	wanted := normalize(name)
+	var names []string
+	for _, palette := range all {
+		if normalize(palette.Name) == wanted || normalize(palette.Title) == wanted {
+			return palette, nil
+		}
+		names = append(names, palette.Name)
+	}
+	return Palette{}, fmt.Errorf("unknown theme %q, available: %s", name, strings.Join(names, ", "))
+}
+
+func normalize(name string) string {
+	return strings.NewReplacer("é", "e", " ", "-", "_", "-").Replace(strings.ToLower(strings.TrimSpace(name)))
+}
+
+// Roles returns the palette's named colors in display order.
+func (p Palette) Roles() []Role {
+	return []Role{
+		{"background", p.Background},
+		{"surface", p.Surface},
+		{"foreground", p.Foreground},
+		{"muted", p.Muted},
+		{"accent", p.Accent},
+		{"accent_alt", p.AccentAlt},
+		{"urgent", p.Urgent},
+		{"shadow", p.Shadow},
+	}
+}
+
+// Role is a named color of a palette.
+type Role struct {
+	Name  string
+	Color color.Color
+}
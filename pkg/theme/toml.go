@@ -0,0 +1,88 @@
+package theme
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	tomlHeader = regexp.MustCompile(`^\s*\[\s*([^\[\]]+?)\s*\]\s*(#.*)?$`)
+	tomlKey    = regexp.MustCompile(`^(\s*)([\w-]+)(\s*=\s*)(.*)$`)
+)
+
+// tomlDoc edits keys of a TOML file line by line, so comments and layout
+// survive. It only understands [table] headers and bare key = value lines.
+type tomlDoc struct {
+	lines []string
+	// newline records whether the file ended with a newline.
+	newline bool
+}
+
+func parseTOML(content string) *tomlDoc {
+	if content == "" {
+		return &tomlDoc{newline: true}
+	}
+	trimmed := strings.TrimSuffix(content, "\n")
+	return &tomlDoc{lines: strings.Split(trimmed, "\n"), newline: trimmed != content}
+}
+
+func (d *tomlDoc) String() string {
+	content := strings.Join(d.lines, "\n")
+	if d.newline {
+		content += "\n"
+	}
+	return content
+}
+
+// set replaces the key's value in the table, keeping a trailing comment, or
+// adds the key after the table's last key. A missing table is appended.
+func (d *tomlDoc) set(table, key, value string) {
+	line := key + " = " + quoteTOML(value)
+
+	start := -1
+	for i, l := range d.lines {
+		if match := tomlHeader.FindStringSubmatch(l); match != nil && match[1] == table {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		if len(d.lines) > 0 && strings.TrimSpace(d.lines[len(d.lines)-1]) != "" {
+			d.lines = append(d.lines, "")
+		}
+		d.lines = append(d.lines, "["+table+"]", line)
+		return
+	}
+
+	insertAt := start + 1
+	for i := start + 1; i < len(d.lines) && !tomlHeader.MatchString(d.lines[i]); i++ {
+		match := tomlKey.FindStringSubmatch(d.lines[i])
+		if match == nil {
+			continue
+		}
+		if match[2] == key {
+			d.lines[i] = match[1] + match[2] + match[3] + quoteTOML(value) + tomlComment(match[4])
+			return
+		}
+		insertAt = i + 1
+	}
+	d.lines = append(d.lines[:insertAt], append([]string{line}, d.lines[insertAt:]...)...)
+}
+
+func quoteTOML(value string) string {
+	return `"` + strings.ReplaceAll(value, `"`, `\"`) + `"`
+}
+
+// tomlComment returns the comment after a value, with its leading space.
+func tomlComment(value string) string {
+	rest := value
+	if strings.HasPrefix(rest, `"`) || strings.HasPrefix(rest, "'") {
+		if end := strings.IndexByte(rest[1:], rest[0]); end >= 0 {
+			rest = rest[end+2:]
+		}
+	}
+	if i := strings.IndexByte(rest, '#'); i >= 0 {
+		return " " + strings.TrimSpace(rest[i:])
+	}
+	return ""
+}
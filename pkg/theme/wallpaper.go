@@ -0,0 +1,245 @@
+package theme
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/saat-sy/hyprlander/pkg/color"
+	"github.com/saat-sy/hyprlander/pkg/hyprlang"
+)
+
+// HyprpaperConfigFileName is the hyprpaper config next to hyprland.conf.
+const HyprpaperConfigFileName = "hyprpaper.conf"
+
+// Minimum WCAG contrast of each role against the background. Text needs 7:1;
+// borders and other UI components need 3:1.
+const (
+	textContrast      = 7.0
+	componentContrast = 3.0
+	mutedContrast     = 3.0
+	surfaceContrast   = 1.3
+)
+
+// WallpaperPath reads the wallpaper shown on monitor from hyprpaper.conf in
+// hyprlandDir. Without a monitor it prefers the wallpaper set for all
+// monitors, then the first one set, then the first preloaded image.
+func WallpaperPath(hyprlandDir, monitor string) (string, error) {
+	path := filepath.Join(hyprlandDir, HyprpaperConfigFileName)
+	doc, err := hyprlang.ParseFile(path)
+	if err != nil {
+		return "", fmt.Errorf("could not read %s, pass the image instead: %w", path, err)
+	}
+
+	type wallpaper struct{ monitor, path string }
+	var wallpapers []wallpaper
+	var preloaded []string
+	var block *wallpaper
+	for _, line := range doc.Lines {
+		switch {
+		case line.Kind == hyprlang.BlockStart && line.Key == "wallpaper":
+			block = &wallpaper{}
+		case line.Kind == hyprlang.BlockEnd && block != nil:
+			wallpapers = append(wallpapers, *block)
+			block = nil
+		case line.Kind != hyprlang.Assignment:
+		case block != nil && line.Key == "monitor":
+			block.monitor = line.Value
+		case block != nil && line.Key == "path":
+			block.path = line.Value
+		case line.Key == "wallpaper":
+			name, file, _ := strings.Cut(line.Value, ",")
+			wallpapers = append(wallpapers, wallpaper{strings.TrimSpace(name), strings.TrimSpace(file)})
+		case line.Key == "preload":
+			preloaded = append(preloaded, line.Value)
+		}
+	}
+
+	var found string
+	for _, w := range wallpapers {
+		if w.path == "" {
+			continue
+		}
+		if monitor != "" {
+			if w.monitor == monitor {
+				found = w.path
+				break
+			}
+			continue
+		}
+		if w.monitor == "" {
+			found = w.path
+			break
+		}
+		if found == "" {
+			found = w.path
+		}
+	}
+	if found == "" && monitor == "" && len(preloaded) > 0 {
+		found = preloaded[0]
+	}
+	if found == "" {
+		if monitor != "" {
+			return "", fmt.Errorf("%s sets no wallpaper for monitor %s", path, monitor)
+		}
+		return "", fmt.Errorf("%s sets no wallpaper", path)
+	}
+	return expandPath(hyprlandDir, found), nil
+}
+
+// expandPath strips hyprpaper's fit mode prefix and resolves ~ and relative
+// paths.
+func expandPath(dir, path string) string {
+	for _, mode := range []string{"contain:", "cover:", "tile:", "fill:"} {
+		path = strings.TrimPrefix(path, mode)
+	}
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, rest)
+		}
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(dir, path)
+	}
+	return path
+}
+
+// FromImage derives a palette from an image file. variant is "dark",
+// "light" or "" to follow the image's overall lightness.
+func FromImage(path, variant string) (Palette, []Swatch, error) {
+	img, err := LoadImage(path)
+	if err != nil {
+		return Palette{}, nil, err
+	}
+	swatches := Extract(img, 8)
+	if len(swatches) == 0 {
+		return Palette{}, nil, fmt.Errorf("%s has no opaque pixels", path)
+	}
+	palette, err := FromSwatches(swatches, variant)
+	if err != nil {
+		return Palette{}, nil, err
+	}
+	palette.Title = "Wallpaper (" + filepath.Base(path) + ")"
+	return palette, swatches, nil
+}
+
+// FromSwatches assigns palette roles to the swatches of an image. The most
+// common color becomes the background, the most vivid ones the accents, and
+// every role is moved in lightness until it meets its contrast minimum.
+func FromSwatches(swatches []Swatch, variant string) (Palette, error) {
+	if variant == "" {
+		lightness := 0.0
+		for _, s := range swatches {
+			l, _, _ := s.Color.OKLCH()
+			lightness += l * s.Population
+		}
+		variant = "dark"
+		if lightness > 0.6 {
+			variant = "light"
+		}
+	}
+	if variant != "dark" && variant != "light" {
+		return Palette{}, fmt.Errorf("unknown variant %q, use dark or light", variant)
+	}
+	dark := variant == "dark"
+
+	// The background keeps the hue of the dominant color with little chroma.
+	bgL, bgC, bgH := swatches[0].Color.OKLCH()
+	bgC = min(bgC, 0.04)
+	if dark {
+		bgL = min(bgL, 0.24)
+	} else {
+		bgL = max(bgL, 0.94)
+	}
+	background := color.FromOKLCH(bgL, bgC, bgH, 1)
+
+	away := 1.0
+	if !dark {
+		away = -1.0
+	}
+	surface := ensureContrast(color.FromOKLCH(bgL+0.1*away, min(bgC*1.5, 0.05), bgH, 1), background, surfaceContrast, dark)
+	foreground := ensureContrast(color.FromOKLCH(bgL+0.7*away, 0.02, bgH, 1), background, textContrast, dark)
+	muted := ensureContrast(color.Mix(background, foreground, 0.5), background, mutedContrast, dark)
+
+	accentIndex := mostVivid(swatches, -1)
+	accent := vivid(swatches, accentIndex, bgH)
+	accentAlt := accent.RotateHue(40)
+	if altIndex := mostVivid(swatches, accentIndex); altIndex >= 0 {
+		accentAlt = vivid(swatches, altIndex, bgH)
+	}
+	accent = ensureContrast(accent, background, componentContrast, dark)
+	accentAlt = ensureContrast(accentAlt, background, componentContrast, dark)
+
+	accentL, accentC, _ := accent.OKLCH()
+	urgent := ensureContrast(color.FromOKLCH(accentL, max(accentC, 0.15), 25, 1), background, componentContrast, dark)
+
+	return Palette{
+		Name:       "wallpaper",
+		Title:      "Wallpaper",
+		Variant:    variant,
+		Background: background,
+		Surface:    surface,
+		Foreground: foreground,
+		Muted:      muted,
+		Accent:     accent,
+		AccentAlt:  accentAlt,
+		Urgent:     urgent,
+		Shadow:     background.Darken(0.6),
+	}, nil
+}
+
+// mostVivid returns the swatch with the highest chroma weighted by how much
+// of the image it covers. With other set, only swatches whose hue is at
+// least 30 degrees away from it are considered. It returns -1 if there is
+// none.
+func mostVivid(swatches []Swatch, other int) int {
+	best, bestScore := -1, 0.0
+	for i, s := range swatches {
+		_, chroma, hue := s.Color.OKLCH()
+		if i == other || chroma < 0.03 {
+			continue
+		}
+		if other >= 0 {
+			_, _, otherHue := swatches[other].Color.OKLCH()
+			if hueDistance(hue, otherHue) < 30 {
+				continue
+			}
+		}
+		if score := chroma * math.Sqrt(s.Population); score > bestScore {
+			best, bestScore = i, score
+		}
+	}
+	return best
+}
+
+// vivid returns the swatch with a minimum chroma, or a tint of hue if the
+// image has no colorful swatch at all.
+func vivid(swatches []Swatch, index int, hue float64) color.Color {
+	if index < 0 {
+		return color.FromOKLCH(0.72, 0.1, hue, 1)
+	}
+	l, c, h := swatches[index].Color.OKLCH()
+	return color.FromOKLCH(l, max(c, 0.1), h, 1)
+}
+
+func hueDistance(a, b float64) float64 {
+	d := math.Abs(a - b)
+	return min(d, 360-d)
+}
+
+// ensureContrast moves c away from the background in lightness, keeping its
+// hue, until it reaches ratio or cannot get any further.
+func ensureContrast(c, background color.Color, ratio float64, dark bool) color.Color {
+	lightness, chroma, hue := c.OKLCH()
+	step := 0.01
+	if !dark {
+		step = -0.01
+	}
+	for color.ContrastRatio(c, background) < ratio && lightness >= 0 && lightness <= 1 {
+		lightness += step
+		c = color.FromOKLCH(lightness, chroma, hue, 1)
+	}
+	return c
+}
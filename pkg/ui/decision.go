@@ -0,0 +1,64 @@
+package ui
+
+import "strings"
+
+// Decision is the user's answer to a tool or hunk confirmation.
+type Decision int
+
+const (
+	Reject Decision = iota
+	Approve
+	// Edit asks to change the proposed content before it is applied.
+	Edit
+	// Review asks to approve a write hunk by hunk.
+	Review
+	// ApproveRemaining and RejectRemaining answer the current hunk and every
+	// hunk after it.
+	ApproveRemaining
+	RejectRemaining
+	// ApproveSession allows every call of the same tool until the program exits.
+	ApproveSession
+	// ApproveAlways allows the call and persists a rule for similar calls.
+	ApproveAlways
+)
+
+type choice struct {
+	key   string
+	label string
+}
+
+var decisionChoices = map[Decision]choice{
+	Approve:          {"y", "yes"},
+	Reject:           {"n", "no"},
+	Edit:             {"e", "edit"},
+	Review:           {"p", "review hunk by hunk"},
+	ApproveRemaining: {"a", "apply this and all remaining hunks"},
+	RejectRemaining:  {"d", "skip this and all remaining hunks"},
+	ApproveSession:   {"s", "yes to all calls of this tool this session"},
+	ApproveAlways:    {"r", "always allow calls matching a pattern"},
+}
+
+var hunkChoices = []Decision{Approve, Reject, Edit, ApproveRemaining, RejectRemaining}
+
+func toolChoices(extra []Decision) []Decision {
+	return append([]Decision{Approve, Reject}, extra...)
+}
+
+func choiceKeys(choices []Decision) string {
+	keys := make([]string, len(choices))
+	for i, choice := range choices {
+		keys[i] = decisionChoices[choice].key
+	}
+	return strings.Join(keys, "/")
+}
+
+func parseChoice(input string, choices []Decision) (Decision, bool) {
+	input = strings.TrimSpace(strings.ToLower(input))
+	for _, choice := range choices {
+		c := decisionChoices[choice]
+		if input == c.key || (choice == Approve && input == "yes") || (choice == Reject && input == "no") {
+			return choice, true
+		}
+	}
+	return Reject, false
+}
@@ -0,0 +1,52 @@
+package ui
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// editorCommand builds the command for the user's preferred editor, taken from
+// $VISUAL or $EDITOR and falling back to vi. The variables may include flags.
+func editorCommand(path string) *exec.Cmd {
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	fields := strings.Fields(editor)
+	return exec.Command(fields[0], append(fields[1:], path)...)
+}
+
+// writeEditFile stores content in a temporary file named after name, so the
+// editor can pick syntax highlighting from the extension.
+func writeEditFile(name, content string) (string, error) {
+	pattern := "hyprlander-*-" + filepath.Base(name)
+	file, err := os.CreateTemp("", pattern)
+	if err != nil {
+		return "", fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(content); err != nil {
+		os.Remove(file.Name())
+		return "", fmt.Errorf("failed to write temporary file: %w", err)
+	}
+
+	return file.Name(), nil
+}
+
+func readEditFile(path string) (string, error) {
+	defer os.Remove(path)
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read edited file: %w", err)
+	}
+	return string(content), nil
+}
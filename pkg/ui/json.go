@@ -0,0 +1,265 @@
+package ui
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/saat-sy/hyprlander/pkg/core/tools"
+	"github.com/saat-sy/hyprlander/pkg/diff"
+	"github.com/saat-sy/hyprlander/pkg/secrets"
+)
+
+const (
+	EventAgentText       = "agent_text"
+	EventToolCall        = "tool_call"
+	EventToolResult      = "tool_result"
+	EventDiff            = "diff"
+	EventApprovalRequest = "approval_request"
+	EventInputRequest    = "input_request"
+	EventSelectRequest   = "select_request"
+	EventConclusion      = "conclusion"
+	EventUsage           = "usage"
+	EventMessage         = "message"
+	EventError           = "error"
+)
+
+// Event is one line of the JSON output stream.
+type Event struct {
+	Type    string                 `json:"type"`
+	Time    time.Time              `json:"time"`
+	Level   string                 `json:"level,omitempty"`
+	Message string                 `json:"message,omitempty"`
+	Tool    string                 `json:"tool,omitempty"`
+	Args    map[string]interface{} `json:"args,omitempty"`
+	Path    string                 `json:"path,omitempty"`
+	Diff    string                 `json:"diff,omitempty"`
+	Output  string                 `json:"output,omitempty"`
+	Options []string               `json:"options,omitempty"`
+	Usage   *Usage                 `json:"usage,omitempty"`
+}
+
+// JSONStream writes newline-delimited JSON events instead of formatted text.
+// Answers to approval, input and select requests are read one per line from
+// the input, either as plain text ("y", "2") or as {"answer": ...}.
+type JSONStream struct {
+	reader *bufio.Reader
+	out    io.Writer
+	mu     sync.Mutex
+}
+
+func NewJSON(in io.Reader, out io.Writer) UI {
+	return &JSONStream{
+		reader: bufio.NewReader(in),
+		out:    out,
+	}
+}
+
+func (j *JSONStream) emit(event Event) {
+	event.Time = time.Now().UTC()
+
+	encoded, err := json.Marshal(event)
+	if err != nil {
+		encoded, _ = json.Marshal(Event{Type: EventError, Time: event.Time, Message: err.Error()})
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	fmt.Fprintln(j.out, secrets.Redact(string(encoded)))
+}
+
+func (j *JSONStream) readAnswer() (string, error) {
+	line, err := j.reader.ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("failed to read input: %w", err)
+	}
+	line = strings.TrimSpace(line)
+
+	var answer struct {
+		Answer json.RawMessage `json:"answer"`
+	}
+	if strings.HasPrefix(line, "{") && json.Unmarshal([]byte(line), &answer) == nil && answer.Answer != nil {
+		var text string
+		if json.Unmarshal(answer.Answer, &text) == nil {
+			return text, nil
+		}
+		return strings.Trim(string(answer.Answer), `"`), nil
+	}
+
+	return line, nil
+}
+
+func (j *JSONStream) Input(prompt string) (string, error) {
+	j.emit(Event{Type: EventInputRequest, Message: prompt})
+	return j.readAnswer()
+}
+
+func (j *JSONStream) InputRequired(prompt string) (string, error) {
+	for {
+		input, err := j.Input(prompt)
+		if err != nil {
+			return "", err
+		}
+		if input != "" {
+			return input, nil
+		}
+		j.emit(Event{Type: EventMessage, Level: "warning", Message: "Input cannot be empty"})
+	}
+}
+
+func (j *JSONStream) InputSecret(prompt string) (string, error) {
+	value, err := j.InputRequired(prompt)
+	if err == nil {
+		secrets.Register(value)
+	}
+	return value, err
+}
+
+func (j *JSONStream) Confirm(prompt string) (bool, error) {
+	for {
+		j.emit(Event{Type: EventApprovalRequest, Message: prompt, Options: []string{"y", "n"}})
+		answer, err := j.readAnswer()
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(answer) {
+		case "y", "yes", "true":
+			return true, nil
+		case "n", "no", "false":
+			return false, nil
+		}
+		j.emit(Event{Type: EventMessage, Level: "warning", Message: "Please answer 'y' or 'n'"})
+	}
+}
+
+// ConfirmTool offers every choice except Edit, which needs a local editor.
+func (j *JSONStream) ConfirmTool(prompt string, extra ...Decision) (Decision, error) {
+	var choices []Decision
+	for _, choice := range toolChoices(extra) {
+		if choice != Edit {
+			choices = append(choices, choice)
+		}
+	}
+	return j.choose(Event{Type: EventApprovalRequest, Message: prompt}, choices)
+}
+
+func (j *JSONStream) ConfirmHunk(path string, hunk diff.Hunk, index, total int) (Decision, error) {
+	event := Event{
+		Type:    EventApprovalRequest,
+		Message: fmt.Sprintf("Apply hunk %d/%d?", index+1, total),
+		Path:    path,
+		Diff:    hunk.String(),
+	}
+	return j.choose(event, []Decision{Approve, Reject, ApproveRemaining, RejectRemaining})
+}
+
+func (j *JSONStream) choose(event Event, choices []Decision) (Decision, error) {
+	for _, choice := range choices {
+		event.Options = append(event.Options, decisionChoices[choice].key)
+	}
+
+	for {
+		j.emit(event)
+		answer, err := j.readAnswer()
+		if err != nil {
+			return Reject, err
+		}
+		if decision, ok := parseChoice(answer, choices); ok {
+			return decision, nil
+		}
+		j.emit(Event{Type: EventMessage, Level: "warning", Message: "Please answer with one of: " + choiceKeys(choices)})
+	}
+}
+
+// Edit is not supported on the JSON stream; the content is returned unchanged.
+func (j *JSONStream) Edit(name string, content string) (string, error) {
+	return content, nil
+}
+
+func (j *JSONStream) Select(prompt string, options []string) (int, error) {
+	for {
+		j.emit(Event{Type: EventSelectRequest, Message: prompt, Options: options})
+		answer, err := j.readAnswer()
+		if err != nil {
+			return -1, err
+		}
+		choice, err := strconv.Atoi(answer)
+		if err == nil && choice >= 1 && choice <= len(options) {
+			return choice - 1, nil
+		}
+		j.emit(Event{Type: EventMessage, Level: "warning", Message: fmt.Sprintf("Please enter a number between 1 and %d", len(options))})
+	}
+}
+
+func (j *JSONStream) Print(message string) {
+	j.emit(Event{Type: EventMessage, Level: "info", Message: message})
+}
+
+func (j *JSONStream) PrintAgent(message string) {
+	j.emit(Event{Type: EventAgentText, Message: message})
+}
+
+func (j *JSONStream) PrintConclusion(message string) {
+	j.emit(Event{Type: EventConclusion, Message: message})
+}
+
+func (j *JSONStream) PrintTool(toolName string, args map[string]interface{}) {
+	j.emit(Event{Type: EventToolCall, Tool: toolName, Args: args})
+}
+
+func (j *JSONStream) PrintReadTool(args map[string]interface{}) {
+	j.PrintTool("readFile", args)
+}
+
+func (j *JSONStream) PrintWriteTool(args map[string]interface{}) {
+	path, _ := args["path"].(string)
+	content, _ := args["content"].(string)
+
+	j.emit(Event{Type: EventToolCall, Tool: "writeFile", Path: path})
+
+	original, err := tools.ReadFile(path)
+	if err != nil {
+		original = ""
+	}
+	j.emit(Event{Type: EventDiff, Path: path, Diff: diff.Unified(path, original, content)})
+}
+
+func (j *JSONStream) PrintShellTool(args map[string]interface{}) {
+	j.PrintTool("shellExecute", args)
+}
+
+func (j *JSONStream) PrintToolResult(toolName string, output string) {
+	j.emit(Event{Type: EventToolResult, Tool: toolName, Output: output})
+}
+
+func (j *JSONStream) PrintUsage(usage Usage) {
+	j.emit(Event{Type: EventUsage, Usage: &usage})
+}
+
+func (j *JSONStream) PrintError(err error) {
+	j.emit(Event{Type: EventError, Message: err.Error()})
+}
+
+func (j *JSONStream) PrintSuccess(message string) {
+	j.emit(Event{Type: EventMessage, Level: "success", Message: message})
+}
+
+func (j *JSONStream) PrintWarning(message string) {
+	j.emit(Event{Type: EventMessage, Level: "warning", Message: message})
+}
+
+func (j *JSONStream) PrintTitle(title string) {
+	j.emit(Event{Type: EventMessage, Level: "title", Message: title})
+}
+
+func (j *JSONStream) PrintSeparator() {}
+
+func newJSONStdio() UI {
+	return NewJSON(os.Stdin, os.Stdout)
+}
@@ -0,0 +1,255 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/saat-sy/hyprlander/pkg/config"
+	"github.com/saat-sy/hyprlander/pkg/core/tools"
+	"github.com/saat-sy/hyprlander/pkg/diff"
+	"github.com/saat-sy/hyprlander/pkg/secrets"
+	"golang.org/x/term"
+)
+
+var ErrTUIClosed = errors.New("the terminal UI was closed")
+
+// TreeViewer is implemented by UIs that can show the Hyprland directory.
+type TreeViewer interface {
+	SetTree(root string, entries []config.TreeEntry)
+}
+
+// TUI is a full-screen implementation of UI. The bubbletea program runs in its
+// own goroutine; every UI call is forwarded to it as a message, and calls that
+// need an answer block until the user responds or the program exits.
+type TUI struct {
+	program *tea.Program
+	done    chan struct{}
+
+	mu        sync.Mutex
+	writePath string
+}
+
+// CanUseTUI reports whether both stdin and stdout are terminals.
+func CanUseTUI() bool {
+	return term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+func NewTUI() *TUI {
+	t := &TUI{done: make(chan struct{})}
+	t.program = tea.NewProgram(newTUIModel(), tea.WithAltScreen())
+
+	go func() {
+		defer close(t.done)
+		t.program.Run()
+	}()
+
+	return t
+}
+
+// Close waits for the user to dismiss the screen after the conversation ends.
+func (t *TUI) Close() {
+	t.send(finishedMsg{})
+	<-t.done
+}
+
+func (t *TUI) send(msg tea.Msg) {
+	select {
+	case <-t.done:
+	default:
+		t.program.Send(msg)
+	}
+}
+
+func (t *TUI) appendEntry(kind entryKind, text string) {
+	t.send(appendMsg{kind: kind, text: secrets.Redact(text)})
+}
+
+func wait[T any](t *TUI, reply chan T) (T, error) {
+	select {
+	case value := <-reply:
+		return value, nil
+	case <-t.done:
+		var zero T
+		return zero, ErrTUIClosed
+	}
+}
+
+func (t *TUI) SetTree(root string, entries []config.TreeEntry) {
+	if absRoot, err := filepath.Abs(root); err == nil {
+		root = absRoot
+	}
+	t.send(treeMsg{root: root, entries: entries})
+}
+
+func (t *TUI) Input(prompt string) (string, error) {
+	reply := make(chan string, 1)
+	t.send(inputRequestMsg{prompt: prompt, reply: reply})
+	return wait(t, reply)
+}
+
+func (t *TUI) InputRequired(prompt string) (string, error) {
+	for {
+		input, err := t.Input(prompt)
+		if err != nil || input != "" {
+			return input, err
+		}
+		t.appendEntry(entryWarning, "Input cannot be empty. Please try again.")
+	}
+}
+
+func (t *TUI) InputSecret(prompt string) (string, error) {
+	for {
+		reply := make(chan string, 1)
+		t.send(inputRequestMsg{prompt: prompt, secret: true, reply: reply})
+		input, err := wait(t, reply)
+		if err != nil {
+			return "", err
+		}
+		if input != "" {
+			secrets.Register(input)
+			return input, nil
+		}
+		t.appendEntry(entryWarning, "Input cannot be empty. Please try again.")
+	}
+}
+
+func (t *TUI) Confirm(prompt string) (bool, error) {
+	decision, err := t.ConfirmTool(prompt)
+	return decision == Approve, err
+}
+
+func (t *TUI) ConfirmTool(prompt string, extra ...Decision) (Decision, error) {
+	return t.choose(prompt, toolChoices(extra))
+}
+
+func (t *TUI) ConfirmHunk(path string, hunk diff.Hunk, index, total int) (Decision, error) {
+	t.send(pendingHunkMsg{
+		title: fmt.Sprintf("Hunk %d/%d of %s", index+1, total, path),
+		hunk:  hunk,
+	})
+	return t.choose("Apply this hunk?", hunkChoices)
+}
+
+func (t *TUI) choose(prompt string, choices []Decision) (Decision, error) {
+	reply := make(chan Decision, 1)
+	t.send(confirmRequestMsg{prompt: prompt, choices: choices, reply: reply})
+	decision, err := wait(t, reply)
+	if err != nil {
+		return Reject, err
+	}
+	return decision, nil
+}
+
+func (t *TUI) Edit(name string, content string) (string, error) {
+	path, err := writeEditFile(name, content)
+	if err != nil {
+		return "", err
+	}
+
+	reply := make(chan error, 1)
+	t.send(editRequestMsg{cmd: editorCommand(path), reply: reply})
+	editErr, err := wait(t, reply)
+	if err != nil {
+		os.Remove(path)
+		return "", err
+	}
+	if editErr != nil {
+		os.Remove(path)
+		return "", fmt.Errorf("editor exited with an error: %w", editErr)
+	}
+
+	return readEditFile(path)
+}
+
+func (t *TUI) Select(prompt string, options []string) (int, error) {
+	reply := make(chan int, 1)
+	t.send(selectRequestMsg{prompt: prompt, options: options, reply: reply})
+	choice, err := wait(t, reply)
+	if err != nil {
+		return -1, err
+	}
+	return choice, nil
+}
+
+func (t *TUI) Print(message string) {
+	t.appendEntry(entryInfo, message)
+}
+
+func (t *TUI) PrintAgent(message string) {
+	t.appendEntry(entryAgent, message)
+}
+
+func (t *TUI) PrintConclusion(message string) {
+	t.appendEntry(entryAgent, message)
+}
+
+func (t *TUI) PrintTool(toolName string, args map[string]interface{}) {
+	t.appendEntry(entryTool, fmt.Sprintf("%s %v", toolName, args))
+}
+
+func (t *TUI) PrintReadTool(args map[string]interface{}) {
+	path, _ := args["path"].(string)
+	t.appendEntry(entryTool, "Reading file: "+path)
+}
+
+func (t *TUI) PrintWriteTool(args map[string]interface{}) {
+	path, _ := args["path"].(string)
+	content, _ := args["content"].(string)
+
+	t.mu.Lock()
+	t.writePath = path
+	t.mu.Unlock()
+
+	original, err := tools.ReadFile(path)
+	if err != nil {
+		original = ""
+	}
+
+	t.appendEntry(entryTool, "Writing file: "+path)
+	t.send(pendingWriteMsg{
+		path:     path,
+		original: secrets.Redact(original),
+		proposed: secrets.Redact(content),
+	})
+}
+
+func (t *TUI) PrintShellTool(args map[string]interface{}) {
+	command, _ := args["command"].(string)
+	t.appendEntry(entryTool, "Executing command: "+command)
+}
+
+func (t *TUI) PrintToolResult(toolName string, output string) {
+	if toolName == "writeFile" {
+		t.mu.Lock()
+		path := t.writePath
+		t.mu.Unlock()
+		t.send(modifiedMsg{path: path})
+	}
+	t.appendEntry(entrySuccess, "Function successfully executed")
+}
+
+func (t *TUI) PrintUsage(usage Usage) {
+	t.appendEntry(entryInfo, fmt.Sprintf("Tokens: %d prompt, %d response, %d total", usage.PromptTokens, usage.ResponseTokens, usage.TotalTokens))
+}
+
+func (t *TUI) PrintError(err error) {
+	t.appendEntry(entryError, err.Error())
+}
+
+func (t *TUI) PrintSuccess(message string) {
+	t.appendEntry(entrySuccess, message)
+}
+
+func (t *TUI) PrintWarning(message string) {
+	t.appendEntry(entryWarning, message)
+}
+
+func (t *TUI) PrintTitle(title string) {
+	t.appendEntry(entryTitle, title)
+}
+
+func (t *TUI) PrintSeparator() {}
@@ -0,0 +1,503 @@
+package ui
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/saat-sy/hyprlander/pkg/config"
+	"github.com/saat-sy/hyprlander/pkg/diff"
+)
+
+type entryKind int
+
+const (
+	entryInfo entryKind = iota
+	entryAgent
+	entryTool
+	entrySuccess
+	entryWarning
+	entryError
+	entryTitle
+)
+
+type entry struct {
+	kind entryKind
+	text string
+}
+
+type (
+	appendMsg struct {
+		kind entryKind
+		text string
+	}
+	treeMsg struct {
+		root    string
+		entries []config.TreeEntry
+	}
+	modifiedMsg     struct{ path string }
+	pendingWriteMsg struct {
+		path     string
+		original string
+		proposed string
+	}
+	pendingHunkMsg struct {
+		title string
+		hunk  diff.Hunk
+	}
+	confirmRequestMsg struct {
+		prompt  string
+		choices []Decision
+		reply   chan Decision
+	}
+	inputRequestMsg struct {
+		prompt string
+		secret bool
+		reply  chan string
+	}
+	selectRequestMsg struct {
+		prompt  string
+		options []string
+		reply   chan int
+	}
+	editRequestMsg struct {
+		cmd   *exec.Cmd
+		reply chan error
+	}
+	editFinishedMsg struct{ err error }
+	finishedMsg     struct{}
+)
+
+type pane int
+
+const (
+	paneConversation pane = iota
+	paneDiff
+	paneFiles
+)
+
+var (
+	paneStyle       = lipgloss.NewStyle().Border(lipgloss.RoundedBorder()).BorderForeground(lipgloss.Color("8"))
+	activePaneStyle = paneStyle.BorderForeground(lipgloss.Color("6"))
+	titleStyle      = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("6"))
+	agentStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("4"))
+	toolStyle       = lipgloss.NewStyle().Foreground(lipgloss.Color("5"))
+	successStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+	warningStyle    = lipgloss.NewStyle().Foreground(lipgloss.Color("3"))
+	errorStyle      = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+	dimStyle        = lipgloss.NewStyle().Foreground(lipgloss.Color("8"))
+	deleteStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("1"))
+	insertStyle     = lipgloss.NewStyle().Foreground(lipgloss.Color("2"))
+	modifiedStyle   = lipgloss.NewStyle().Foreground(lipgloss.Color("3")).Bold(true)
+	keyStyle        = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("6"))
+)
+
+type tuiModel struct {
+	width  int
+	height int
+	focus  pane
+
+	entries      []entry
+	conversation viewport.Model
+
+	pending *pendingWriteMsg
+	hunk    *pendingHunkMsg
+	diff    viewport.Model
+
+	root     string
+	tree     []config.TreeEntry
+	modified map[string]bool
+	files    viewport.Model
+
+	confirm  *confirmRequestMsg
+	input    *inputRequestMsg
+	selector *selectRequestMsg
+	edit     *editRequestMsg
+	field    textinput.Model
+
+	finished bool
+}
+
+func newTUIModel() tuiModel {
+	return tuiModel{
+		modified: make(map[string]bool),
+		field:    textinput.New(),
+	}
+}
+
+func (m tuiModel) Init() tea.Cmd {
+	return nil
+}
+
+func (m tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width, m.height = msg.Width, msg.Height
+		m.layout()
+		return m, nil
+
+	case tea.KeyMsg:
+		return m.handleKey(msg)
+
+	case appendMsg:
+		m.entries = append(m.entries, entry{kind: msg.kind, text: msg.text})
+		m.refreshConversation()
+		return m, nil
+
+	case treeMsg:
+		m.root, m.tree = msg.root, msg.entries
+		m.refreshFiles()
+		return m, nil
+
+	case modifiedMsg:
+		m.modified[msg.path] = true
+		m.pending = nil
+		m.layout()
+		m.refreshFiles()
+		return m, nil
+
+	case pendingWriteMsg:
+		m.pending = &msg
+		m.hunk = nil
+		m.layout()
+		return m, nil
+
+	case pendingHunkMsg:
+		m.hunk = &msg
+		m.refreshDiff()
+		return m, nil
+
+	case confirmRequestMsg:
+		m.confirm = &msg
+		return m, nil
+
+	case inputRequestMsg:
+		m.input = &msg
+		m.field.Reset()
+		m.field.Prompt = "❯ "
+		m.field.EchoMode = textinput.EchoNormal
+		if msg.secret {
+			m.field.EchoMode = textinput.EchoPassword
+		}
+		return m, m.field.Focus()
+
+	case selectRequestMsg:
+		m.selector = &msg
+		return m, nil
+
+	case editRequestMsg:
+		m.edit = &msg
+		return m, tea.ExecProcess(msg.cmd, func(err error) tea.Msg { return editFinishedMsg{err: err} })
+
+	case editFinishedMsg:
+		if m.edit != nil {
+			m.edit.reply <- msg.err
+			m.edit = nil
+		}
+		return m, nil
+
+	case finishedMsg:
+		m.finished = true
+		return m, nil
+	}
+
+	return m, nil
+}
+
+func (m tuiModel) handleKey(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	if msg.String() == "ctrl+c" {
+		return m, tea.Quit
+	}
+
+	switch {
+	case m.input != nil:
+		if msg.Type == tea.KeyEnter {
+			m.input.reply <- strings.TrimSpace(m.field.Value())
+			m.input = nil
+			m.field.Blur()
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.field, cmd = m.field.Update(msg)
+		return m, cmd
+
+	case m.confirm != nil:
+		if decision, ok := parseChoice(msg.String(), m.confirm.choices); ok {
+			return m.answerConfirm(decision), nil
+		}
+
+	case m.selector != nil:
+		if choice, err := strconv.Atoi(msg.String()); err == nil && choice >= 1 && choice <= len(m.selector.options) {
+			m.selector.reply <- choice - 1
+			m.selector = nil
+			return m, nil
+		}
+
+	case m.finished:
+		if msg.String() == "q" || msg.Type == tea.KeyEnter || msg.Type == tea.KeyEsc {
+			return m, tea.Quit
+		}
+	}
+
+	switch msg.String() {
+	case "tab":
+		m.focus = m.nextPane()
+		return m, nil
+	}
+
+	var cmd tea.Cmd
+	switch m.focus {
+	case paneDiff:
+		m.diff, cmd = m.diff.Update(msg)
+	case paneFiles:
+		m.files, cmd = m.files.Update(msg)
+	default:
+		m.conversation, cmd = m.conversation.Update(msg)
+	}
+	return m, cmd
+}
+
+func (m tuiModel) answerConfirm(decision Decision) tuiModel {
+	m.confirm.reply <- decision
+	m.confirm = nil
+	if m.hunk != nil {
+		m.hunk = nil
+		m.refreshDiff()
+	} else if decision == Reject {
+		m.pending = nil
+		m.layout()
+	}
+	return m
+}
+
+func (m tuiModel) nextPane() pane {
+	next := (m.focus + 1) % 3
+	if next == paneDiff && m.pending == nil {
+		next = paneFiles
+	}
+	return next
+}
+
+func (m *tuiModel) layout() {
+	if m.width == 0 || m.height == 0 {
+		return
+	}
+
+	filesWidth := min(40, m.width/4)
+	mainWidth := m.width - filesWidth
+	bodyHeight := m.height - 3
+
+	// Each pane loses two rows and columns to its border and a row to its title.
+	m.files = resized(m.files, filesWidth-2, bodyHeight-3)
+
+	conversationHeight := bodyHeight
+	if m.pending != nil {
+		conversationHeight = bodyHeight / 2
+		m.diff = resized(m.diff, mainWidth-2, bodyHeight-conversationHeight-3)
+	}
+	m.conversation = resized(m.conversation, mainWidth-2, conversationHeight-3)
+
+	m.refreshConversation()
+	m.refreshFiles()
+	m.refreshDiff()
+}
+
+func resized(view viewport.Model, width, height int) viewport.Model {
+	if view.Width == 0 && view.Height == 0 {
+		return viewport.New(max(width, 1), max(height, 1))
+	}
+	view.Width, view.Height = max(width, 1), max(height, 1)
+	return view
+}
+
+func (m *tuiModel) refreshConversation() {
+	width := max(m.conversation.Width, 1)
+	var lines []string
+	for _, e := range m.entries {
+		lines = append(lines, renderEntry(e, width))
+	}
+	m.conversation.SetContent(strings.Join(lines, "\n"))
+	m.conversation.GotoBottom()
+}
+
+func renderEntry(e entry, width int) string {
+	wrap := lipgloss.NewStyle().Width(width)
+	switch e.kind {
+	case entryAgent:
+		return agentStyle.Bold(true).Render("🤖 Agent") + "\n" + wrap.Render(e.text) + "\n"
+	case entryTool:
+		return toolStyle.Render(wrap.Render("🔧 " + e.text))
+	case entrySuccess:
+		return successStyle.Render(wrap.Render("✅ " + e.text))
+	case entryWarning:
+		return warningStyle.Render(wrap.Render("⚠ " + e.text))
+	case entryError:
+		return errorStyle.Render(wrap.Render("❌ " + e.text))
+	case entryTitle:
+		return titleStyle.Render(wrap.Render("═══ " + e.text + " ═══"))
+	default:
+		return wrap.Render(e.text)
+	}
+}
+
+func (m *tuiModel) refreshFiles() {
+	var lines []string
+	for _, e := range m.tree {
+		name := e.Path
+		if rel, err := filepath.Rel(m.root, e.Path); err == nil {
+			name = rel
+		}
+		if m.modified[e.Path] {
+			lines = append(lines, modifiedStyle.Render("● "+name))
+		} else {
+			lines = append(lines, "  "+name)
+		}
+	}
+	var created []string
+	for path := range m.modified {
+		if !m.inTree(path) {
+			created = append(created, path)
+		}
+	}
+	sort.Strings(created)
+	for _, path := range created {
+		lines = append(lines, modifiedStyle.Render("+ "+path))
+	}
+	m.files.SetContent(strings.Join(lines, "\n"))
+}
+
+func (m *tuiModel) inTree(path string) bool {
+	for _, e := range m.tree {
+		if e.Path == path {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *tuiModel) refreshDiff() {
+	if m.pending == nil {
+		return
+	}
+	if m.hunk != nil {
+		m.diff.SetContent(sideBySideHunk(m.hunk.hunk, m.diff.Width))
+	} else {
+		m.diff.SetContent(sideBySide(m.pending.original, m.pending.proposed, m.diff.Width))
+	}
+	m.diff.GotoTop()
+}
+
+// sideBySide renders the old text on the left and the new text on the right,
+// pairing deleted lines with the insertions that replace them.
+func sideBySide(original, proposed string, width int) string {
+	return renderSideBySide(diff.Lines(original, proposed), width)
+}
+
+func sideBySideHunk(hunk diff.Hunk, width int) string {
+	return titleStyle.Render(hunk.Header()) + "\n" + renderSideBySide(hunk.Ops, width)
+}
+
+func renderSideBySide(ops []diff.Op, width int) string {
+	column := max((width-3)/2, 1)
+	cell := func(text string, style *lipgloss.Style) string {
+		text = ansi.Truncate(strings.ReplaceAll(text, "\t", "    "), column, "…")
+		text += strings.Repeat(" ", max(column-ansi.StringWidth(text), 0))
+		if style != nil {
+			return style.Render(text)
+		}
+		return text
+	}
+
+	var rows []string
+	for i := 0; i < len(ops); {
+		if ops[i].Kind == diff.Equal {
+			rows = append(rows, cell(ops[i].Line, &dimStyle)+" │ "+cell(ops[i].Line, &dimStyle))
+			i++
+			continue
+		}
+
+		var deleted, inserted []string
+		for ; i < len(ops) && ops[i].Kind == diff.Delete; i++ {
+			deleted = append(deleted, ops[i].Line)
+		}
+		for ; i < len(ops) && ops[i].Kind == diff.Insert; i++ {
+			inserted = append(inserted, ops[i].Line)
+		}
+		for j := 0; j < max(len(deleted), len(inserted)); j++ {
+			left, right := cell("", nil), cell("", nil)
+			if j < len(deleted) {
+				left = cell(deleted[j], &deleteStyle)
+			}
+			if j < len(inserted) {
+				right = cell(inserted[j], &insertStyle)
+			}
+			rows = append(rows, left+" │ "+right)
+		}
+	}
+
+	return strings.Join(rows, "\n")
+}
+
+func (m tuiModel) View() string {
+	if m.width == 0 {
+		return "Starting..."
+	}
+
+	style := func(p pane) lipgloss.Style {
+		if m.focus == p {
+			return activePaneStyle
+		}
+		return paneStyle
+	}
+
+	files := style(paneFiles).Render(titled("Files", m.files.View(), m.files.Width))
+	main := style(paneConversation).Render(titled("Conversation", m.conversation.View(), m.conversation.Width))
+	if m.pending != nil {
+		title := "Pending write: " + m.pending.path
+		if m.hunk != nil {
+			title = m.hunk.title
+		}
+		diffPane := style(paneDiff).Render(titled(title, m.diff.View(), m.diff.Width))
+		main = lipgloss.JoinVertical(lipgloss.Left, main, diffPane)
+	}
+
+	body := lipgloss.JoinHorizontal(lipgloss.Top, files, main)
+	return lipgloss.JoinVertical(lipgloss.Left, body, m.statusLine())
+}
+
+func titled(title, content string, width int) string {
+	return titleStyle.Render(ansi.Truncate(title, width, "…")) + "\n" + content
+}
+
+func (m tuiModel) statusLine() string {
+	key := func(k, label string) string { return keyStyle.Render(k) + " " + label }
+
+	switch {
+	case m.input != nil:
+		return m.input.prompt + "\n" + m.field.View()
+	case m.confirm != nil:
+		var keys []string
+		for _, choice := range m.confirm.choices {
+			keys = append(keys, key(decisionChoices[choice].key, decisionChoices[choice].label))
+		}
+		return m.confirm.prompt + "  " + strings.Join(keys, " · ")
+	case m.selector != nil:
+		var options []string
+		for i, option := range m.selector.options {
+			options = append(options, key(strconv.Itoa(i+1), option))
+		}
+		return m.selector.prompt + "  " + strings.Join(options, " · ")
+	case m.finished:
+		return "Done. " + key("q", "quit")
+	default:
+		return dimStyle.Render(fmt.Sprintf("Working...  %s · %s · %s", key("tab", "switch pane"), key("↑/↓", "scroll"), key("ctrl+c", "abort")))
+	}
+}
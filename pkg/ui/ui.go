@@ -3,11 +3,16 @@ package ui
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
 
 	"github.com/saat-sy/hyprlander/pkg/core/tools"
+	"github.com/saat-sy/hyprlander/pkg/diff"
+	"github.com/saat-sy/hyprlander/pkg/secrets"
+	"golang.org/x/term"
 )
 
 const (
@@ -31,15 +36,22 @@ const (
 type UI interface {
 	Input(prompt string) (string, error)
 	InputRequired(prompt string) (string, error)
+	InputSecret(prompt string) (string, error)
 	Confirm(prompt string) (bool, error)
+	ConfirmTool(prompt string, extra ...Decision) (Decision, error)
+	ConfirmHunk(path string, hunk diff.Hunk, index, total int) (Decision, error)
+	Edit(name string, content string) (string, error)
 	Select(prompt string, options []string) (int, error)
 
 	Print(message string)
 	PrintAgent(message string)
+	PrintConclusion(message string)
 	PrintTool(toolName string, args map[string]interface{})
 	PrintReadTool(args map[string]interface{})
 	PrintWriteTool(args map[string]interface{})
 	PrintShellTool(args map[string]interface{})
+	PrintToolResult(toolName string, output string)
+	PrintUsage(usage Usage)
 	PrintError(err error)
 	PrintSuccess(message string)
 	PrintWarning(message string)
@@ -47,24 +59,81 @@ type UI interface {
 	PrintSeparator()
 }
 
+const (
+	OutputText = "text"
+	OutputJSON = "json"
+)
+
+type Options struct {
+	Color    bool
+	ShowDiff bool
+	Output   string
+}
+
+// Usage is the token count reported by the model provider for a conversation.
+type Usage struct {
+	PromptTokens   int32 `json:"prompt_tokens"`
+	ResponseTokens int32 `json:"response_tokens"`
+	TotalTokens    int32 `json:"total_tokens"`
+}
+
+var defaultOptions = Options{
+	Color:    true,
+	ShowDiff: true,
+	Output:   OutputText,
+}
+
+// Configure sets the options used by every UI created afterwards with New.
+func Configure(options Options) {
+	defaultOptions = options
+}
+
 type Console struct {
-	reader *bufio.Reader
+	reader  *bufio.Reader
+	out     io.Writer
+	options Options
 }
 
 func New() UI {
+	if defaultOptions.Output == OutputJSON {
+		return newJSONStdio()
+	}
+
 	return &Console{
-		reader: bufio.NewReader(os.Stdin),
+		reader:  bufio.NewReader(os.Stdin),
+		out:     &filterWriter{out: os.Stdout, color: defaultOptions.Color},
+		options: defaultOptions,
+	}
+}
+
+var ansiEscape = regexp.MustCompile("\033\\[[0-9;]*m")
+
+// filterWriter masks registered secrets and, when colour is disabled, strips
+// ANSI escapes from everything the console prints.
+type filterWriter struct {
+	out   io.Writer
+	color bool
+}
+
+func (w *filterWriter) Write(p []byte) (int, error) {
+	text := secrets.Redact(string(p))
+	if !w.color {
+		text = ansiEscape.ReplaceAllString(text, "")
+	}
+	if _, err := io.WriteString(w.out, text); err != nil {
+		return 0, err
 	}
+	return len(p), nil
 }
 
 func (c *Console) Input(prompt string) (string, error) {
-	fmt.Printf("%s%s❯%s ", Cyan, Bold, Reset)
-	fmt.Print(prompt)
+	fmt.Fprintf(c.out, "%s%s❯%s ", Cyan, Bold, Reset)
+	fmt.Fprint(c.out, prompt)
 	input, err := c.reader.ReadString('\n')
 	if err != nil {
 		return "", fmt.Errorf("failed to read input: %w", err)
 	}
-	fmt.Println("")
+	fmt.Fprintln(c.out, "")
 	return strings.TrimSpace(input), nil
 }
 
@@ -77,13 +146,35 @@ func (c *Console) InputRequired(prompt string) (string, error) {
 		if input != "" {
 			return input, nil
 		}
-		fmt.Printf("%s%s⚠ Input cannot be empty. Please try again.%s\n", Yellow, Bold, Reset)
+		fmt.Fprintf(c.out, "%s%s⚠ Input cannot be empty. Please try again.%s\n", Yellow, Bold, Reset)
+	}
+}
+
+// InputSecret reads a required value without echoing it when stdin is a terminal.
+func (c *Console) InputSecret(prompt string) (string, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		return c.InputRequired(prompt)
+	}
+
+	for {
+		fmt.Fprintf(c.out, "%s%s❯%s ", Cyan, Bold, Reset)
+		fmt.Fprint(c.out, prompt)
+		input, err := term.ReadPassword(fd)
+		fmt.Fprintln(c.out)
+		if err != nil {
+			return "", fmt.Errorf("failed to read input: %w", err)
+		}
+		if value := strings.TrimSpace(string(input)); value != "" {
+			return value, nil
+		}
+		fmt.Fprintf(c.out, "%s%s⚠ Input cannot be empty. Please try again.%s\n", Yellow, Bold, Reset)
 	}
 }
 
 func (c *Console) Confirm(prompt string) (bool, error) {
 	for {
-		fmt.Printf("%s%s❯%s %s %s[y/n]%s: ", Cyan, Bold, Reset, prompt, Gray, Reset)
+		fmt.Fprintf(c.out, "%s%s❯%s %s %s[y/n]%s: ", Cyan, Bold, Reset, prompt, Gray, Reset)
 		input, err := c.reader.ReadString('\n')
 		if err != nil {
 			return false, fmt.Errorf("failed to read input: %w", err)
@@ -96,22 +187,85 @@ func (c *Console) Confirm(prompt string) (bool, error) {
 		case "n", "no":
 			return false, nil
 		default:
-			fmt.Printf("%s%s⚠ Please enter 'y' or 'n'%s\n", Yellow, Bold, Reset)
+			fmt.Fprintf(c.out, "%s%s⚠ Please enter 'y' or 'n'%s\n", Yellow, Bold, Reset)
+		}
+	}
+}
+
+// ConfirmTool asks whether a tool call may run. Besides yes and no, the
+// user may pick any of the extra decisions.
+func (c *Console) ConfirmTool(prompt string, extra ...Decision) (Decision, error) {
+	return c.choose(prompt, toolChoices(extra))
+}
+
+func (c *Console) ConfirmHunk(path string, hunk diff.Hunk, index, total int) (Decision, error) {
+	fmt.Fprintf(c.out, "\n%s%sHunk %d/%d of %s%s\n", Cyan, Bold, index+1, total, path, Reset)
+	fmt.Fprintln(c.out, colorizeHunk(hunk))
+	return c.choose("Apply this hunk?", hunkChoices)
+}
+
+func (c *Console) choose(prompt string, choices []Decision) (Decision, error) {
+	for {
+		fmt.Fprintf(c.out, "%s%s❯%s %s %s[%s]%s: ", Cyan, Bold, Reset, prompt, Gray, choiceKeys(choices), Reset)
+		input, err := c.reader.ReadString('\n')
+		if err != nil {
+			return Reject, fmt.Errorf("failed to read input: %w", err)
+		}
+
+		if decision, ok := parseChoice(input, choices); ok {
+			return decision, nil
+		}
+
+		fmt.Fprintf(c.out, "%s%s⚠ Please enter one of:%s\n", Yellow, Bold, Reset)
+		for _, choice := range choices {
+			fmt.Fprintf(c.out, "  %s%s%s - %s\n", Cyan, decisionChoices[choice].key, Reset, decisionChoices[choice].label)
+		}
+	}
+}
+
+func colorizeHunk(hunk diff.Hunk) string {
+	lines := []string{fmt.Sprintf("%s%s%s", Cyan, hunk.Header(), Reset)}
+	for _, op := range hunk.Ops {
+		switch op.Kind {
+		case diff.Delete:
+			lines = append(lines, fmt.Sprintf("%s-%s%s", Red, op.Line, Reset))
+		case diff.Insert:
+			lines = append(lines, fmt.Sprintf("%s+%s%s", Green, op.Line, Reset))
+		default:
+			lines = append(lines, " "+op.Line)
 		}
 	}
+	return strings.Join(lines, "\n")
+}
+
+// Edit opens content in the user's editor and returns the saved result.
+func (c *Console) Edit(name string, content string) (string, error) {
+	path, err := writeEditFile(name, content)
+	if err != nil {
+		return "", err
+	}
+
+	cmd := editorCommand(path)
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		os.Remove(path)
+		return "", fmt.Errorf("editor exited with an error: %w", err)
+	}
+
+	return readEditFile(path)
 }
 
 func (c *Console) Select(prompt string, options []string) (int, error) {
 	c.PrintTitle(prompt)
-	fmt.Println()
+	fmt.Fprintln(c.out)
 
 	for i, option := range options {
-		fmt.Printf("  %s%s%d)%s %s\n", Cyan, Bold, i+1, Reset, option)
+		fmt.Fprintf(c.out, "  %s%s%d)%s %s\n", Cyan, Bold, i+1, Reset, option)
 	}
-	fmt.Println()
+	fmt.Fprintln(c.out)
 
 	for {
-		fmt.Printf("%s%s❯%s Select an option %s[1-%d]%s: ", Cyan, Bold, Reset, Gray, len(options), Reset)
+		fmt.Fprintf(c.out, "%s%s❯%s Select an option %s[1-%d]%s: ", Cyan, Bold, Reset, Gray, len(options), Reset)
 		input, err := c.reader.ReadString('\n')
 		if err != nil {
 			return -1, fmt.Errorf("failed to read input: %w", err)
@@ -120,12 +274,12 @@ func (c *Console) Select(prompt string, options []string) (int, error) {
 		input = strings.TrimSpace(input)
 		choice, err := strconv.Atoi(input)
 		if err != nil {
-			fmt.Printf("%s%s⚠ Please enter a valid number%s\n", Yellow, Bold, Reset)
+			fmt.Fprintf(c.out, "%s%s⚠ Please enter a valid number%s\n", Yellow, Bold, Reset)
 			continue
 		}
 
 		if choice < 1 || choice > len(options) {
-			fmt.Printf("%s%s⚠ Please enter a number between 1 and %d%s\n", Yellow, Bold, len(options), Reset)
+			fmt.Fprintf(c.out, "%s%s⚠ Please enter a number between 1 and %d%s\n", Yellow, Bold, len(options), Reset)
 			continue
 		}
 
@@ -134,86 +288,101 @@ func (c *Console) Select(prompt string, options []string) (int, error) {
 }
 
 func (c *Console) Print(message string) {
-	fmt.Printf("  %s\n", message)
+	fmt.Fprintf(c.out, "  %s\n", message)
 }
 
 func (c *Console) PrintAgent(message string) {
-	fmt.Printf("\n%s%s🤖 Agent:%s\n", Blue, Bold, Reset)
-	fmt.Printf(" %s❯%s %s\n\n", Blue, Reset, message)
+	fmt.Fprintf(c.out, "\n%s%s🤖 Agent:%s\n", Blue, Bold, Reset)
+	fmt.Fprintf(c.out, " %s❯%s %s\n\n", Blue, Reset, message)
+}
+
+func (c *Console) PrintConclusion(message string) {
+	c.PrintAgent(message)
 }
 
 func (c *Console) PrintTool(toolName string, args map[string]interface{}) {
-	fmt.Printf("%s%s🔧 Tool:%s %s%s%s", Magenta, Bold, Reset, Cyan, toolName, Reset)
+	fmt.Fprintf(c.out, "%s%s🔧 Tool:%s %s%s%s", Magenta, Bold, Reset, Cyan, toolName, Reset)
 	if len(args) > 0 {
-		fmt.Printf("%s%s%v%s", Gray, Dim, args, Reset)
+		fmt.Fprintf(c.out, "%s%s%v%s", Gray, Dim, args, Reset)
 	}
-	fmt.Println()
+	fmt.Fprintln(c.out)
 }
 
 func (c *Console) PrintReadTool(args map[string]interface{}) {
-	fmt.Printf("%s%s🔧 Reading File:%s", Blue, Bold, Reset)
+	fmt.Fprintf(c.out, "%s%s🔧 Reading File:%s", Blue, Bold, Reset)
 	if path, ok := args["path"].(string); ok {
-		fmt.Printf("%s%s%s", Cyan, path, Reset)
+		fmt.Fprintf(c.out, "%s%s%s", Cyan, path, Reset)
 	}
 	if len(args) > 1 {
-		fmt.Printf("%s%s %v%s", Gray, Dim, args, Reset)
+		fmt.Fprintf(c.out, "%s%s %v%s", Gray, Dim, args, Reset)
 	}
-	fmt.Println()
+	fmt.Fprintln(c.out)
 }
 
 func (c *Console) PrintWriteTool(args map[string]interface{}) {
-	fmt.Printf("%s%s🔧 Writing File:%s", Green, Bold, Reset)
+	fmt.Fprintf(c.out, "%s%s🔧 Writing File:%s", Green, Bold, Reset)
 	if path, ok := args["path"].(string); ok {
-		fmt.Printf("%s%s%s", Cyan, path, Reset)
+		fmt.Fprintf(c.out, "%s%s%s", Cyan, path, Reset)
+	}
+	fmt.Fprintln(c.out)
+	if !c.options.ShowDiff {
+		return
 	}
-	fmt.Println()
 	if content, ok := args["content"].(string); ok {
 		if path, ok := args["path"].(string); ok {
 			c.printDiff(path, content)
 		} else {
-			fmt.Printf("%s%sContent:%s\n%s", Gray, Dim, Reset, content)
+			fmt.Fprintf(c.out, "%s%sContent:%s\n%s", Gray, Dim, Reset, content)
 		}
 	} else if len(args) > 1 {
-		fmt.Printf("%s%s %v%s", Gray, Dim, args, Reset)
+		fmt.Fprintf(c.out, "%s%s %v%s", Gray, Dim, args, Reset)
 	}
-	fmt.Println()
+	fmt.Fprintln(c.out)
 }
 
 func (c *Console) PrintShellTool(args map[string]interface{}) {
-	fmt.Printf("%s%s🔧 Executing Command:%s\n", Yellow, Bold, Reset)
+	fmt.Fprintf(c.out, "%s%s🔧 Executing Command:%s\n", Yellow, Bold, Reset)
 	if command, ok := args["command"].(string); ok {
-		fmt.Printf("%s%s❯❯ %s%s%s\n", BgRed, White, command, Reset, Reset)
+		fmt.Fprintf(c.out, "%s%s❯❯ %s%s%s\n", BgRed, White, command, Reset, Reset)
 	}
 	if len(args) > 1 {
-		fmt.Printf("%s%s  %v%s", Gray, Dim, args, Reset)
-		fmt.Println()
+		fmt.Fprintf(c.out, "%s%s  %v%s", Gray, Dim, args, Reset)
+		fmt.Fprintln(c.out)
 	}
 }
 
+func (c *Console) PrintToolResult(toolName string, output string) {
+	c.PrintSuccess("Function successfully executed")
+}
+
+func (c *Console) PrintUsage(usage Usage) {
+	fmt.Fprintf(c.out, "%s%sTokens: %d prompt, %d response, %d total%s\n", Gray, Dim, usage.PromptTokens, usage.ResponseTokens, usage.TotalTokens, Reset)
+}
+
 func (c *Console) PrintError(err error) {
-	fmt.Printf("\n%s%s❌ Error:%s %s\n\n", Red, Bold, Reset, err.Error())
+	fmt.Fprintf(c.out, "\n%s%s❌ Error:%s %s\n\n", Red, Bold, Reset, err.Error())
 }
 
 func (c *Console) PrintSuccess(message string) {
-	fmt.Printf("\n%s%s✅ %s%s\n\n", Green, Bold, message, Reset)
+	fmt.Fprintf(c.out, "\n%s%s✅ %s%s\n\n", Green, Bold, message, Reset)
 }
 
 func (c *Console) PrintWarning(message string) {
-	fmt.Printf("\n%s%s⚠ Warning:%s %s\n\n", Yellow, Bold, Reset, message)
+	fmt.Fprintf(c.out, "\n%s%s⚠ Warning:%s %s\n\n", Yellow, Bold, Reset, message)
 }
 
 func (c *Console) PrintTitle(title string) {
-	fmt.Printf("\n%s%s═══ %s ═══%s\n", Cyan, Bold, title, Reset)
+	fmt.Fprintf(c.out, "\n%s%s═══ %s ═══%s\n", Cyan, Bold, title, Reset)
 }
 
 func (c *Console) PrintSeparator() {
-	fmt.Printf("%s%s────────────────────────────────────────%s\n", Gray, Dim, Reset)
+	fmt.Fprintf(c.out, "%s%s────────────────────────────────────────%s\n", Gray, Dim, Reset)
 }
 
 func (c *Console) printDiff(path, content string) {
 	originalContent, err := tools.ReadFile(path)
 	if err != nil {
-		fmt.Printf("%s%sContent:%s\n%s\n", Gray, Dim, Reset, content)
+		fmt.Fprintf(c.out, "%s%sContent:%s\n%s\n", Gray, Dim, Reset, content)
 		return
 	}
 
@@ -251,5 +420,5 @@ func (c *Console) printDiff(path, content string) {
 		}
 	}
 
-	fmt.Printf("%s%sDiff:%s\n%s\n", Gray, Dim, Reset, strings.Join(diffLines, "\n"))
+	fmt.Fprintf(c.out, "%s%sDiff:%s\n%s\n", Gray, Dim, Reset, strings.Join(diffLines, "\n"))
 }
@@ -0,0 +1,339 @@
+// Package waybar edits waybar's JSONC config: options of the bars and their
+// modules, and the modules-left, modules-center and modules-right lists.
+// Comments and layout are kept, and every edit is checked before it is
+// written.
+package waybar
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/saat-sy/hyprlander/pkg/jsonc"
+)
+
+// Sections are the module lists of a bar.
+var Sections = []string{"modules-left", "modules-center", "modules-right"}
+
+// ConfigFileNames are the files waybar reads its config from, in the order
+// it looks for them.
+var ConfigFileNames = []string{"config.jsonc", "config"}
+
+// FindConfig returns the config file waybar reads in dir.
+func FindConfig(dir string) (string, error) {
+	for _, name := range ConfigFileNames {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path, nil
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return "", err
+		}
+	}
+	return "", fmt.Errorf("no waybar config found in %s; set app_dirs.waybar if it lives elsewhere", dir)
+}
+
+// Config is a waybar config: a single bar, or an array of bars.
+type Config struct {
+	doc *jsonc.Document
+}
+
+func Parse(content string) (*Config, error) {
+	doc, err := jsonc.Parse(content)
+	if err != nil {
+		return nil, err
+	}
+	c := &Config{doc: doc}
+	if _, err := c.bars(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (c *Config) String() string {
+	return c.doc.String()
+}
+
+// bars decodes each bar of the config.
+func (c *Config) bars() ([]map[string]any, error) {
+	root, _ := c.doc.Get(nil)
+	switch root := root.(type) {
+	case map[string]any:
+		return []map[string]any{root}, nil
+	case []any:
+		bars := make([]map[string]any, len(root))
+		for i, item := range root {
+			bar, ok := item.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("bar %d is not an object", i)
+			}
+			bars[i] = bar
+		}
+		return bars, nil
+	}
+	return nil, fmt.Errorf("the config must be an object, or an array of objects for several bars")
+}
+
+// path prefixes path with the bar's index when the config has several bars.
+func (c *Config) path(bar int, path []string) ([]string, error) {
+	root, _ := c.doc.Get(nil)
+	bars, several := root.([]any)
+	count := 1
+	if several {
+		count = len(bars)
+	}
+	if bar < 0 || bar >= count {
+		return nil, fmt.Errorf("there is no bar %d, the config has %d", bar, count)
+	}
+	if several {
+		return append([]string{strconv.Itoa(bar)}, path...), nil
+	}
+	return path, nil
+}
+
+// Get decodes an option of a bar.
+func (c *Config) Get(bar int, path []string) (any, bool) {
+	full, err := c.path(bar, path)
+	if err != nil {
+		return nil, false
+	}
+	return c.doc.Get(full)
+}
+
+// Set sets an option of a bar, such as ["height"] or ["clock", "format"].
+func (c *Config) Set(bar int, path []string, value any) error {
+	if len(path) == 0 {
+		return fmt.Errorf("no option given")
+	}
+	full, err := c.path(bar, path)
+	if err != nil {
+		return err
+	}
+	return c.doc.Set(full, value)
+}
+
+// Delete removes an option of a bar.
+func (c *Config) Delete(bar int, path []string) error {
+	full, err := c.path(bar, path)
+	if err != nil {
+		return err
+	}
+	return c.doc.Delete(full)
+}
+
+// Modules returns the module list of a section of a bar.
+func (c *Config) Modules(bar int, section string) ([]string, error) {
+	if err := checkSection(section); err != nil {
+		return nil, err
+	}
+	full, err := c.path(bar, []string{section})
+	if err != nil {
+		return nil, err
+	}
+	value, ok := c.doc.Get(full)
+	if !ok {
+		return nil, nil
+	}
+	return moduleList(section, value)
+}
+
+// AddModule inserts module into a section of a bar at position, or at the
+// end when position is -1.
+func (c *Config) AddModule(bar int, section, module string, position int) error {
+	modules, err := c.Modules(bar, section)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(modules, module) {
+		return fmt.Errorf("%s is already in %s", module, section)
+	}
+
+	full, err := c.path(bar, []string{section})
+	if err != nil {
+		return err
+	}
+	if _, ok := c.doc.Get(full); !ok {
+		if position > 0 {
+			return fmt.Errorf("%s is empty, position %d is past its end", section, position)
+		}
+		return c.doc.Set(full, []any{module})
+	}
+	return c.doc.Insert(full, position, module)
+}
+
+// RemoveModule removes module from a section of a bar, or from every section
+// when section is empty, and returns the sections it was removed from.
+func (c *Config) RemoveModule(bar int, section, module string) ([]string, error) {
+	sections := Sections
+	if section != "" {
+		sections = []string{section}
+	}
+
+	var removed []string
+	for _, s := range sections {
+		modules, err := c.Modules(bar, s)
+		if err != nil {
+			return nil, err
+		}
+		for i := len(modules) - 1; i >= 0; i-- {
+			if modules[i] != module {
+				continue
+			}
+			full, err := c.path(bar, []string{s, strconv.Itoa(i)})
+			if err != nil {
+				return nil, err
+			}
+			if err := c.doc.Delete(full); err != nil {
+				return nil, err
+			}
+			if !slices.Contains(removed, s) {
+				removed = append(removed, s)
+			}
+		}
+	}
+	if len(removed) == 0 {
+		return nil, fmt.Errorf("%s is not in %s", module, strings.Join(sections, ", "))
+	}
+	return removed, nil
+}
+
+// Validate checks what waybar would refuse to start with: module lists that
+// are not lists of names and top-level options of the wrong type.
+func (c *Config) Validate() error {
+	bars, err := c.bars()
+	if err != nil {
+		return err
+	}
+	for i, bar := range bars {
+		for _, section := range Sections {
+			if value, ok := bar[section]; ok {
+				if _, err := moduleList(section, value); err != nil {
+					return barError(len(bars), i, err)
+				}
+			}
+		}
+		for _, key := range slices.Sorted(maps.Keys(barOptions)) {
+			if value, ok := bar[key]; ok {
+				if err := barOptions[key](value); err != nil {
+					return barError(len(bars), i, fmt.Errorf("%s: %w", key, err))
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// Unconfigured lists the custom modules of a bar that have no config
+// object, which waybar cannot show.
+func (c *Config) Unconfigured(bar int) []string {
+	var missing []string
+	for _, section := range Sections {
+		modules, _ := c.Modules(bar, section)
+		for _, module := range modules {
+			if !strings.HasPrefix(module, "custom/") {
+				continue
+			}
+			if _, ok := c.Get(bar, []string{module}); !ok && !slices.Contains(missing, module) {
+				missing = append(missing, module)
+			}
+		}
+	}
+	return missing
+}
+
+func barError(count, bar int, err error) error {
+	if count == 1 {
+		return err
+	}
+	return fmt.Errorf("bar %d: %w", bar, err)
+}
+
+func checkSection(section string) error {
+	if !slices.Contains(Sections, section) {
+		return fmt.Errorf("unknown section %q, use %s", section, strings.Join(Sections, ", "))
+	}
+	return nil
+}
+
+func moduleList(section string, value any) ([]string, error) {
+	items, ok := value.([]any)
+	if !ok {
+		return nil, fmt.Errorf("%s must be a list of module names", section)
+	}
+	modules := make([]string, len(items))
+	for i, item := range items {
+		name, ok := item.(string)
+		if !ok {
+			return nil, fmt.Errorf("%s must be a list of module names, item %d is not a string", section, i)
+		}
+		modules[i] = name
+	}
+	return modules, nil
+}
+
+var barOptions = map[string]func(any) error{
+	"layer":    oneOf("top", "bottom", "overlay", "background"),
+	"position": oneOf("top", "bottom", "left", "right"),
+	"height":   isNumber,
+	"width":    isNumber,
+	"spacing":  isNumber,
+	"output":   isStringOrList,
+}
+
+func oneOf(values ...string) func(any) error {
+	return func(value any) error {
+		if s, ok := value.(string); !ok || !slices.Contains(values, s) {
+			return fmt.Errorf("must be one of %s", strings.Join(values, ", "))
+		}
+		return nil
+	}
+}
+
+func isNumber(value any) error {
+	if _, ok := value.(float64); !ok {
+		return fmt.Errorf("must be a number")
+	}
+	return nil
+}
+
+func isStringOrList(value any) error {
+	switch value := value.(type) {
+	case string:
+		return nil
+	case []any:
+		for _, item := range value {
+			if _, ok := item.(string); !ok {
+				return fmt.Errorf("must be an output name or a list of them")
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("must be an output name or a list of them")
+}
+
+// ParseValue reads a value given as JSON, keeping numbers as written. Text
+// that is not JSON is taken as a string, so formats need no extra quotes.
+func ParseValue(text string) any {
+	decoder := json.NewDecoder(bytes.NewReader([]byte(text)))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil || decoder.More() {
+		return text
+	}
+	return value
+}
+
+// Check reports whether waybar can read content.
+func Check(content string) error {
+	c, err := Parse(content)
+	if err != nil {
+		return err
+	}
+	return c.Validate()
+}
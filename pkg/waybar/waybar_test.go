@@ -0,0 +1,295 @@
+package waybar
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+)
+
+const single = `// -*- mode: jsonc -*-
+{
+    "layer": "top",
+    "height": 30,
+    "modules-left": ["hyprland/workspaces"],
+    "modules-center": [],
+    // right side
+    "modules-right": [
+        "pulseaudio",
+        "clock", // time
+    ],
+    "clock": {
+        "format": "{:%H:%M}",
+    },
+}
+`
+
+const several = `[
+    {
+        "output": "DP-1",
+        "modules-left": ["clock", "tray"],
+    },
+    {
+        "output": "HDMI-A-1",
+        "modules-right": ["clock"], // clock only
+    },
+]
+`
+
+func TestRoundTrip(t *testing.T) {
+	for _, content := range []string{single, several} {
+		config, err := Parse(content)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := config.String(); got != content {
+			t.Errorf("round trip changed the config:\n%s\nwant:\n%s", got, content)
+		}
+		if err := config.Validate(); err != nil {
+			t.Errorf("Validate: %v", err)
+		}
+	}
+}
+
+func TestModules(t *testing.T) {
+	config, err := Parse(single)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		section string
+		want    []string
+	}{
+		{"modules-left", []string{"hyprland/workspaces"}},
+		{"modules-center", []string{}},
+		{"modules-right", []string{"pulseaudio", "clock"}},
+	}
+	for _, test := range tests {
+		if got, err := config.Modules(0, test.section); err != nil || !slices.Equal(got, test.want) {
+			t.Errorf("Modules(%s) = %q, %v; want %q", test.section, got, err, test.want)
+		}
+	}
+	if _, err := config.Modules(0, "modules-top"); err == nil {
+		t.Error("Modules of an unknown section succeeded")
+	}
+	if _, err := config.Modules(1, "modules-left"); err == nil {
+		t.Error("Modules of a missing bar succeeded")
+	}
+}
+
+func TestAddModule(t *testing.T) {
+	tests := []struct {
+		name     string
+		section  string
+		module   string
+		position int
+		old, new string
+	}{
+		{
+			"append to a one-line list",
+			"modules-left", "cpu", -1,
+			`["hyprland/workspaces"]`, `["hyprland/workspaces", "cpu"]`,
+		},
+		{
+			"insert at the front",
+			"modules-left", "cpu", 0,
+			`["hyprland/workspaces"]`, `["cpu", "hyprland/workspaces"]`,
+		},
+		{
+			"into an empty list",
+			"modules-center", "clock", -1,
+			`"modules-center": [],`, `"modules-center": ["clock"],`,
+		},
+		{
+			"append after a trailing comment",
+			"modules-right", "battery", -1,
+			"        \"clock\", // time\n",
+			"        \"clock\", // time\n        \"battery\",\n",
+		},
+	}
+
+	for _, test := range tests {
+		config, err := Parse(single)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := config.AddModule(0, test.section, test.module, test.position); err != nil {
+			t.Errorf("%s: %v", test.name, err)
+			continue
+		}
+		want := strings.Replace(single, test.old, test.new, 1)
+		if got := config.String(); got != want {
+			t.Errorf("%s:\n%s\nwant:\n%s", test.name, got, want)
+		}
+	}
+}
+
+func TestAddModuleMissingSection(t *testing.T) {
+	config, err := Parse(several)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := config.AddModule(1, "modules-left", "tray", 0); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := config.Modules(1, "modules-left"); !slices.Equal(got, []string{"tray"}) {
+		t.Errorf("modules-left of bar 1 = %q, want [tray]", got)
+	}
+	// The other bar is untouched.
+	if got, _ := config.Modules(0, "modules-left"); !slices.Equal(got, []string{"clock", "tray"}) {
+		t.Errorf("modules-left of bar 0 = %q", got)
+	}
+
+	if err := config.AddModule(1, "modules-center", "tray", 2); err == nil {
+		t.Error("AddModule past the end of a missing section succeeded")
+	}
+	if err := config.AddModule(0, "modules-left", "clock", -1); err == nil {
+		t.Error("AddModule of a module already in the section succeeded")
+	}
+}
+
+func TestRemoveModule(t *testing.T) {
+	config, err := Parse(several)
+	if err != nil {
+		t.Fatal(err)
+	}
+	removed, err := config.RemoveModule(1, "", "clock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(removed, []string{"modules-right"}) {
+		t.Errorf("removed from %q, want modules-right", removed)
+	}
+	want := strings.Replace(several, `"modules-right": ["clock"], // clock only`, `"modules-right": [], // clock only`, 1)
+	if got := config.String(); got != want {
+		t.Errorf("RemoveModule:\n%s\nwant:\n%s", got, want)
+	}
+
+	if _, err := config.RemoveModule(1, "", "clock"); err == nil {
+		t.Error("RemoveModule of a missing module succeeded")
+	}
+}
+
+func TestRemoveModuleMultiline(t *testing.T) {
+	config, err := Parse(single)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := config.RemoveModule(0, "modules-right", "clock"); err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Replace(single, "        \"clock\", // time\n", "", 1)
+	if got := config.String(); got != want {
+		t.Errorf("RemoveModule:\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func TestSetAndGet(t *testing.T) {
+	config, err := Parse(several)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := config.Set(0, []string{"clock", "format"}, "{:%A}"); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := config.Get(0, []string{"clock", "format"}); got != "{:%A}" {
+		t.Errorf("Get = %#v, want the new format", got)
+	}
+	if _, ok := config.Get(1, []string{"clock"}); ok {
+		t.Error("Set changed the other bar")
+	}
+	if err := config.Set(2, []string{"height"}, 30); err == nil {
+		t.Error("Set on a missing bar succeeded")
+	}
+	if err := config.Delete(1, []string{"output"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := config.Get(1, []string{"output"}); ok {
+		t.Error("Delete left the option")
+	}
+}
+
+func TestUnconfigured(t *testing.T) {
+	config, err := Parse(`{
+  "modules-left": ["custom/power", "custom/media", "clock"],
+  "modules-right": ["custom/power"],
+  "custom/media": {"exec": "playerctl metadata"},
+}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := config.Unconfigured(0); !slices.Equal(got, []string{"custom/power"}) {
+		t.Errorf("Unconfigured = %q, want [custom/power]", got)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{single, ""},
+		{several, ""},
+		{`{"height": "30"}`, "height: must be a number"},
+		{`{"position": "middle"}`, "position: must be one of"},
+		{`{"modules-left": "clock"}`, "modules-left must be a list of module names"},
+		{`[{}, {"modules-left": [1]}]`, "bar 1: modules-left must be a list of module names, item 0"},
+		{`[{}, "bar"]`, "bar 1 is not an object"},
+		{`"bar"`, "the config must be an object"},
+		{"{\n  /* unfinished", "line 2, column 3: unterminated comment"},
+		{`{"height": 30,, }`, `expected a quoted key`},
+	}
+	for _, test := range tests {
+		err := Check(test.content)
+		switch {
+		case test.want == "" && err != nil:
+			t.Errorf("Check(%q) = %v", test.content, err)
+		case test.want != "" && (err == nil || !strings.Contains(err.Error(), test.want)):
+			t.Errorf("Check(%q) = %v, want an error containing %q", test.content, err, test.want)
+		}
+	}
+}
+
+func TestParseValue(t *testing.T) {
+	tests := []struct {
+		text string
+		want any
+	}{
+		{"30", json.Number("30")},
+		{"1.50", json.Number("1.50")},
+		{"true", true},
+		{`"quoted"`, "quoted"},
+		{`["clock", "tray"]`, []any{"clock", "tray"}},
+		{"{:%H:%M}", "{:%H:%M}"},
+		{"top", "top"},
+		{"1 2", "1 2"},
+	}
+	for _, test := range tests {
+		if got := ParseValue(test.text); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("ParseValue(%q) = %#v, want %#v", test.text, got, test.want)
+		}
+	}
+}
+
+func TestFindConfig(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := FindConfig(dir); err == nil {
+		t.Error("FindConfig found a config in an empty directory")
+	}
+
+	for _, name := range []string{"config", "config.jsonc"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("{}"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	path, err := FindConfig(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := filepath.Join(dir, "config.jsonc"); path != want {
+		t.Errorf("FindConfig = %s, want %s", path, want)
+	}
+}